@@ -0,0 +1,206 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monkit
+
+import (
+	"math"
+	"time"
+
+	"github.com/spacemonkeygo/monkit/v3/monotime"
+)
+
+// Aggregate can implement additional aggregation for collected values. Every
+// call creates new state, whose observe and stat functions are called with
+// the RawVal's mutex held, so they don't need synchronization of their own.
+// Until a value is observed, stat reports 0, or NaN where 0 would be a
+// valid result.
+type Aggregate func() (observe func(val float64), stat func() (field string, val float64))
+
+// describeAggregate describes field if it is a field of the built-in
+// aggregations. help is the user supplied description of the observed
+// values, if any, and unit their unit.
+func describeAggregate(key SeriesKey, field, help, unit string,
+	cb func(key SeriesKey, field string, info StatInfo)) {
+	var info StatInfo
+	switch field {
+	case "count":
+		info = describeField(StatKindCounter, help, "", "number of observed values")
+	case "sum":
+		// the sum decreases with negative values, so it's not a counter.
+		info = describeField(StatKindGauge, help, unit, "sum of observed values")
+	case "min":
+		info = describeField(StatKindGauge, help, unit, "lowest observed value")
+	case "max":
+		info = describeField(StatKindGauge, help, unit, "highest observed value")
+	case "last":
+		info = describeField(StatKindGauge, help, unit, "most recently observed value")
+	case "mean":
+		info = describeField(StatKindGauge, help, unit, "average of observed values")
+	case "variance":
+		info = describeField(StatKindGauge, help, "", "population variance of observed values")
+	case "stddev":
+		info = describeField(StatKindGauge, help, unit, "population standard deviation of observed values")
+	case "first_seen":
+		info = describeField(StatKindGauge, help, UnitSeconds, "time of the first observed value since the Unix epoch")
+	case "rate":
+		info = describeField(StatKindGauge, help, "", "change per second between the two most recent observed values")
+	default:
+		return
+	}
+	cb(key, field, info)
+}
+
+// Count is a value aggregator that counts the number of times the value is measured.
+func Count() (observe func(val float64), stat func() (field string, val float64)) {
+	var counter int
+	return func(val float64) {
+			counter++
+		}, func() (field string, val float64) {
+			return "count", float64(counter)
+		}
+}
+
+// Sum is a value aggregator that summarizes the values measured.
+func Sum() (observe func(val float64), stat func() (field string, val float64)) {
+	var sum float64
+	return func(val float64) {
+			sum += val
+		}, func() (field string, val float64) {
+			return "sum", sum
+		}
+}
+
+// Min is a value aggregator that reports the lowest value measured.
+func Min() (observe func(val float64), stat func() (field string, val float64)) {
+	min := math.NaN()
+	return func(val float64) {
+			if math.IsNaN(min) || val < min {
+				min = val
+			}
+		}, func() (field string, val float64) {
+			return "min", min
+		}
+}
+
+// Max is a value aggregator that reports the highest value measured.
+func Max() (observe func(val float64), stat func() (field string, val float64)) {
+	max := math.NaN()
+	return func(val float64) {
+			if math.IsNaN(max) || val > max {
+				max = val
+			}
+		}, func() (field string, val float64) {
+			return "max", max
+		}
+}
+
+// Last is a value aggregator that reports the most recent value measured.
+func Last() (observe func(val float64), stat func() (field string, val float64)) {
+	last := math.NaN()
+	return func(val float64) {
+			last = val
+		}, func() (field string, val float64) {
+			return "last", last
+		}
+}
+
+// moments keeps the running mean and variance of values, using Welford's
+// algorithm so that it stays accurate for large numbers of values.
+type moments struct {
+	count int64
+	mean  float64
+	m2    float64 // sum of squared differences from the mean
+}
+
+func (m *moments) observe(val float64) {
+	m.count++
+	delta := val - m.mean
+	m.mean += delta / float64(m.count)
+	m.m2 += delta * (val - m.mean)
+}
+
+// variance returns the population variance.
+func (m *moments) variance() float64 {
+	if m.count == 0 {
+		return math.NaN()
+	}
+	return m.m2 / float64(m.count)
+}
+
+// Mean is a value aggregator that reports the average of the values measured.
+func Mean() (observe func(val float64), stat func() (field string, val float64)) {
+	var m moments
+	return m.observe, func() (field string, val float64) {
+		if m.count == 0 {
+			return "mean", math.NaN()
+		}
+		return "mean", m.mean
+	}
+}
+
+// Variance is a value aggregator that reports the population variance of the
+// values measured.
+func Variance() (observe func(val float64), stat func() (field string, val float64)) {
+	var m moments
+	return m.observe, func() (field string, val float64) {
+		return "variance", m.variance()
+	}
+}
+
+// Stddev is a value aggregator that reports the population standard deviation
+// of the values measured.
+func Stddev() (observe func(val float64), stat func() (field string, val float64)) {
+	var m moments
+	return m.observe, func() (field string, val float64) {
+		return "stddev", math.Sqrt(m.variance())
+	}
+}
+
+// FirstSeen is a value aggregator that reports when the value was first
+// measured, in seconds since the Unix epoch.
+func FirstSeen() (observe func(val float64), stat func() (field string, val float64)) {
+	var first time.Time
+	return func(val float64) {
+			if first.IsZero() {
+				first = time.Now()
+			}
+		}, func() (field string, val float64) {
+			if first.IsZero() {
+				return "first_seen", math.NaN()
+			}
+			return "first_seen", float64(first.UnixNano()) / float64(time.Second)
+		}
+}
+
+// Rate is a value aggregator that reports how fast the value changes, in
+// units per second, between the two most recent measurements.
+func Rate() (observe func(val float64), stat func() (field string, val float64)) {
+	return rate(monotime.Now)
+}
+
+func rate(now func() time.Time) (observe func(val float64), stat func() (field string, val float64)) {
+	var prev, last float64
+	var prevAt, lastAt time.Time
+	return func(val float64) {
+			prev, prevAt = last, lastAt
+			last, lastAt = val, now()
+		}, func() (field string, val float64) {
+			interval := lastAt.Sub(prevAt).Seconds()
+			if prevAt.IsZero() || interval <= 0 {
+				return "rate", math.NaN()
+			}
+			return "rate", (last - prev) / interval
+		}
+}
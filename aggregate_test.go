@@ -0,0 +1,168 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monkit
+
+import (
+	"math"
+	"sync"
+	"testing"
+	"time"
+)
+
+func rawValStats(v *RawVal) map[string]float64 {
+	values := map[string]float64{}
+	v.Stats(func(key SeriesKey, field string, val float64) {
+		values[key.WithField(field)] = val
+	})
+	return values
+}
+
+func TestRawValAggregates(t *testing.T) {
+	v := NewRawVal(NewSeriesKey("raw"),
+		Count, Sum, Min, Max, Last, Mean, Variance, Stddev, FirstSeen)
+
+	values := rawValStats(v)
+	for _, field := range []string{"min", "max", "last", "mean", "variance", "stddev", "first_seen"} {
+		if val := values["raw "+field]; !math.IsNaN(val) {
+			t.Errorf("%s before observing: got %v, expected NaN", field, val)
+		}
+	}
+
+	start := time.Now()
+	for _, val := range []float64{2, 4.5, 4, 4, 5, 5, 7, 9} {
+		v.Observe(val)
+	}
+	values = rawValStats(v)
+	for field, expected := range map[string]float64{
+		"recent":   9,
+		"count":    8,
+		"sum":      40.5,
+		"min":      2,
+		"max":      9,
+		"last":     9,
+		"mean":     5.0625,
+		"variance": 3.90234375,
+		"stddev":   math.Sqrt(3.90234375),
+	} {
+		if got := values["raw "+field]; math.Abs(got-expected) > 1e-9 {
+			t.Errorf("%s: got %v, expected %v", field, got, expected)
+		}
+	}
+	firstSeen := values["raw first_seen"]
+	if firstSeen < float64(start.Unix()) || firstSeen > float64(time.Now().Unix()+1) {
+		t.Errorf("first_seen: got %v, expected about %v", firstSeen, start.Unix())
+	}
+}
+
+func TestRawValRate(t *testing.T) {
+	now := time.Unix(1000, 0)
+	observe, stat := rate(func() time.Time { return now })
+
+	observe(10)
+	if _, val := stat(); !math.IsNaN(val) {
+		t.Fatalf("rate after one value: got %v, expected NaN", val)
+	}
+	now = now.Add(2 * time.Second)
+	observe(16)
+	if field, val := stat(); field != "rate" || val != 3 {
+		t.Fatalf("got %s %v, expected rate 3", field, val)
+	}
+}
+
+func TestRawValBuckets(t *testing.T) {
+	v := NewRawVal(NewSeriesKey("raw"))
+	v.Observe(100)
+	v.UseBuckets([]float64{1, 2})
+	for _, val := range []float64{0.5, 1.5, 1.75, 3} {
+		v.Observe(val)
+	}
+	// the same bounds keep the counts.
+	v.UseBuckets([]float64{1, 2})
+
+	values := rawValStats(v)
+	for name, expected := range map[string]float64{
+		"raw,le=1 bucket":    1,
+		"raw,le=2 bucket":    3,
+		"raw,le=+Inf bucket": 4,
+		"raw sum":            6.75,
+		"raw count":          4,
+	} {
+		if got := values[name]; got != expected {
+			t.Errorf("%s: got %v, expected %v", name, got, expected)
+		}
+	}
+
+	described := map[string]StatKind{}
+	v.DescribeStats(func(key SeriesKey, field string, info StatInfo) {
+		described[field] = info.Kind
+	})
+	if described["bucket"] != StatKindHistogram || described["recent"] != StatKindGauge {
+		t.Errorf("unexpected descriptions: %v", described)
+	}
+}
+
+func TestRawValBucketsWithAggregates(t *testing.T) {
+	v := NewRawVal(NewSeriesKey("raw"), Sum, Count)
+	v.Observe(100)
+	v.UseBuckets([]float64{1, 2})
+	v.Observe(1.5)
+
+	emitted := map[string]int{}
+	v.Stats(func(key SeriesKey, field string, val float64) {
+		emitted[key.WithField(field)]++
+	})
+	for name, times := range emitted {
+		if times != 1 {
+			t.Errorf("%s: emitted %d times", name, times)
+		}
+	}
+	// the aggregations report their own sum and count.
+	values := rawValStats(v)
+	if values["raw sum"] != 101.5 || values["raw count"] != 2 || values["raw,le=2 bucket"] != 1 {
+		t.Errorf("got %v", values)
+	}
+
+	described := map[string]int{}
+	v.DescribeStats(func(key SeriesKey, field string, info StatInfo) {
+		described[key.WithField(field)]++
+	})
+	for name, times := range described {
+		if times != 1 {
+			t.Errorf("%s: described %d times", name, times)
+		}
+	}
+}
+
+func TestRawValStatsConcurrent(t *testing.T) {
+	v := NewRawVal(NewSeriesKey("raw"), Sum, Mean, Variance)
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			v.Observe(float64(i))
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			v.Stats(func(key SeriesKey, field string, val float64) {})
+		}
+	}()
+	wg.Wait()
+	if got := rawValStats(v)["raw sum"]; got != 499500 {
+		t.Fatalf("got sum %v, expected 499500", got)
+	}
+}
@@ -0,0 +1,193 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alert
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/spacemonkeygo/monkit/v3"
+)
+
+type recorder struct {
+	alerts []Alert
+}
+
+func (r *recorder) Notify(ctx context.Context, alert Alert) error {
+	r.alerts = append(r.alerts, alert)
+	return nil
+}
+
+func (r *recorder) states() (states []string) {
+	for _, alert := range r.alerts {
+		states = append(states, alert.State.String())
+	}
+	r.alerts = nil
+	return states
+}
+
+func TestEngineStates(t *testing.T) {
+	ctx := context.Background()
+	reg := monkit.NewRegistry()
+	mon := reg.ScopeNamed("test")
+	value := 0.0
+	mon.Gauge("queue", func() float64 { return value })
+
+	notifier := &recorder{}
+	e := NewEngine(reg, notifier, Rule{
+		Name:       "long_queue",
+		Expr:       Field(Selector{Measurement: "queue"}, "value"),
+		Threshold:  10,
+		Hysteresis: 2,
+		For:        2 * time.Minute,
+	})
+
+	start := time.Unix(1000, 0)
+	step := func(minutes int, val float64) []string {
+		value = val
+		if err := e.Evaluate(ctx, start.Add(time.Duration(minutes)*time.Minute)); err != nil {
+			t.Fatal(err)
+		}
+		return notifier.states()
+	}
+
+	// a short spike is dropped while pending.
+	step(0, 20)
+	if alerts := e.Alerts(); len(alerts) != 1 || alerts[0].State != Pending {
+		t.Fatalf("got %v", alerts)
+	}
+	step(1, 5)
+	if alerts := e.Alerts(); len(alerts) != 0 {
+		t.Fatalf("got %v", alerts)
+	}
+
+	// a long one fires.
+	step(2, 20)
+	step(3, 20)
+	if states := step(4, 20); len(states) != 1 || states[0] != "firing" {
+		t.Fatalf("got %v", states)
+	}
+	// within the hysteresis, it keeps firing.
+	if states := step(5, 9); len(states) != 0 {
+		t.Fatalf("got %v", states)
+	}
+	if alerts := e.Alerts(); len(alerts) != 1 || alerts[0].State != Firing || alerts[0].Value != 9 {
+		t.Fatalf("got %v", alerts)
+	}
+	if states := step(6, 8); len(states) != 1 || states[0] != "resolved" {
+		t.Fatalf("got %v", states)
+	}
+	if alerts := e.Alerts(); len(alerts) != 0 {
+		t.Fatalf("got %v", alerts)
+	}
+}
+
+func TestEngineRatio(t *testing.T) {
+	ctx := context.Background()
+	reg := monkit.NewRegistry()
+	mon := reg.ScopeNamed("test")
+	work := mon.FuncNamed("work")
+
+	notifier := &recorder{}
+	sel := Selector{Measurement: "function", Tags: map[string]string{"name": "work"}}
+	e := NewEngine(reg, notifier, Rule{
+		Name: "failing_work",
+		Expr: Ratio(
+			Increase(Field(sel, "failures")),
+			Increase(Field(sel, "total"))),
+		Threshold: 0.01,
+	})
+
+	calls := func(successes, failures int) {
+		for i := 0; i < successes; i++ {
+			work.Observe()(nil)
+		}
+		for i := 0; i < failures; i++ {
+			err := errors.New("fail")
+			work.Observe()(&err)
+		}
+	}
+
+	now := time.Unix(1000, 0)
+	calls(10, 10)
+	_ = e.Evaluate(ctx, now)
+	calls(1000, 1)
+	_ = e.Evaluate(ctx, now.Add(time.Minute))
+	if states := notifier.states(); len(states) != 0 {
+		t.Fatalf("got %v", states)
+	}
+	calls(100, 10)
+	_ = e.Evaluate(ctx, now.Add(2*time.Minute))
+	if len(notifier.alerts) != 1 || notifier.alerts[0].Value != 10.0/110 ||
+		notifier.alerts[0].Key.Tags.Get("name") != "work" {
+		t.Fatalf("got %v", notifier.alerts)
+	}
+}
+
+func TestRunAndWebhook(t *testing.T) {
+	received := make(chan map[string]interface{}, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var body map[string]interface{}
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			t.Error(err)
+		}
+		received <- body
+	}))
+	defer server.Close()
+
+	reg := monkit.NewRegistry()
+	reg.ScopeNamed("test").Gauge("queue", func() float64 { return 100 })
+	e := NewEngine(reg, &WebhookNotifier{URL: server.URL}, Rule{
+		Name:      "long_queue",
+		Expr:      Field(Selector{Measurement: "queue"}, "value"),
+		Threshold: 10,
+	})
+
+	if Active(reg) != nil {
+		t.Fatal("unexpected alerts without running engines")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go e.Run(ctx, time.Millisecond)
+
+	body := <-received
+	if body["rule"] != "long_queue" || body["state"] != "firing" || body["value"] != 100.0 {
+		t.Fatalf("got %v", body)
+	}
+
+	var firing float64
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); {
+		if alerts := Active(reg); len(alerts) == 1 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if alerts := Active(reg); len(alerts) != 1 || alerts[0].State != Firing {
+		t.Fatalf("got %v", alerts)
+	}
+	reg.Stats(func(key monkit.SeriesKey, field string, val float64) {
+		if key.Measurement == "alerts" && field == "firing" {
+			firing = val
+		}
+	})
+	if firing != 1 {
+		t.Fatalf("got %v firing alerts", firing)
+	}
+}
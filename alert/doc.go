@@ -0,0 +1,55 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package alert evaluates alert rules against the statistics of a Registry in
+process, without an external monitoring system.
+
+A Rule compares the value of an Expr to a threshold, for every series the
+Expr yields a value for. For example, to alert when the 99th percentile of
+successful calls to a function is over 500ms for 2 minutes:
+
+	alert.Rule{
+	  Name: "slow_work",
+	  Expr: alert.Field(alert.Selector{
+	    Measurement: "function_times",
+	    Tags:        map[string]string{"name": "work", "kind": "success"},
+	  }, "r99"),
+	  Threshold: 0.5,
+	  For:       2 * time.Minute,
+	}
+
+or when more than 1% of calls fail between evaluations:
+
+	work := alert.Selector{Measurement: "function", Tags: map[string]string{"name": "work"}}
+	alert.Rule{
+	  Name: "failing_work",
+	  Expr: alert.Ratio(
+	    alert.Increase(alert.Field(work, "failures")),
+	    alert.Increase(alert.Field(work, "total"))),
+	  Threshold:  0.01,
+	  Hysteresis: 0.005,
+	}
+
+An Engine evaluates its rules with every collection. Alerts start out
+pending, fire once their condition held for the Rule's For duration, and
+resolve once the value gets back past the threshold by the Rule's
+Hysteresis. Firing and resolved alerts are passed to a Notifier.
+
+While an Engine runs, the present package lists its alerts at /alerts, and
+the number of pending and firing alerts of every rule are reported as the
+pending and firing fields of the alerts measurement in the
+"github.com/spacemonkeygo/monkit/v3/alert" Scope.
+*/
+package alert // import "github.com/spacemonkeygo/monkit/v3/alert"
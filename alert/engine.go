@@ -0,0 +1,372 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alert
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/spacemonkeygo/monkit/v3"
+)
+
+// Rule describes when to alert.
+type Rule struct {
+	// Name identifies the rule. It must be unique within an Engine.
+	Name string
+	// Description is passed on with the rule's alerts.
+	Description string
+	// Expr computes the values that are compared to Threshold. Every series
+	// it yields a value for gets its own alert.
+	Expr Expr
+	// Threshold is the value a series has to be above to alert.
+	Threshold float64
+	// Below makes series alert when they are below Threshold instead.
+	Below bool
+	// Hysteresis is how far a firing alert's value has to get back past
+	// Threshold to resolve, so that values around Threshold don't make the
+	// alert flap.
+	Hysteresis float64
+	// For is how long an alert stays pending before it fires. Pending alerts
+	// whose value gets back past Threshold are dropped without notification.
+	For time.Duration
+}
+
+// matches returns whether val should alert.
+func (r *Rule) matches(val float64) bool {
+	if r.Below {
+		return val < r.Threshold
+	}
+	return val > r.Threshold
+}
+
+// clears returns whether val resolves a firing alert.
+func (r *Rule) clears(val float64) bool {
+	if r.Below {
+		return val >= r.Threshold+r.Hysteresis
+	}
+	return val <= r.Threshold-r.Hysteresis
+}
+
+// State is the state of an Alert.
+type State int
+
+const (
+	// Pending alerts match their Rule, but not for long enough yet.
+	Pending State = iota
+	// Firing alerts matched their Rule for at least its For duration.
+	Firing
+	// Resolved alerts fired before and no longer match their Rule.
+	Resolved
+)
+
+// String returns the name of the state.
+func (s State) String() string {
+	switch s {
+	case Pending:
+		return "pending"
+	case Firing:
+		return "firing"
+	case Resolved:
+		return "resolved"
+	default:
+		return fmt.Sprintf("State(%d)", int(s))
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s State) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// Alert is a series that matched a Rule.
+type Alert struct {
+	Rule        string
+	Description string
+	Key         monkit.SeriesKey
+	State       State
+	// Value is the most recent value of the series. It is NaN if the series
+	// resolved because it disappeared.
+	Value float64
+	// ActiveAt is when the series started matching the Rule.
+	ActiveAt time.Time
+	// FiredAt and ResolvedAt are when the alert started firing and resolved,
+	// if it did.
+	FiredAt    time.Time
+	ResolvedAt time.Time
+}
+
+// Engine evaluates Rules against the statistics of a Registry. Create one
+// with NewEngine.
+type Engine struct {
+	r        *monkit.Registry
+	notifier Notifier
+	rules    []Rule
+
+	mtx    sync.Mutex
+	alerts map[string]*Alert // by rule name and series key
+}
+
+// NewEngine creates an Engine that evaluates rules against r, and passes
+// alerts that fire or resolve to notifier.
+func NewEngine(r *monkit.Registry, notifier Notifier, rules ...Rule) *Engine {
+	return &Engine{
+		r:        r,
+		notifier: notifier,
+		rules:    append([]Rule(nil), rules...),
+		alerts:   map[string]*Alert{},
+	}
+}
+
+// Run calls Evaluate every interval until ctx is done. While it runs, the
+// Engine's alerts are listed by Active. Errors of the Notifier are ignored,
+// so the Notifier should handle them itself.
+func (e *Engine) Run(ctx context.Context, interval time.Duration) {
+	defer register(e)()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			_ = e.Evaluate(ctx, now)
+		}
+	}
+}
+
+// Evaluate collects the Registry's statistics once, as of now, and updates
+// the alerts of all rules. It returns the first error of the Notifier.
+func (e *Engine) Evaluate(ctx context.Context, now time.Time) error {
+	c := Collect(e.r, now)
+
+	e.mtx.Lock()
+	var notify []Alert
+	for i := range e.rules {
+		notify = append(notify, e.evaluateRule(&e.rules[i], c)...)
+	}
+	e.mtx.Unlock()
+
+	var firstErr error
+	for _, alert := range notify {
+		if err := e.notifier.Notify(ctx, alert); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// evaluateRule updates the alerts of rule and returns the ones to notify
+// about. e.mtx must be held.
+func (e *Engine) evaluateRule(rule *Rule, c *Collection) (notify []Alert) {
+	seen := map[string]bool{}
+	for _, sample := range rule.Expr.Eval(c) {
+		name := rule.Name + " " + sample.Key.String()
+		seen[name] = true
+		alert, ok := e.alerts[name]
+		if !ok {
+			if !rule.matches(sample.Value) {
+				continue
+			}
+			alert = &Alert{
+				Rule:        rule.Name,
+				Description: rule.Description,
+				Key:         sample.Key,
+				State:       Pending,
+				ActiveAt:    c.Time,
+			}
+			e.alerts[name] = alert
+		}
+		alert.Value = sample.Value
+
+		switch alert.State {
+		case Pending:
+			if !rule.matches(sample.Value) {
+				delete(e.alerts, name)
+			} else if !c.Time.Before(alert.ActiveAt.Add(rule.For)) {
+				alert.State, alert.FiredAt = Firing, c.Time
+				notify = append(notify, *alert)
+			}
+		case Firing:
+			if rule.clears(sample.Value) {
+				notify = append(notify, e.resolve(name, c.Time))
+			}
+		}
+	}
+
+	// series without a value resolve.
+	for name, alert := range e.alerts {
+		if alert.Rule != rule.Name || seen[name] {
+			continue
+		}
+		if alert.State == Firing {
+			alert.Value = math.NaN()
+			notify = append(notify, e.resolve(name, c.Time))
+		} else {
+			delete(e.alerts, name)
+		}
+	}
+	return notify
+}
+
+func (e *Engine) resolve(name string, now time.Time) Alert {
+	alert := e.alerts[name]
+	delete(e.alerts, name)
+	alert.State, alert.ResolvedAt = Resolved, now
+	return *alert
+}
+
+// Alerts returns the pending and firing alerts, ordered by rule and series.
+func (e *Engine) Alerts() []Alert {
+	e.mtx.Lock()
+	alerts := make([]Alert, 0, len(e.alerts))
+	for _, alert := range e.alerts {
+		alerts = append(alerts, *alert)
+	}
+	e.mtx.Unlock()
+	sortAlerts(alerts)
+	return alerts
+}
+
+func sortAlerts(alerts []Alert) {
+	sort.Slice(alerts, func(i, j int) bool {
+		if alerts[i].Rule != alerts[j].Rule {
+			return alerts[i].Rule < alerts[j].Rule
+		}
+		return alerts[i].Key.String() < alerts[j].Key.String()
+	})
+}
+
+// scopeName is the Scope the statistics of running Engines are reported in.
+const scopeName = "github.com/spacemonkeygo/monkit/v3/alert"
+
+var running = struct {
+	mtx  sync.Mutex
+	sets map[*monkit.Scope]*engineSet
+}{sets: map[*monkit.Scope]*engineSet{}}
+
+// engineSet is the set of running Engines of a Registry. It reports their
+// alert counts as a StatSource.
+type engineSet struct {
+	mtx     sync.Mutex
+	engines map[*Engine]struct{}
+}
+
+// register adds e to the running Engines of its Registry. The Registry is
+// identified by its alert Scope, which is the same for all Registries that
+// only differ by their CallbackTransformers.
+func register(e *Engine) (unregister func()) {
+	scope := e.r.ScopeNamed(scopeName)
+
+	running.mtx.Lock()
+	set, ok := running.sets[scope]
+	if !ok {
+		set = &engineSet{engines: map[*Engine]struct{}{}}
+		running.sets[scope] = set
+		scope.Chain(set)
+	}
+	running.mtx.Unlock()
+
+	set.mtx.Lock()
+	set.engines[e] = struct{}{}
+	set.mtx.Unlock()
+
+	return func() {
+		set.mtx.Lock()
+		delete(set.engines, e)
+		set.mtx.Unlock()
+	}
+}
+
+func (set *engineSet) alerts() []Alert {
+	set.mtx.Lock()
+	engines := make([]*Engine, 0, len(set.engines))
+	for e := range set.engines {
+		engines = append(engines, e)
+	}
+	set.mtx.Unlock()
+
+	var alerts []Alert
+	for _, e := range engines {
+		alerts = append(alerts, e.Alerts()...)
+	}
+	sortAlerts(alerts)
+	return alerts
+}
+
+// Stats implements monkit.StatSource.
+func (set *engineSet) Stats(cb func(key monkit.SeriesKey, field string, val float64)) {
+	set.mtx.Lock()
+	engines := make([]*Engine, 0, len(set.engines))
+	for e := range set.engines {
+		engines = append(engines, e)
+	}
+	set.mtx.Unlock()
+
+	type counts struct{ pending, firing int }
+	var rules []string
+	byRule := map[string]*counts{}
+	for _, e := range engines {
+		e.mtx.Lock()
+		for _, rule := range e.rules {
+			if _, ok := byRule[rule.Name]; !ok {
+				rules = append(rules, rule.Name)
+				byRule[rule.Name] = &counts{}
+			}
+		}
+		for _, alert := range e.alerts {
+			if alert.State == Firing {
+				byRule[alert.Rule].firing++
+			} else {
+				byRule[alert.Rule].pending++
+			}
+		}
+		e.mtx.Unlock()
+	}
+
+	sort.Strings(rules)
+	for _, rule := range rules {
+		key := monkit.NewSeriesKey("alerts").WithTag("rule", rule)
+		cb(key, "pending", float64(byRule[rule].pending))
+		cb(key, "firing", float64(byRule[rule].firing))
+	}
+}
+
+// Active returns the pending and firing alerts of all Engines that are
+// running for r, ordered by rule and series.
+func Active(r *monkit.Registry) []Alert {
+	// look the Scope up without creating it for Registries without Engines.
+	var scope *monkit.Scope
+	r.Scopes(func(s *monkit.Scope) {
+		if s.Name() == scopeName {
+			scope = s
+		}
+	})
+	if scope == nil {
+		return nil
+	}
+
+	running.mtx.Lock()
+	set, ok := running.sets[scope]
+	running.mtx.Unlock()
+	if !ok {
+		return nil
+	}
+	return set.alerts()
+}
@@ -0,0 +1,186 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alert
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/spacemonkeygo/monkit/v3"
+)
+
+// Collection is the values of one collection of a StatSource.
+type Collection struct {
+	Time   time.Time
+	series map[string]*collectedSeries
+}
+
+type collectedSeries struct {
+	key    monkit.SeriesKey
+	fields map[string]float64
+}
+
+// Collect collects the current values of source.
+func Collect(source monkit.StatSource, now time.Time) *Collection {
+	c := &Collection{Time: now, series: map[string]*collectedSeries{}}
+	source.Stats(func(key monkit.SeriesKey, field string, val float64) {
+		name := key.String()
+		series, ok := c.series[name]
+		if !ok {
+			series = &collectedSeries{key: key, fields: map[string]float64{}}
+			c.series[name] = series
+		}
+		series.fields[field] = val
+	})
+	return c
+}
+
+// Lookup returns the value of the given field of a series.
+func (c *Collection) Lookup(key monkit.SeriesKey, field string) (float64, bool) {
+	series, ok := c.series[key.String()]
+	if !ok {
+		return 0, false
+	}
+	val, ok := series.fields[field]
+	return val, ok
+}
+
+// Sample is the value of an Expr for one series.
+type Sample struct {
+	Key   monkit.SeriesKey
+	Value float64
+}
+
+// Expr computes values from a Collection, at most one per series.
+type Expr interface {
+	Eval(c *Collection) []Sample
+}
+
+// Selector selects series by their measurement and tags. An empty
+// Measurement matches every measurement, and series have to have all of Tags,
+// but may have others.
+type Selector struct {
+	Measurement string
+	Tags        map[string]string
+}
+
+// Matches returns whether the Selector selects the series.
+func (s Selector) Matches(key monkit.SeriesKey) bool {
+	if s.Measurement != "" && s.Measurement != key.Measurement {
+		return false
+	}
+	all := key.Tags.All()
+	for tag, val := range s.Tags {
+		if actual, ok := all[tag]; !ok || actual != val {
+			return false
+		}
+	}
+	return true
+}
+
+// Field returns an Expr with the value of the given field of every series sel
+// selects.
+func Field(sel Selector, field string) Expr {
+	return fieldExpr{sel: sel, field: field}
+}
+
+type fieldExpr struct {
+	sel   Selector
+	field string
+}
+
+func (e fieldExpr) Eval(c *Collection) []Sample {
+	names := make([]string, 0, len(c.series))
+	for name := range c.series {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var samples []Sample
+	for _, name := range names {
+		series := c.series[name]
+		if !e.sel.Matches(series.key) {
+			continue
+		}
+		if val, ok := series.fields[e.field]; ok {
+			samples = append(samples, Sample{Key: series.key, Value: val})
+		}
+	}
+	return samples
+}
+
+// Ratio returns an Expr with the ratio of the values of num and den for the
+// series both have a value for. Series where den is 0 are left out.
+func Ratio(num, den Expr) Expr {
+	return ratioExpr{num: num, den: den}
+}
+
+type ratioExpr struct {
+	num, den Expr
+}
+
+func (e ratioExpr) Eval(c *Collection) []Sample {
+	dens := map[string]float64{}
+	for _, sample := range e.den.Eval(c) {
+		dens[sample.Key.String()] = sample.Value
+	}
+	var samples []Sample
+	for _, sample := range e.num.Eval(c) {
+		if den, ok := dens[sample.Key.String()]; ok && den != 0 {
+			samples = append(samples, Sample{Key: sample.Key, Value: sample.Value / den})
+		}
+	}
+	return samples
+}
+
+// Increase returns an Expr with how much the values of e, which should be
+// counters, increased since the previous evaluation. A value that is lower
+// than before was reset, and its increase is its new value. Series are left
+// out the first time they are seen. Increase keeps state, so every Rule needs
+// its own.
+func Increase(e Expr) Expr {
+	return &increaseExpr{e: e, prev: map[string]float64{}}
+}
+
+type increaseExpr struct {
+	e Expr
+
+	mtx  sync.Mutex
+	prev map[string]float64
+}
+
+func (e *increaseExpr) Eval(c *Collection) []Sample {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+
+	current := map[string]float64{}
+	var samples []Sample
+	for _, sample := range e.e.Eval(c) {
+		name := sample.Key.String()
+		current[name] = sample.Value
+		prev, ok := e.prev[name]
+		if !ok {
+			continue
+		}
+		increase := sample.Value - prev
+		if sample.Value < prev {
+			increase = sample.Value
+		}
+		samples = append(samples, Sample{Key: sample.Key, Value: increase})
+	}
+	e.prev = current
+	return samples
+}
@@ -0,0 +1,140 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"time"
+)
+
+// Notifier is told about alerts that start firing or resolve.
+type Notifier interface {
+	Notify(ctx context.Context, alert Alert) error
+}
+
+// NotifierFunc is a function that implements Notifier.
+type NotifierFunc func(ctx context.Context, alert Alert) error
+
+// Notify implements Notifier.
+func (f NotifierFunc) Notify(ctx context.Context, alert Alert) error {
+	return f(ctx, alert)
+}
+
+// Notifiers returns a Notifier that passes alerts to all of notifiers, and
+// returns the first error.
+func Notifiers(notifiers ...Notifier) Notifier {
+	return NotifierFunc(func(ctx context.Context, alert Alert) error {
+		var firstErr error
+		for _, n := range notifiers {
+			if err := n.Notify(ctx, alert); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+		return firstErr
+	})
+}
+
+// LogNotifier returns a Notifier that logs alerts to logger, or to the
+// standard logger if logger is nil.
+func LogNotifier(logger *log.Logger) Notifier {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return NotifierFunc(func(ctx context.Context, alert Alert) error {
+		logger.Printf("alert %s %s: %s=%g", alert.Rule, alert.State, alert.Key, alert.Value)
+		return nil
+	})
+}
+
+// WebhookNotifier posts alerts to URL as JSON objects, with the fields rule,
+// description, state, measurement, tags, value (null if not finite),
+// active_at, fired_at and, once resolved, resolved_at.
+type WebhookNotifier struct {
+	URL string
+	// Client is used for the requests, http.DefaultClient if nil.
+	Client *http.Client
+}
+
+// Notify implements Notifier.
+func (n *WebhookNotifier) Notify(ctx context.Context, alert Alert) error {
+	body, err := json.Marshal(alertJSON(alert))
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := n.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("alert: webhook %s: %s", n.URL, resp.Status)
+	}
+	return nil
+}
+
+type jsonAlert struct {
+	Rule        string            `json:"rule"`
+	Description string            `json:"description,omitempty"`
+	State       State             `json:"state"`
+	Measurement string            `json:"measurement"`
+	Tags        map[string]string `json:"tags"`
+	Value       *float64          `json:"value"`
+	ActiveAt    time.Time         `json:"active_at"`
+	FiredAt     *time.Time        `json:"fired_at,omitempty"`
+	ResolvedAt  *time.Time        `json:"resolved_at,omitempty"`
+}
+
+func alertJSON(alert Alert) jsonAlert {
+	out := jsonAlert{
+		Rule:        alert.Rule,
+		Description: alert.Description,
+		State:       alert.State,
+		Measurement: alert.Key.Measurement,
+		Tags:        alert.Key.Tags.All(),
+		ActiveAt:    alert.ActiveAt,
+	}
+	if value := alert.Value; !math.IsNaN(value) && !math.IsInf(value, 0) {
+		out.Value = &value
+	}
+	if !alert.FiredAt.IsZero() {
+		out.FiredAt = &alert.FiredAt
+	}
+	if !alert.ResolvedAt.IsZero() {
+		out.ResolvedAt = &alert.ResolvedAt
+	}
+	return out
+}
+
+// MarshalJSON implements json.Marshaler, with the same fields as the JSON
+// objects of WebhookNotifier.
+func (alert Alert) MarshalJSON() ([]byte, error) {
+	return json.Marshal(alertJSON(alert))
+}
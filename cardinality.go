@@ -0,0 +1,176 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monkit
+
+import (
+	"sort"
+	"sync/atomic"
+)
+
+// OverflowTagValue replaces all tag values of sources created once their
+// measurement is over its cardinality limit. See SetCardinalityLimit.
+const OverflowTagValue = "__overflow__"
+
+// overflowMeasurement is the measurement of the self-metric counting how
+// often sources were collapsed into overflow series.
+const overflowMeasurement = "cardinality_overflow"
+
+// SetCardinalityLimit caps the number of distinct tag combinations of every
+// measurement name in all Scopes of the Registry, unless the Scope has its
+// own limit. Funcs are limited per function name. Once a measurement has
+// limit series, retrieving a source with new tags returns a source where all
+// tag values are OverflowTagValue instead, and the "cardinality_overflow"
+// measurement of the Scope counts the distinct tag combinations that were
+// collapsed that way. The overflow series does not count towards the limit. A
+// limit of 0 disables the limit. Existing series are kept.
+func (r *Registry) SetCardinalityLimit(limit int) {
+	atomic.StoreInt64(&r.limit, int64(limit))
+}
+
+// SetCardinalityLimit is like Registry.SetCardinalityLimit, but only applies
+// to this Scope and overrides the Registry's limit. A limit of 0 uses the
+// Registry's limit again, and a negative limit disables it for the Scope.
+func (s *Scope) SetCardinalityLimit(limit int) {
+	atomic.StoreInt64(&s.limit, int64(limit))
+}
+
+func (s *Scope) cardinalityLimit() int {
+	if limit := atomic.LoadInt64(&s.limit); limit != 0 {
+		return int(limit)
+	}
+	return int(atomic.LoadInt64(&s.r.limit))
+}
+
+// SeriesCardinality is the number of series of a measurement in a Scope.
+type SeriesCardinality struct {
+	// Measurement is the measurement name, or "func:" followed by the name
+	// for Funcs.
+	Measurement string
+	// Series is the number of distinct tag combinations, not including the
+	// overflow series.
+	Series int
+	// Overflows is the number of distinct tag combinations that were
+	// collapsed into the overflow series.
+	Overflows int64
+}
+
+// Cardinality returns the current number of series for every measurement in
+// the Scope, sorted by measurement. Chained StatSources are not included, and
+// the series a GaugeMulti callback emits count as one.
+func (s *Scope) Cardinality() []SeriesCardinality {
+	s.mtx.RLock()
+	rv := make([]SeriesCardinality, 0, len(s.cardinality))
+	for series, count := range s.cardinality {
+		rv = append(rv, SeriesCardinality{
+			Measurement: series,
+			Series:      count,
+			Overflows:   s.overflows[series],
+		})
+	}
+	for series, overflows := range s.overflows {
+		if _, exists := s.cardinality[series]; !exists {
+			rv = append(rv, SeriesCardinality{Measurement: series, Overflows: overflows})
+		}
+	}
+	s.mtx.RUnlock()
+
+	sort.Slice(rv, func(i, j int) bool { return rv[i].Measurement < rv[j].Measurement })
+	return rv
+}
+
+// CardinalityLimit returns the cardinality limit of the Scope, or a value
+// <= 0 if there is none.
+func (s *Scope) CardinalityLimit() int { return s.cardinalityLimit() }
+
+// sourceSeries is the series of a source, and whether the source is the
+// overflow series.
+type sourceSeries struct {
+	series   string
+	overflow bool
+}
+
+// deleteSource removes a source and its cardinality bookkeeping. If it is an
+// overflow source, the sources that were collapsed into it are forgotten, so
+// they are looked up again. s.mtx must be held.
+func (s *Scope) deleteSource(name string) {
+	delete(s.sources, name)
+	ss, ok := s.seriesOf[name]
+	if !ok {
+		return
+	}
+	delete(s.seriesOf, name)
+	if ss.overflow {
+		for aliased, overflowName := range s.overflowed[ss.series] {
+			if overflowName == name {
+				delete(s.overflowed[ss.series], aliased)
+			}
+		}
+		return
+	}
+	s.cardinality[ss.series]--
+	if s.cardinality[ss.series] <= 0 {
+		delete(s.cardinality, ss.series)
+	}
+}
+
+// overflowedInto returns the number of sources of series that were collapsed
+// into the overflow source named overflowName.
+func (s *Scope) overflowedInto(series, overflowName string) int {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	n := 0
+	for _, name := range s.overflowed[series] {
+		if name == overflowName {
+			n++
+		}
+	}
+	return n
+}
+
+func (s *Scope) overflowStats(cb func(key SeriesKey, field string, val float64)) {
+	s.mtx.RLock()
+	overflows := make(map[string]int64, len(s.overflows))
+	for series, count := range s.overflows {
+		overflows[series] = count
+	}
+	s.mtx.RUnlock()
+
+	for series, count := range overflows {
+		cb(NewSeriesKey(overflowMeasurement).WithTag("measurement", series),
+			"total", float64(count))
+	}
+}
+
+func describeOverflow(cb func(key SeriesKey, field string, info StatInfo)) {
+	cb(NewSeriesKey(overflowMeasurement), "total", describeField(StatKindCounter, "", "",
+		"number of tag combinations collapsed into the overflow series of the measurement tag"))
+}
+
+func overflowTags(tags []SeriesTag) []SeriesTag {
+	overflowed := make([]SeriesTag, len(tags))
+	for i, tag := range tags {
+		overflowed[i] = NewSeriesTag(tag.Key, OverflowTagValue)
+	}
+	return overflowed
+}
+
+func overflowKey(key SeriesKey) SeriesKey {
+	overflowed := make(map[string]string, key.Tags.Len())
+	for name := range key.Tags.All() {
+		overflowed[name] = OverflowTagValue
+	}
+	key.Tags = key.Tags.SetAll(overflowed)
+	return key
+}
@@ -0,0 +1,106 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monkit
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCardinalityLimit(t *testing.T) {
+	r := NewRegistry()
+	mon := r.ScopeNamed("cardinality")
+	r.SetCardinalityLimit(2)
+
+	tenant := func(name string) SeriesTag { return NewSeriesTag("tenant", name) }
+	a := mon.Meter("requests", tenant("a"))
+	mon.Meter("requests", tenant("b"))
+	overflow := mon.Meter("requests", tenant("c"))
+	if mon.Meter("requests", tenant("d")) != overflow {
+		t.Fatal("expected new tenants to share the overflow series")
+	}
+	if mon.Meter("requests", tenant("d")) != overflow {
+		t.Fatal("expected overflowed tenants to keep the overflow series")
+	}
+	if mon.Meter("requests", tenant("a")) != a {
+		t.Fatal("expected existing series to be kept")
+	}
+	if overflow.key.Tags.Get("tenant") != OverflowTagValue {
+		t.Fatalf("unexpected overflow key %v", overflow.key)
+	}
+	mon.RawVal("raw", tenant("a")).Observe(1)
+	mon.Counter("untagged").Inc(1)
+
+	expected := []SeriesCardinality{
+		{Measurement: "raw", Series: 1},
+		{Measurement: "requests", Series: 2, Overflows: 2},
+		{Measurement: "untagged", Series: 1},
+	}
+	if got := mon.Cardinality(); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("got %+v, expected %+v", got, expected)
+	}
+
+	var overflows float64
+	r.Stats(func(key SeriesKey, field string, val float64) {
+		if key.Measurement == "cardinality_overflow" && key.Tags.Get("measurement") == "requests" {
+			overflows = val
+		}
+	})
+	if overflows != 2 {
+		t.Fatalf("expected 2 overflows to be reported, got %v", overflows)
+	}
+
+	// removing a series frees it up for new tenants, while the overflowed
+	// ones keep the overflow series.
+	mon.Remove("requests", tenant("b"))
+	if mon.Meter("requests", tenant("d")) != overflow {
+		t.Fatal("expected overflowed tenants to keep the overflow series after a removal")
+	}
+	if mon.Meter("requests", tenant("e")).key.Tags.Get("tenant") != "e" {
+		t.Fatal("expected removal to free up a series")
+	}
+	if got := mon.Cardinality()[1]; got.Series != 2 || got.Overflows != 2 {
+		t.Fatalf("got %+v after a removal", got)
+	}
+	mon.RemoveSource(overflow)
+	if m := mon.Meter("requests", tenant("c")); m == overflow || m.key.Tags.Get("tenant") != OverflowTagValue {
+		t.Fatal("expected a new overflow series")
+	}
+
+	for i, name := range []string{"a", "b", "c", "d"} {
+		value := float64(i)
+		mon.GaugeTagged("load", func() float64 { return value }, tenant(name))
+	}
+	mon.GaugeTagged("load", func() float64 { return 10 }, tenant("c"))
+	loads := map[string]float64{}
+	mon.Stats(func(key SeriesKey, field string, val float64) {
+		if key.Measurement == "load" {
+			loads[key.Tags.Get("tenant")] = val
+		}
+	})
+	if !reflect.DeepEqual(loads, map[string]float64{"a": 0, "b": 1, OverflowTagValue: 2}) {
+		t.Fatalf("got gauges %v", loads)
+	}
+	for _, c := range mon.Cardinality() {
+		if c.Measurement == "load" && (c.Series != 2 || c.Overflows != 2) {
+			t.Fatalf("got gauge cardinality %+v", c)
+		}
+	}
+
+	mon.SetCardinalityLimit(-1)
+	if mon.FuncNamed("f", tenant("x")) == mon.FuncNamed("f", tenant("y")) {
+		t.Fatal("expected the limit to be disabled")
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monkit
+
+import (
+	"sort"
+	"sync/atomic"
+)
+
+// SetTags adds constant tags to all series of all Scopes in the Registry,
+// such as host, service or version tags, and to all Spans started in them
+// (see Span.Tags). Tags a series already has and tags of its Scope (see
+// Scope.SetTags) take precedence, and the "scope" tag is always the name of
+// the Scope. Calling SetTags again replaces the values of tags with the same
+// keys and adds the others. Like SetTTL, it changes the shared state of the
+// Registry, so it also affects all Registries derived with WithTransformers
+// and the Registry they were derived from. For example:
+//
+//	monkit.Default.SetTags(
+//	  monkit.NewSeriesTag("host", hostname),
+//	  monkit.NewSeriesTag("service", "api"))
+func (r *Registry) SetTags(tags ...SeriesTag) {
+	addConstTags(&r.constTags, tags)
+}
+
+// Tags returns the constant tags added with SetTags, sorted by key.
+func (r *Registry) Tags() []SeriesTag {
+	return loadConstTags(&r.constTags)
+}
+
+// SetTags is like Registry.SetTags, but only adds the tags to the series and
+// Spans of this Scope, overriding tags of the Registry with the same keys.
+// Scopes are shared by everyone who gets them by name, such as with
+// monkit.Package, so the tags apply to all of their users. For example:
+//
+//	var mon = monkit.Package()
+//
+//	func init() {
+//	  mon.SetTags(monkit.NewSeriesTag("component", "db"))
+//	}
+func (s *Scope) SetTags(tags ...SeriesTag) {
+	addConstTags(&s.constTags, tags)
+}
+
+// Tags returns the constant tags of the Scope, including the ones of the
+// Registry, sorted by key. It does not include the "scope" tag.
+func (s *Scope) Tags() []SeriesTag {
+	return mergeTags(s.r.Tags(), loadConstTags(&s.constTags))
+}
+
+// Tags returns the constant tags of the Registry and Scope of the Span's Func,
+// sorted by key. See Registry.SetTags.
+func (s *Span) Tags() []SeriesTag {
+	return s.f.scope.Tags()
+}
+
+// seriesTagger adds the scope tag and the constant tags to series keys.
+type seriesTagger struct {
+	scope    string
+	defaults []SeriesTag
+}
+
+func (s *Scope) tagger() seriesTagger {
+	return seriesTagger{scope: s.name, defaults: s.Tags()}
+}
+
+// tag returns key with the scope tag, and the constant tags it does not
+// have yet.
+func (t seriesTagger) tag(key SeriesKey) SeriesKey {
+	if len(t.defaults) == 0 {
+		return key.WithTag("scope", t.scope)
+	}
+	all := key.Tags.All()
+	tags := make([]SeriesTag, 0, len(t.defaults)+1)
+	for _, tag := range t.defaults {
+		if _, exists := all[tag.Key]; !exists {
+			tags = append(tags, tag)
+		}
+	}
+	tags = append(tags, NewSeriesTag("scope", t.scope))
+	return key.WithTags(tags...)
+}
+
+func loadConstTags(p *atomic.Pointer[[]SeriesTag]) []SeriesTag {
+	if tags := p.Load(); tags != nil {
+		return append([]SeriesTag(nil), *tags...)
+	}
+	return nil
+}
+
+func addConstTags(p *atomic.Pointer[[]SeriesTag], tags []SeriesTag) {
+	for {
+		old := p.Load()
+		var current []SeriesTag
+		if old != nil {
+			current = *old
+		}
+		merged := mergeTags(current, tags)
+		if p.CompareAndSwap(old, &merged) {
+			return
+		}
+	}
+}
+
+// mergeTags returns the tags of a and b sorted by key, where tags of b
+// override tags of a with the same key. a and b are not modified.
+func mergeTags(a, b []SeriesTag) []SeriesTag {
+	if len(b) == 0 {
+		return a
+	}
+	byKey := make(map[string]string, len(a)+len(b))
+	for _, tag := range a {
+		byKey[tag.Key] = tag.Val
+	}
+	for _, tag := range b {
+		byKey[tag.Key] = tag.Val
+	}
+	merged := make([]SeriesTag, 0, len(byKey))
+	for key, val := range byKey {
+		merged = append(merged, NewSeriesTag(key, val))
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].Key < merged[j].Key })
+	return merged
+}
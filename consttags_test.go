@@ -0,0 +1,75 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monkit
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestConstTags(t *testing.T) {
+	r := NewRegistry()
+	r.SetTags(NewSeriesTag("host", "a"), NewSeriesTag("region", "eu"))
+	mon := r.ScopeNamed("test")
+	mon.SetTags(NewSeriesTag("region", "us"))
+	r.ScopeNamed("other").Counter("calls").Inc(1)
+	mon.Counter("calls", NewSeriesTag("host", "b")).Inc(1)
+	mon.Counter("calls").Inc(1)
+
+	seen := map[string]bool{}
+	r.Stats(func(key SeriesKey, field string, val float64) {
+		if key.Measurement == "calls" && field == "value" {
+			seen[key.String()] = true
+		}
+	})
+	for _, expected := range []string{
+		"calls,host=a,region=eu,scope=other",
+		"calls,host=a,region=us,scope=test",
+		"calls,host=b,region=us,scope=test",
+	} {
+		if !seen[expected] {
+			t.Fatalf("missing %q in %v", expected, seen)
+		}
+	}
+
+	// descriptions get the same tags, so they match exactly.
+	info, ok := NewStatCatalog(r).exact["calls,host=a,region=us,scope=test value"]
+	if !ok || info.Kind != StatKindGauge {
+		t.Fatalf("got %v, %v for the tagged description", info, ok)
+	}
+
+	// later calls replace and add tags.
+	r.SetTags(NewSeriesTag("host", "c"), NewSeriesTag("version", "1"))
+	expected := []SeriesTag{
+		NewSeriesTag("host", "c"), NewSeriesTag("region", "us"), NewSeriesTag("version", "1"),
+	}
+	if tags := mon.Tags(); !reflect.DeepEqual(tags, expected) {
+		t.Fatalf("got scope tags %v, expected %v", tags, expected)
+	}
+
+	// derived Registries share their tags.
+	r.WithTransformers().SetTags(NewSeriesTag("version", "2"))
+	if tags := r.Tags(); len(tags) != 3 || tags[2] != NewSeriesTag("version", "2") {
+		t.Fatalf("got registry tags %v", tags)
+	}
+	expected[2] = NewSeriesTag("version", "2")
+
+	ctx := context.Background()
+	defer mon.Task()(&ctx)(nil)
+	if tags := SpanFromCtx(ctx).Tags(); !reflect.DeepEqual(tags, expected) {
+		t.Fatalf("got span tags %v, expected %v", tags, expected)
+	}
+}
@@ -16,7 +16,7 @@ package monkit
 
 import (
 	"math"
-	"sync"
+	"sync/atomic"
 )
 
 // Counter keeps track of running totals, along with the highest and lowest
@@ -31,45 +31,50 @@ import (
 //	  mon.Counter("beans").Inc(1)
 //	}
 type Counter struct {
-	mtx            sync.Mutex
+	// sync/atomic things. low and high are math.MaxInt64 and math.MinInt64
+	// until the first value is set.
 	val, low, high int64
-	nonempty       bool
-	key            SeriesKey
+	updates        adder
+
+	key SeriesKey
+	description
 }
 
 // NewCounter constructs a counter
 func NewCounter(key SeriesKey) *Counter {
-	return &Counter{key: key}
+	return &Counter{key: key, low: math.MaxInt64, high: math.MinInt64}
 }
 
-func (c *Counter) set(val int64) {
-	c.val = val
-	if !c.nonempty || val < c.low {
-		c.low = val
+// observe updates the high and low values with val.
+func (c *Counter) observe(val int64) {
+	for {
+		low := atomic.LoadInt64(&c.low)
+		if val >= low || atomic.CompareAndSwapInt64(&c.low, low, val) {
+			break
+		}
 	}
-	if !c.nonempty || c.high < val {
-		c.high = val
+	for {
+		high := atomic.LoadInt64(&c.high)
+		if val <= high || atomic.CompareAndSwapInt64(&c.high, high, val) {
+			break
+		}
 	}
-	c.nonempty = true
+	c.updates.Add(1)
 }
 
 // Set will immediately change the value of the counter to whatever val is. It
 // will appropriately update the high and low values, and return the former
 // value.
 func (c *Counter) Set(val int64) (former int64) {
-	c.mtx.Lock()
-	former = c.val
-	c.set(val)
-	c.mtx.Unlock()
+	former = atomic.SwapInt64(&c.val, val)
+	c.observe(val)
 	return former
 }
 
 // Inc will atomically increment the counter by delta and return the new value.
 func (c *Counter) Inc(delta int64) (current int64) {
-	c.mtx.Lock()
-	c.set(c.val + delta)
-	current = c.val
-	c.mtx.Unlock()
+	current = atomic.AddInt64(&c.val, delta)
+	c.observe(current)
 	return current
 }
 
@@ -78,45 +83,49 @@ func (c *Counter) Dec(delta int64) (current int64) {
 	return c.Inc(-delta)
 }
 
+// bounds returns the low and high values, and whether any value was set.
+func (c *Counter) bounds() (low, high int64, nonempty bool) {
+	low, high = atomic.LoadInt64(&c.low), atomic.LoadInt64(&c.high)
+	if low > high {
+		return 0, 0, false
+	}
+	return low, high, true
+}
+
 // High returns the highest value seen since construction or the last reset
 func (c *Counter) High() (h int64) {
-	c.mtx.Lock()
-	h = c.high
-	c.mtx.Unlock()
+	_, h, _ = c.bounds()
 	return h
 }
 
 // Low returns the lowest value seen since construction or the last reset
 func (c *Counter) Low() (l int64) {
-	c.mtx.Lock()
-	l = c.low
-	c.mtx.Unlock()
+	l, _, _ = c.bounds()
 	return l
 }
 
 // Current returns the current value
 func (c *Counter) Current() (cur int64) {
-	c.mtx.Lock()
-	cur = c.val
-	c.mtx.Unlock()
-	return cur
+	return atomic.LoadInt64(&c.val)
 }
 
 // Reset resets all values including high/low counters and returns what they
-// were.
+// were. Updates concurrent with Reset may be applied before or after it.
 func (c *Counter) Reset() (val, low, high int64) {
-	c.mtx.Lock()
-	val, low, high = c.val, c.low, c.high
-	c.val, c.low, c.high, c.nonempty = 0, 0, 0, false
-	c.mtx.Unlock()
+	val = atomic.SwapInt64(&c.val, 0)
+	low = atomic.SwapInt64(&c.low, math.MaxInt64)
+	high = atomic.SwapInt64(&c.high, math.MinInt64)
+	c.updates.Add(1)
+	if low > high {
+		low, high = 0, 0
+	}
 	return val, low, high
 }
 
 // Stats implements the StatSource interface
 func (c *Counter) Stats(cb func(key SeriesKey, field string, val float64)) {
-	c.mtx.Lock()
-	val, low, high, nonempty := c.val, c.low, c.high, c.nonempty
-	c.mtx.Unlock()
+	val := c.Current()
+	low, high, nonempty := c.bounds()
 	if nonempty {
 		cb(c.key, "high", float64(high))
 		cb(c.key, "low", float64(low))
@@ -126,3 +135,13 @@ func (c *Counter) Stats(cb func(key SeriesKey, field string, val float64)) {
 	}
 	cb(c.key, "value", float64(val))
 }
+
+// DescribeStats implements the StatDescriber interface.
+func (c *Counter) DescribeStats(cb func(key SeriesKey, field string, info StatInfo)) {
+	help, unit := c.get()
+	cb(c.key, "high", describeField(StatKindGauge, help, unit,
+		"highest value since construction or the last reset"))
+	cb(c.key, "low", describeField(StatKindGauge, help, unit,
+		"lowest value since construction or the last reset"))
+	cb(c.key, "value", describeField(StatKindGauge, help, unit, "current value"))
+}
@@ -120,7 +120,7 @@ func newSpan(ctx context.Context, f *Func, args []interface{}, trace *Trace,
 		if errptr != nil {
 			err = *errptr
 		}
-		s.f.end(err, panicked, finish.Sub(s.start))
+		s.f.end(err, panicked, finish.Sub(s.start), finish, s)
 
 		var children []*Span
 		s.mtx.Lock()
@@ -0,0 +1,137 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monkit
+
+import (
+	"sync"
+	"time"
+
+	"github.com/spacemonkeygo/monkit/v3/monotime"
+)
+
+// CumulativeDeltaOptions configures a CumulativeDeltaTransformer.
+type CumulativeDeltaOptions struct {
+	// Rates makes the transformer emit per-second rates, computed from the
+	// time between collections, instead of deltas.
+	Rates bool
+	// ExpireAfter is the number of collections a series can be missing from
+	// before its state is dropped. If it is 0, state is dropped as soon as
+	// a collection does not contain the series.
+	ExpireAfter int
+}
+
+// CumulativeDeltaTransformer is a CallbackTransformer that turns every
+// monotonic field into the change since the previous collection, while other
+// fields are passed through unchanged. Whether a field is monotonic is
+// decided by the StatKind its StatSource describes: counter fields, such as
+// the totals, successes, failures, panics and error counts of Funcs, the
+// counts of distributions and the sums of durations, and the buckets and
+// counts of histograms are.
+//
+// Every call to Transform is one collection. A field that is lower than in
+// the previous collection was reset, for example by FuncStats.Reset, and its
+// delta is its new value. A field that was not in the previous collection
+// starts out at 0, so its first delta is its whole value.
+//
+// Like DeltaTransformer, a CumulativeDeltaTransformer keeps state per series,
+// so every output needs its own.
+type CumulativeDeltaTransformer struct {
+	source StatSource
+	opts   CumulativeDeltaOptions
+	now    func() time.Time
+
+	mtx        sync.Mutex
+	collection int
+	last       time.Time // time of the previous collection
+	state      map[string]*deltaState
+}
+
+type deltaState struct {
+	value      float64
+	at         time.Time
+	collection int
+}
+
+// NewCumulativeDeltaTransformer creates a CumulativeDeltaTransformer for the
+// values of source, whose StatDescriber implementation provides the kinds of
+// the fields. Usually, source is the Registry the transformer is applied to,
+// as in:
+//
+//	reg := monkit.Default
+//	deltas := reg.WithTransformers(monkit.NewCumulativeDeltaTransformer(reg,
+//	  monkit.CumulativeDeltaOptions{}))
+//
+// The series keys passed to the transformer have to match the ones source
+// describes, so it should be applied before any transformer that changes
+// them.
+func NewCumulativeDeltaTransformer(source StatSource,
+	opts CumulativeDeltaOptions) *CumulativeDeltaTransformer {
+	return &CumulativeDeltaTransformer{
+		source: source,
+		opts:   opts,
+		now:    monotime.Now,
+		state:  map[string]*deltaState{},
+	}
+}
+
+// Transform implements CallbackTransformer.
+func (dt *CumulativeDeltaTransformer) Transform(
+	cb func(SeriesKey, string, float64)) func(SeriesKey, string, float64) {
+	catalog := NewStatCatalog(dt.source)
+	now := dt.now()
+
+	dt.mtx.Lock()
+	dt.collection++
+	collection, last := dt.collection, dt.last
+	dt.last = now
+	for name, state := range dt.state {
+		if collection-state.collection > dt.opts.ExpireAfter+1 {
+			delete(dt.state, name)
+		}
+	}
+	dt.mtx.Unlock()
+
+	return func(key SeriesKey, field string, val float64) {
+		info, _ := catalog.Lookup(key, field)
+		if info.Kind != StatKindCounter && info.Kind != StatKindHistogram {
+			cb(key, field, val)
+			return
+		}
+
+		name := key.WithField(field)
+		dt.mtx.Lock()
+		state, found := dt.state[name]
+		if !found {
+			state = &deltaState{at: last}
+			dt.state[name] = state
+		}
+		delta := val - state.value
+		if val < state.value {
+			delta = val
+		}
+		since := state.at
+		state.value, state.at, state.collection = val, now, collection
+		dt.mtx.Unlock()
+
+		if !dt.opts.Rates {
+			cb(key, field, delta)
+			return
+		}
+		// without an earlier collection, there's no interval for a rate.
+		if interval := now.Sub(since).Seconds(); !since.IsZero() && interval > 0 {
+			cb(key, field, delta/interval)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monkit
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func collectDeltas(r *Registry, dt *CumulativeDeltaTransformer) map[string]float64 {
+	values := map[string]float64{}
+	r.WithTransformers(dt).Stats(func(key SeriesKey, field string, val float64) {
+		values[key.WithField(field)] = val
+	})
+	return values
+}
+
+func TestCumulativeDeltaTransformer(t *testing.T) {
+	r := NewRegistry()
+	mon := r.ScopeNamed("test")
+	f := mon.FuncNamed("work")
+	dt := NewCumulativeDeltaTransformer(r, CumulativeDeltaOptions{})
+
+	f.Observe()(nil)
+	f.Observe()(nil)
+	values := collectDeltas(r, dt)
+	if got := values["function,name=work,scope=test successes"]; got != 2 {
+		t.Fatalf("got %v successes, expected 2", got)
+	}
+
+	err := errors.New("oops")
+	f.Observe()(&err)
+	f.Observe()(nil)
+	values = collectDeltas(r, dt)
+	for name, expected := range map[string]float64{
+		"function,name=work,scope=test successes":                       1,
+		"function,name=work,scope=test failures":                        1,
+		"function,name=work,scope=test total":                           2,
+		"function,error_name=System\\ Error,name=work,scope=test count": 1,
+		"function_times,kind=success,name=work,scope=test count":        1,
+		"function,name=work,scope=test highwater":                       1, // a gauge
+	} {
+		if got, ok := values[name]; !ok || got != expected {
+			t.Fatalf("got %v (%v) for %s, expected %v in %v", got, ok, name, expected, values)
+		}
+	}
+
+	// after a reset, the new value is the delta instead of a negative one.
+	f.Reset()
+	f.Observe()(nil)
+	values = collectDeltas(r, dt)
+	if got := values["function,name=work,scope=test successes"]; got != 1 {
+		t.Fatalf("got %v successes after reset, expected 1", got)
+	}
+}
+
+func TestCumulativeDeltaTransformerExpiry(t *testing.T) {
+	r := NewRegistry()
+	mon := r.ScopeNamed("test")
+	dt := NewCumulativeDeltaTransformer(r, CumulativeDeltaOptions{ExpireAfter: 1})
+
+	mon.Meter("hits").Mark(5)
+	collectDeltas(r, dt)
+	mon.Remove("hits")
+	collectDeltas(r, dt)
+	if len(dt.state) == 0 {
+		t.Fatal("state expired too early")
+	}
+	collectDeltas(r, dt)
+
+	// missing state is only dropped when the next collection starts.
+	collectDeltas(r, dt)
+	if len(dt.state) != 0 {
+		t.Fatalf("got state %v, expected it to be expired", dt.state)
+	}
+}
+
+func TestCumulativeDeltaTransformerRates(t *testing.T) {
+	r := NewRegistry()
+	mon := r.ScopeNamed("test")
+	dt := NewCumulativeDeltaTransformer(r, CumulativeDeltaOptions{Rates: true})
+	now := time.Unix(1000, 0)
+	dt.now = func() time.Time { return now }
+
+	mon.Meter("hits").Mark(5)
+	values := collectDeltas(r, dt)
+	if _, ok := values["hits,scope=test total"]; ok {
+		t.Fatal("got a rate without an interval")
+	}
+
+	now = now.Add(2 * time.Second)
+	mon.Meter("hits").Mark(10)
+	values = collectDeltas(r, dt)
+	if got := values["hits,scope=test total"]; got != 5 {
+		t.Fatalf("got rate %v, expected 5", got)
+	}
+}
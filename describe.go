@@ -0,0 +1,162 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monkit
+
+import (
+	"sync/atomic"
+)
+
+// StatKind describes how the value of a field behaves over time.
+type StatKind int
+
+const (
+	// StatKindUnknown is used for fields nothing is known about.
+	StatKindUnknown StatKind = iota
+	// StatKindGauge is used for point-in-time values that can go up and down.
+	StatKindGauge
+	// StatKindCounter is used for monotonically increasing totals. Counters
+	// only decrease when they are reset.
+	StatKindCounter
+	// StatKindQuantile is used for estimates of a quantile of a
+	// distribution. The quantile is stored in StatInfo.Quantile.
+	StatKindQuantile
+	// StatKindHistogram is used for the fields of a Histogram: the cumulative
+	// "bucket" counts with their "le" tag, and the "sum" and "count" of all
+	// observed values. All of them only decrease when they are reset.
+	StatKindHistogram
+)
+
+// String returns a lowercase name for the kind.
+func (k StatKind) String() string {
+	switch k {
+	case StatKindGauge:
+		return "gauge"
+	case StatKindCounter:
+		return "counter"
+	case StatKindQuantile:
+		return "quantile"
+	case StatKindHistogram:
+		return "histogram"
+	}
+	return "unknown"
+}
+
+// Units commonly used in StatInfo.
+const (
+	UnitSeconds = "seconds"
+	UnitBytes   = "bytes"
+)
+
+// StatInfo describes a field emitted by a StatSource.
+type StatInfo struct {
+	Kind StatKind
+	// Unit is the unit of the field's value, such as UnitSeconds, or empty
+	// if the value is a plain number.
+	Unit string
+	// Help is a short human readable description of the field.
+	Help string
+	// Quantile is the quantile estimated by StatKindQuantile fields,
+	// where 0 <= Quantile <= 1.
+	Quantile float64
+}
+
+// StatDescriber is an optional interface a StatSource can implement to
+// describe the fields it emits through Stats. DescribeStats should call cb
+// once for every series key and field that Stats would emit. Fields whose
+// series keys depend on observed data (such as the error_name tag on
+// FuncStats error counts) may be described once without those tags.
+type StatDescriber interface {
+	DescribeStats(cb func(key SeriesKey, field string, info StatInfo))
+}
+
+// DescribeStatSource calls DescribeStats on s if s implements StatDescriber,
+// and otherwise does nothing.
+func DescribeStatSource(s StatSource,
+	cb func(key SeriesKey, field string, info StatInfo)) {
+	if d, ok := s.(StatDescriber); ok {
+		d.DescribeStats(cb)
+	}
+}
+
+// StatCatalog is a lookup table of the StatInfo a StatDescriber describes.
+type StatCatalog struct {
+	exact   map[string]StatInfo
+	byField map[string]StatInfo
+}
+
+// NewStatCatalog collects the descriptions from s into a StatCatalog. If s
+// does not implement StatDescriber, the catalog is empty.
+func NewStatCatalog(s StatSource) *StatCatalog {
+	c := &StatCatalog{
+		exact:   map[string]StatInfo{},
+		byField: map[string]StatInfo{},
+	}
+	DescribeStatSource(s, func(key SeriesKey, field string, info StatInfo) {
+		c.exact[key.WithField(field)] = info
+		if _, exists := c.byField[key.Measurement+" "+field]; !exists {
+			c.byField[key.Measurement+" "+field] = info
+		}
+	})
+	return c
+}
+
+// Lookup returns the description of the given series key and field. If the
+// exact series was not described, Lookup falls back to the first description
+// with the same measurement and field.
+func (c *StatCatalog) Lookup(key SeriesKey, field string) (info StatInfo, ok bool) {
+	if info, ok := c.exact[key.WithField(field)]; ok {
+		return info, true
+	}
+	info, ok = c.byField[key.Measurement+" "+field]
+	return info, ok
+}
+
+// description keeps user-provided help text and unit for a StatSource. It is
+// embedded by the StatSources that support Describe.
+type description struct {
+	value atomic.Value // descriptionValue
+}
+
+type descriptionValue struct {
+	help, unit string
+}
+
+// Describe sets the help text and the unit reported for the fields of this
+// StatSource through DescribeStats. An empty unit means the values are plain
+// numbers. For distributions of durations the unit is always UnitSeconds and
+// the unit argument is ignored.
+func (d *description) Describe(help, unit string) {
+	d.value.Store(descriptionValue{help: help, unit: unit})
+}
+
+func (d *description) get() (help, unit string) {
+	v, _ := d.value.Load().(descriptionValue)
+	return v.help, v.unit
+}
+
+// describeField returns a StatInfo with the given kind, where help is
+// prefixed by the user supplied help text, if any.
+func describeField(kind StatKind, userHelp, unit, help string) StatInfo {
+	if userHelp != "" {
+		help = userHelp + ": " + help
+	}
+	return StatInfo{Kind: kind, Unit: unit, Help: help}
+}
+
+func describeQuantile(userHelp, unit, help string, quantile float64) StatInfo {
+	info := describeField(StatKindQuantile, userHelp, unit, help)
+	info.Quantile = quantile
+	return info
+}
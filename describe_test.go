@@ -0,0 +1,77 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monkit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDescribeStats(t *testing.T) {
+	r := NewRegistry()
+	mon := r.ScopeNamed("describe")
+
+	m1, m2 := mon.Meter("m1"), mon.Meter("m2")
+	m1.Mark(1)
+	mon.DiffMeter("diff", m1, m2)
+	mon.Counter("counter").Inc(3)
+	mon.IntVal("int").Observe(4)
+	mon.FloatVal("float").Observe(4.5)
+	mon.DurationVal("duration").Observe(time.Second)
+	mon.BoolVal("bool").Observe(true)
+	mon.Timer("timer").Start().Stop()
+	mon.Gauge("gauge", func() float64 { return 1 })
+	mon.RawValk(NewSeriesKey("raw"), Count, Sum, Min, Max, Last, Mean, Variance, Stddev, FirstSeen, Rate).Observe(1)
+	mon.Histogram("histogram", LinearBuckets(1, 1, 3)).Observe(2)
+	func() (err error) {
+		ctx := context.Background()
+		defer mon.Task()(&ctx)(&err)
+		return errors.New("fail")
+	}()
+
+	catalog := NewStatCatalog(r)
+	r.Stats(func(key SeriesKey, field string, val float64) {
+		info, ok := catalog.Lookup(key, field)
+		if !ok || info.Kind == StatKindUnknown || info.Help == "" {
+			t.Errorf("%s is not described: %+v", key.WithField(field), info)
+		}
+	})
+
+	mon.DurationVal("duration").Describe("request latency", "")
+	info, _ := catalog.Lookup(NewSeriesKey("duration").WithTag("scope", "describe"), "r99")
+	if info.Kind != StatKindQuantile || info.Quantile != .99 || info.Unit != UnitSeconds {
+		t.Fatalf("unexpected r99 description: %+v", info)
+	}
+	info, _ = NewStatCatalog(r).Lookup(NewSeriesKey("duration").WithTag("scope", "describe"), "count")
+	if info.Kind != StatKindCounter || info.Help != "request latency: number of observed values" {
+		t.Fatalf("unexpected count description: %+v", info)
+	}
+
+	// only sums of durations can't decrease.
+	for measurement, kind := range map[string]StatKind{
+		"duration":  StatKindCounter,
+		"int":       StatKindGauge,
+		"float":     StatKindGauge,
+		"histogram": StatKindGauge,
+		"raw":       StatKindGauge,
+	} {
+		info, _ = catalog.Lookup(NewSeriesKey(measurement).WithTag("scope", "describe"), "sum")
+		if info.Kind != kind {
+			t.Errorf("unexpected %s sum description: %+v", measurement, info)
+		}
+	}
+}
@@ -15,6 +15,7 @@
 package monkit
 
 import (
+	"math"
 	"time"
 )
 
@@ -48,6 +49,59 @@ func (p float32Slice) Less(i, j int) bool {
 //go:generate sh -c "m4 -D_IMPORT_= -D_NAME_=Int -D_LOWER_NAME_=int -D_TYPE_=int64 distgen.go.m4 > intdist.go"
 //go:generate gofmt -w -s durdist.go floatdist.go intdist.go
 
+// mergeReservoirs merges the samples of reservoir b, taken from countB values,
+// into reservoir a, taken from countA values. If there are more samples than
+// fit, every slot of a is taken from a or b with a probability proportional to
+// the number of values the reservoir stands for, capped at Window.
+func mergeReservoirs(a *[ReservoirSize]float32, countA int64,
+	b *[ReservoirSize]float32, countB int64, rng *xorshift128) {
+	sizeA, sizeB := reservoirLen(countA), reservoirLen(countB)
+	if sizeA+sizeB <= ReservoirSize {
+		copy(a[sizeA:], b[:sizeB])
+		return
+	}
+
+	weightA, weightB := countA, countB
+	if Window > 0 {
+		if weightA > Window {
+			weightA = Window
+		}
+		if weightB > Window {
+			weightB = Window
+		}
+	}
+
+	// draw from shuffled copies, so every sample is used at most once.
+	samplesA, samplesB := *a, *b
+	shuffleSamples(samplesA[:sizeA], rng)
+	shuffleSamples(samplesB[:sizeB], rng)
+	nextA, nextB := 0, 0
+	for i := range a {
+		useA := int64(rng.Uint64()%uint64(weightA+weightB)) < weightA
+		if (useA && nextA < sizeA) || nextB >= sizeB {
+			a[i] = samplesA[nextA]
+			nextA++
+		} else {
+			a[i] = samplesB[nextB]
+			nextB++
+		}
+	}
+}
+
+func reservoirLen(count int64) int {
+	if count > ReservoirSize {
+		return ReservoirSize
+	}
+	return int(count)
+}
+
+func shuffleSamples(samples []float32, rng *xorshift128) {
+	for i := len(samples) - 1; i > 0; i-- {
+		j := int(rng.Uint64() % uint64(i+1))
+		samples[i], samples[j] = samples[j], samples[i]
+	}
+}
+
 func (d *DurationDist) toFloat64(v time.Duration) float64 {
 	return v.Seconds()
 }
@@ -59,3 +113,49 @@ func (d *IntDist) toFloat64(v int64) float64 {
 func (d *FloatDist) toFloat64(v float64) float64 {
 	return v
 }
+
+func (d *DurationDist) fromFloat64(v float64) time.Duration {
+	return time.Duration(v * float64(time.Second))
+}
+
+func (d *IntDist) fromFloat64(v float64) int64 {
+	return int64(math.Round(v))
+}
+
+func (d *FloatDist) fromFloat64(v float64) float64 {
+	return v
+}
+
+func (d *DurationDist) unit() string { return UnitSeconds }
+
+func (d *IntDist) unit() string { return "" }
+
+func (d *FloatDist) unit() string { return "" }
+
+// sumKind is the kind of the sum field. Only durations can't be negative, so
+// the sums of other distributions can decrease.
+func (d *DurationDist) sumKind() StatKind { return StatKindCounter }
+
+func (d *IntDist) sumKind() StatKind { return StatKindGauge }
+
+func (d *FloatDist) sumKind() StatKind { return StatKindGauge }
+
+// describeDist describes the fields emitted by the Stats method of the
+// distribution types. help is the user supplied description, if any, and
+// sumKind the kind of the sum field, which is only a counter if the observed
+// values can't be negative.
+func describeDist(key SeriesKey, help, unit string, sumKind StatKind,
+	cb func(key SeriesKey, field string, info StatInfo)) {
+	cb(key, "count", describeField(StatKindCounter, help, "", "number of observed values"))
+	cb(key, "sum", describeField(sumKind, help, unit, "sum of observed values"))
+	cb(key, "min", describeField(StatKindGauge, help, unit, "lowest observed value"))
+	cb(key, "max", describeField(StatKindGauge, help, unit, "highest observed value"))
+	cb(key, "rmin", describeQuantile(help, unit, "lowest value in the sample reservoir", 0))
+	cb(key, "ravg", describeField(StatKindGauge, help, unit, "average of the sample reservoir"))
+	cb(key, "r10", describeQuantile(help, unit, "10th percentile estimate", .1))
+	cb(key, "r50", describeQuantile(help, unit, "median estimate", .5))
+	cb(key, "r90", describeQuantile(help, unit, "90th percentile estimate", .9))
+	cb(key, "r99", describeQuantile(help, unit, "99th percentile estimate", .99))
+	cb(key, "rmax", describeQuantile(help, unit, "highest value in the sample reservoir", 1))
+	cb(key, "recent", describeField(StatKindGauge, help, unit, "most recently observed value"))
+}
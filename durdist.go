@@ -19,6 +19,7 @@
 package monkit
 
 import (
+	"context"
 	"sort"
 	"time"
 )
@@ -46,6 +47,8 @@ type DurationDist struct {
 	reservoir [ReservoirSize]float32
 	rng       xorshift128
 	sorted    bool
+	sketch    *Sketch
+	exemplars *exemplarSet
 }
 
 func initDurationDist(v *DurationDist, key SeriesKey) {
@@ -76,6 +79,10 @@ func (d *DurationDist) Insert(val time.Duration) {
 	d.Recent = val
 	d.Sum += val
 
+	if d.sketch != nil {
+		d.sketch.Insert(d.toFloat64(val))
+	}
+
 	index := d.Count
 	d.Count += 1
 
@@ -97,6 +104,31 @@ func (d *DurationDist) Insert(val time.Duration) {
 	}
 }
 
+// InsertCtx is like Insert, but if ctx has a Span, also keeps the value as an
+// exemplar of the Span. See Exemplars.
+func (d *DurationDist) InsertCtx(ctx context.Context, val time.Duration) {
+	d.insertSpan(val, spanFromContext(ctx))
+}
+
+func (d *DurationDist) insertSpan(val time.Duration, span *Span) {
+	d.Insert(val)
+	if span != nil {
+		if d.exemplars == nil {
+			d.exemplars = new(exemplarSet)
+		}
+		d.exemplars.observe(d.toFloat64(val), span)
+	}
+}
+
+// Exemplars calls cb with the exemplars of the values inserted with
+// InsertCtx, if there are any, as exemplars of the "count" field. It
+// implements the ExemplarSource interface.
+func (d *DurationDist) Exemplars(cb func(key SeriesKey, field string, exemplars []Exemplar)) {
+	if exemplars := d.exemplars.list(); len(exemplars) > 0 {
+		cb(d.key, "count", exemplars)
+	}
+}
+
 // FullAverage calculates and returns the average of all inserted values.
 func (d *DurationDist) FullAverage() time.Duration {
 	if d.Count > 0 {
@@ -122,8 +154,13 @@ func (d *DurationDist) ReservoirAverage() time.Duration {
 }
 
 // Query will return the approximate value at the given quantile from the
-// reservoir, where 0 <= quantile <= 1.
+// reservoir, where 0 <= quantile <= 1. If the distribution uses a Sketch, the
+// value is estimated from the Sketch instead.
 func (d *DurationDist) Query(quantile float64) time.Duration {
+	if d.sketch != nil {
+		return d.fromFloat64(d.sketch.Query(quantile))
+	}
+
 	rlen := int(ReservoirSize)
 	if int64(rlen) > d.Count {
 		rlen = int(d.Count)
@@ -158,12 +195,71 @@ func (d *DurationDist) Query(quantile float64) time.Duration {
 func (d *DurationDist) Copy() *DurationDist {
 	cp := *d
 	cp.rng = newXORShift128()
+	cp.sketch = d.sketch.Copy()
+	cp.exemplars = d.exemplars.copy()
 	return &cp
 }
 
 func (d *DurationDist) Reset() {
 	d.Low, d.High, d.Recent, d.Count, d.Sum = 0, 0, 0, 0, 0
 	// resetting count will reset the quantile reservoir
+	if d.sketch != nil {
+		d.sketch.Reset()
+	}
+	d.exemplars = nil
+}
+
+// merge adds the values observed by other to d, as if they were inserted
+// into d after its own values.
+func (d *DurationDist) merge(other *DurationDist) {
+	if other.Count == 0 {
+		return
+	}
+	if d.Count == 0 || other.Low < d.Low {
+		d.Low = other.Low
+	}
+	if d.Count == 0 || other.High > d.High {
+		d.High = other.High
+	}
+	d.Recent = other.Recent
+	d.Sum += other.Sum
+	if d.sketch != nil && other.sketch != nil {
+		_ = d.sketch.Merge(other.sketch)
+	}
+	if other.exemplars != nil {
+		if d.exemplars == nil {
+			d.exemplars = new(exemplarSet)
+		}
+		d.exemplars.merge(other.exemplars)
+	}
+	mergeReservoirs(&d.reservoir, d.Count, &other.reservoir, other.Count, &d.rng)
+	d.Count += other.Count
+	d.sorted = false
+}
+
+// emptyCopy returns an empty distribution with the same key and settings.
+func (d *DurationDist) emptyCopy() *DurationDist {
+	cp := d.Copy()
+	cp.Reset()
+	return cp
+}
+
+func (d *DurationDist) add(other windowDist) { d.merge(other.(*DurationDist)) }
+
+func (d *DurationDist) clone() windowDist { return d.Copy() }
+
+// UseSketch makes the distribution estimate quantiles with a Sketch of the
+// given relative accuracy instead of the sample reservoir. Only values
+// inserted after the call are part of the Sketch.
+func (d *DurationDist) UseSketch(relativeAccuracy float64) {
+	d.sketch = NewSketch(relativeAccuracy)
+}
+
+// Sketch returns a copy of the Sketch the distribution estimates quantiles
+// with, or nil if UseSketch was never called. The Sketch's values are in the
+// units reported by Stats, so seconds for durations.
+func (d *DurationDist) Sketch() *Sketch {
+	return d.sketch.Copy()
 }
 
 func (d *DurationDist) Stats(cb func(key SeriesKey, field string, val float64)) {
@@ -183,3 +279,8 @@ func (d *DurationDist) Stats(cb func(key SeriesKey, field string, val float64))
 		cb(d.key, "recent", d.toFloat64(d.Recent))
 	}
 }
+
+// DescribeStats implements the StatDescriber interface.
+func (d *DurationDist) DescribeStats(cb func(key SeriesKey, field string, info StatInfo)) {
+	describeDist(d.key, "", d.unit(), d.sumKind(), cb)
+}
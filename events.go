@@ -0,0 +1,102 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monkit
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// EventObserver is told about the raw events of Meters and DurationVals, for
+// forwarding them to systems that aggregate by themselves, such as StatsD.
+// Its methods are called synchronously by Mark and Observe, so they should be
+// fast.
+type EventObserver interface {
+	// ObserveMark is called for every Mark of a Meter.
+	ObserveMark(key SeriesKey, amount int64)
+	// ObserveDuration is called for every value observed by a DurationVal.
+	ObserveDuration(key SeriesKey, val time.Duration)
+}
+
+// eventHook passes the events of the Meters and DurationVals created through
+// the Scopes of a Registry to the Registry's EventObservers.
+type eventHook struct {
+	// sync/atomic things
+	observers atomic.Pointer[[]*EventObserver] // pointers tell equal observers apart
+
+	mtx sync.Mutex
+}
+
+func (h *eventHook) mark(key SeriesKey, amount int64) {
+	if h == nil {
+		return
+	}
+	if observers := h.observers.Load(); observers != nil {
+		for _, obs := range *observers {
+			(*obs).ObserveMark(key, amount)
+		}
+	}
+}
+
+func (h *eventHook) duration(key SeriesKey, val time.Duration) {
+	if h == nil {
+		return
+	}
+	if observers := h.observers.Load(); observers != nil {
+		for _, obs := range *observers {
+			(*obs).ObserveDuration(key, val)
+		}
+	}
+}
+
+// ObserveEvents makes the Meters and DurationVals created through the
+// Registry's Scopes pass their raw events to obs, in addition to aggregating
+// them, until the returned cancel method is called. This applies to existing
+// Meters and DurationVals too, but not to ones created with NewMeter or
+// NewDurationVal.
+func (r *Registry) ObserveEvents(obs EventObserver) (cancel func()) {
+	h := &r.events
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+
+	entry := &obs
+	var observers []*EventObserver
+	if old := h.observers.Load(); old != nil {
+		observers = append(observers, *old...)
+	}
+	observers = append(observers, entry)
+	h.observers.Store(&observers)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			h.mtx.Lock()
+			defer h.mtx.Unlock()
+
+			var observers []*EventObserver
+			for _, o := range *h.observers.Load() {
+				if o != entry {
+					observers = append(observers, o)
+				}
+			}
+			if len(observers) == 0 {
+				h.observers.Store(nil)
+			} else {
+				h.observers.Store(&observers)
+			}
+		})
+	}
+}
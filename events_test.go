@@ -0,0 +1,61 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monkit
+
+import (
+	"testing"
+	"time"
+)
+
+type eventRecorder struct {
+	marks     map[string]int64
+	durations []time.Duration
+}
+
+func (r *eventRecorder) ObserveMark(key SeriesKey, amount int64) {
+	r.marks[key.String()] += amount
+}
+
+func (r *eventRecorder) ObserveDuration(key SeriesKey, val time.Duration) {
+	r.durations = append(r.durations, val)
+}
+
+func TestObserveEvents(t *testing.T) {
+	r := NewRegistry()
+	mon := r.ScopeNamed("test")
+	meter := mon.Meter("requests", NewSeriesTag("kind", "get"))
+	meter.Mark(1)
+
+	rec := &eventRecorder{marks: map[string]int64{}}
+	cancel := r.ObserveEvents(rec)
+	meter.Mark(2)
+	mon.Event("requests", NewSeriesTag("kind", "get"))
+	mon.DurationVal("latency").Observe(time.Second)
+	NewMeter(NewSeriesKey("standalone")).Mark(1)
+
+	cancel()
+	meter.Mark(4)
+	mon.DurationVal("latency").Observe(time.Minute)
+
+	if len(rec.marks) != 1 || rec.marks["requests,kind=get"] != 3 {
+		t.Fatalf("got marks %v", rec.marks)
+	}
+	if len(rec.durations) != 1 || rec.durations[0] != time.Second {
+		t.Fatalf("got durations %v", rec.durations)
+	}
+	if total := meter.Total(); total != 8 {
+		t.Fatalf("got total %v", total)
+	}
+}
@@ -54,7 +54,7 @@ func ComputeThing(ctx context.Context, arg1, arg2 int) (res int, err error) {
 	mon.RawValk(monkit.NewSeriesKey("rawk").WithTag("foo", "bar"), monkit.Sum, monkit.Count).Observe(1.0)
 	mon.BoolVal("was-4").Observe(res == 4)
 	mon.IntVal("res").Observe(int64(res))
-	mon.DurationVal("took").Observe(time.Second + time.Duration(rand.Intn(int(10*time.Second))))
+	mon.DurationVal("took").Observe(time.Second + time.Duration(rand.Int63n(int64(10*time.Second))))
 	mon.Counter("calls").Inc(1)
 	mon.Gauge("arg1", func() float64 { return float64(arg1) })
 	mon.Meter("arg2").Mark(arg2)
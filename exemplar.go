@@ -0,0 +1,155 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monkit
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// MaxExemplars is the number of exemplars a distribution keeps: the most
+// recent ones, along with the one with the highest value.
+const MaxExemplars = 8
+
+// Exemplar is an observed value along with the Span it was observed in, so
+// that a statistic can be traced back to a trace that contributed to it.
+type Exemplar struct {
+	// Value is the observed value, in the units reported by Stats, so
+	// seconds for durations.
+	Value float64
+	// Time is when the value was observed.
+	Time time.Time
+	// TraceId and SpanId identify the Span the value was observed in.
+	TraceId int64
+	SpanId  int64
+}
+
+// ExemplarSource is implemented by StatSources that keep exemplars of the
+// values behind their statistics.
+type ExemplarSource interface {
+	// Exemplars calls cb with the exemplars of every series and field that
+	// has any, ordered by time. The fields match the fields of the
+	// StatSource.
+	Exemplars(cb func(key SeriesKey, field string, exemplars []Exemplar))
+}
+
+// Exemplars calls cb with the exemplars of all sources in the Scope that
+// implement ExemplarSource. See ExemplarSource.
+func (s *Scope) Exemplars(cb func(key SeriesKey, field string, exemplars []Exemplar)) {
+	tagger := s.tagger()
+	cbWithScope := func(key SeriesKey, field string, exemplars []Exemplar) {
+		cb(tagger.tag(key), field, exemplars)
+	}
+
+	for _, namedSource := range s.allNamedSources() {
+		if source, ok := namedSource.source.(ExemplarSource); ok {
+			source.Exemplars(cbWithScope)
+		}
+	}
+
+	s.mtx.Lock()
+	chains := append([]StatSource(nil), s.chains...)
+	s.mtx.Unlock()
+
+	for _, chain := range chains {
+		if source, ok := chain.(ExemplarSource); ok {
+			source.Exemplars(cbWithScope)
+		}
+	}
+}
+
+// Exemplars calls cb with the exemplars of all Scopes in the Registry. The
+// Registry's CallbackTransformers are not applied to the keys.
+func (r *Registry) Exemplars(cb func(key SeriesKey, field string, exemplars []Exemplar)) {
+	r.Scopes(func(s *Scope) { s.Exemplars(cb) })
+}
+
+var _ ExemplarSource = (*Scope)(nil)
+var _ ExemplarSource = (*Registry)(nil)
+
+// spanFromContext is like SpanFromCtx, but allows a nil ctx.
+func spanFromContext(ctx context.Context) *Span {
+	if ctx == nil {
+		return nil
+	}
+	return SpanFromCtx(ctx)
+}
+
+// exemplarSet keeps the MaxExemplars-1 most recent exemplars and the one with
+// the highest value. The nil value is an empty set for reading. Not
+// threadsafe.
+type exemplarSet struct {
+	recent [MaxExemplars - 1]Exemplar
+	next   int // the slot of recent to use next
+	used   int // the number of used slots of recent
+	max    Exemplar
+	hasMax bool
+}
+
+func (e *exemplarSet) add(ex Exemplar) {
+	if e.hasMax && ex.Value < e.max.Value {
+		e.addRecent(ex)
+		return
+	}
+	if e.hasMax {
+		e.addRecent(e.max)
+	}
+	e.max, e.hasMax = ex, true
+}
+
+func (e *exemplarSet) addRecent(ex Exemplar) {
+	e.recent[e.next] = ex
+	e.next = (e.next + 1) % len(e.recent)
+	if e.used < len(e.recent) {
+		e.used++
+	}
+}
+
+func (e *exemplarSet) observe(val float64, span *Span) {
+	e.add(Exemplar{
+		Value:   val,
+		Time:    time.Now(),
+		TraceId: span.Trace().Id(),
+		SpanId:  span.Id(),
+	})
+}
+
+// merge adds the exemplars of other to e.
+func (e *exemplarSet) merge(other *exemplarSet) {
+	for _, ex := range other.list() {
+		e.add(ex)
+	}
+}
+
+func (e *exemplarSet) copy() *exemplarSet {
+	if e == nil {
+		return nil
+	}
+	cp := *e
+	return &cp
+}
+
+// list returns the exemplars ordered by time.
+func (e *exemplarSet) list() []Exemplar {
+	if e == nil || !e.hasMax {
+		return nil
+	}
+	rv := make([]Exemplar, 0, e.used+1)
+	rv = append(rv, e.recent[:e.used]...)
+	rv = append(rv, e.max)
+	sort.SliceStable(rv, func(i, j int) bool { return rv[i].Time.Before(rv[j].Time) })
+	return rv
+}
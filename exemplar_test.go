@@ -0,0 +1,99 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monkit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestExemplarSet(t *testing.T) {
+	var set exemplarSet
+	start := time.Now()
+	set.add(Exemplar{Value: 100, Time: start})
+	for i := 0; i < 2*MaxExemplars; i++ {
+		set.add(Exemplar{Value: float64(i), Time: start.Add(time.Duration(i+1) * time.Second)})
+	}
+
+	list := set.list()
+	if len(list) != MaxExemplars {
+		t.Fatalf("got %d exemplars, expected %d", len(list), MaxExemplars)
+	}
+	// the highest value is kept, along with the most recent ones.
+	if list[0].Value != 100 {
+		t.Fatalf("got %v first, expected the highest value", list[0])
+	}
+	for i, ex := range list[1:] {
+		if expected := float64(MaxExemplars + 1 + i); ex.Value != expected {
+			t.Fatalf("got %v at %d, expected value %v", ex, i+1, expected)
+		}
+	}
+}
+
+func TestIntValExemplars(t *testing.T) {
+	r := NewRegistry()
+	mon := r.ScopeNamed("test")
+	v := mon.IntVal("size")
+
+	v.ObserveCtx(context.Background(), 1)
+	var exemplars []Exemplar
+	mon.Exemplars(func(key SeriesKey, field string, list []Exemplar) {
+		exemplars = append(exemplars, list...)
+	})
+	if len(exemplars) != 0 {
+		t.Fatalf("got exemplars %v without a span", exemplars)
+	}
+
+	ctx := context.Background()
+	func() {
+		defer mon.Task()(&ctx)(nil)
+		v.ObserveCtx(ctx, 5)
+	}()
+	span := SpanFromCtx(ctx)
+
+	// the Task keeps exemplars of the function_times distributions, too.
+	r.Exemplars(func(key SeriesKey, field string, list []Exemplar) {
+		if key.Measurement == "size" && field == "count" && key.Tags.Get("scope") == "test" {
+			exemplars = append(exemplars, list...)
+		}
+	})
+	if len(exemplars) != 1 || exemplars[0].Value != 5 ||
+		exemplars[0].TraceId != span.Trace().Id() || exemplars[0].SpanId != span.Id() {
+		t.Fatalf("got exemplars %+v", exemplars)
+	}
+}
+
+func TestFuncStatsExemplars(t *testing.T) {
+	mon := NewRegistry().ScopeNamed("test")
+	f := mon.Func()
+	f.UseHistogram([]float64{10})
+
+	ctx := context.Background()
+	func() { defer f.Task(&ctx)(nil) }()
+	f.Observe()(nil)
+
+	fields := map[string]int{}
+	f.Exemplars(func(key SeriesKey, field string, exemplars []Exemplar) {
+		if key.Tags.Get("kind") != "success" {
+			t.Fatalf("got exemplars for %v", key)
+		}
+		fields[field+key.Tags.Get("le")] += len(exemplars)
+	})
+	// the call without a span has no exemplar.
+	if len(fields) != 2 || fields["count"] != 1 || fields["bucket10"] != 1 {
+		t.Fatalf("got exemplar counts %v", fields)
+	}
+}
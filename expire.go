@@ -0,0 +1,199 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monkit
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// activitySource is implemented by the StatSources a Scope can expire.
+// activity returns a value that changes whenever the source is updated.
+type activitySource interface {
+	activity() int64
+}
+
+type sourceActivity struct {
+	source  StatSource
+	updates int64
+	at      time.Time
+}
+
+// SetTTL makes all Scopes of the Registry remove their Funcs, Meters,
+// Counters, Timers, Histograms, IntGauges, FloatGauges and value types
+// (IntVal, RawVal, etc.) once they have not been updated for at least ttl,
+// unless the Scope has its own TTL. Callback gauges and chained StatSources
+// are never removed. A ttl of 0 disables expiration.
+//
+// Expiration is checked when Stats is called or new sources are created. Only
+// updates count as activity: retrieving a source again, like calling
+// mon.Meter("name") without marking it, does not keep it from expiring. Once
+// removed, a source is detached from the Scope. References to it that are
+// held elsewhere, such as in a package-level variable, keep working, but their
+// updates are never reported again, and retrieving the source from the Scope
+// returns a new one. So with a TTL, sources should be retrieved from the Scope
+// on every use, like mon.Meter("name").Mark(1) or mon.Task(), instead of being
+// kept around.
+func (r *Registry) SetTTL(ttl time.Duration) {
+	atomic.StoreInt64(&r.ttl, int64(ttl))
+}
+
+// SetTTL is like Registry.SetTTL, but only applies to this Scope and overrides
+// the Registry's TTL. A ttl of 0 uses the Registry's TTL again, and a negative
+// ttl disables expiration for the Scope. The same caveats about held
+// references to expired sources apply.
+func (s *Scope) SetTTL(ttl time.Duration) {
+	atomic.StoreInt64(&s.ttl, int64(ttl))
+}
+
+func (s *Scope) effectiveTTL() time.Duration {
+	if ttl := atomic.LoadInt64(&s.ttl); ttl != 0 {
+		return time.Duration(ttl)
+	}
+	return time.Duration(atomic.LoadInt64(&s.r.ttl))
+}
+
+// expire removes the sources that were not updated within the TTL. To keep it
+// cheap, it only does work every quarter of the TTL.
+func (s *Scope) expire(now time.Time) {
+	ttl := s.effectiveTTL()
+	if ttl <= 0 {
+		return
+	}
+
+	s.expireMtx.Lock()
+	defer s.expireMtx.Unlock()
+	if !s.lastExpire.IsZero() && now.Sub(s.lastExpire) < ttl/4 {
+		return
+	}
+	s.lastExpire = now
+	if s.activity == nil {
+		s.activity = map[string]sourceActivity{}
+	}
+
+	sources := s.allNamedSources()
+	current := make(map[string]struct{}, len(sources))
+	var expired []namedSource
+	for _, named := range sources {
+		current[named.name] = struct{}{}
+		source, ok := named.source.(activitySource)
+		if !ok {
+			continue
+		}
+		updates := source.activity()
+		last, ok := s.activity[named.name]
+		if !ok || last.source != named.source || last.updates != updates || busy(named.source) {
+			s.activity[named.name] = sourceActivity{
+				source:  named.source,
+				updates: updates,
+				at:      now,
+			}
+			continue
+		}
+		if now.Sub(last.at) >= ttl {
+			expired = append(expired, named)
+		}
+	}
+	for name := range s.activity {
+		if _, ok := current[name]; !ok {
+			delete(s.activity, name)
+		}
+	}
+
+	for _, named := range expired {
+		s.mtx.Lock()
+		if s.sources[named.name] == named.source {
+			s.deleteSource(named.name)
+		}
+		s.mtx.Unlock()
+		delete(s.activity, named.name)
+		releaseSource(named.source)
+	}
+}
+
+// busy returns true if source is a Func with calls in progress.
+func busy(source StatSource) bool {
+	f, ok := source.(*Func)
+	return ok && f.Current() > 0
+}
+
+// releaseSource frees any resources held by a source removed from a Scope.
+func releaseSource(source StatSource) {
+	if m, ok := source.(*Meter); ok {
+		m.ticker.unregister(m)
+	}
+}
+
+func (e *Meter) activity() int64 {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	e.flush()
+	updates := e.total
+	for _, slice := range e.slices {
+		updates += slice.count
+	}
+	return updates
+}
+
+func (c *Counter) activity() int64 {
+	return c.updates.Sum()
+}
+
+func (v *IntVal) activity() int64 {
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+	return v.dist.Count
+}
+
+func (v *FloatVal) activity() int64 {
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+	return v.dist.Count
+}
+
+func (v *DurationVal) activity() int64 {
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+	return v.dist.Count
+}
+
+func (v *BoolVal) activity() int64 {
+	return atomic.LoadInt64(&v.trues) + atomic.LoadInt64(&v.falses)
+}
+
+func (v *RawVal) activity() int64 {
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+	return v.updates
+}
+
+func (t *Timer) activity() int64 {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	return t.times.Count
+}
+
+func (h *Histogram) activity() int64 {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	return h.counts.count
+}
+
+func (f *FuncStats) activity() int64 {
+	f.parentsAndMutex.Lock()
+	defer f.parentsAndMutex.Unlock()
+	f.collect()
+	return f.successTimes.Count + f.failureTimes.Count
+}
@@ -0,0 +1,143 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monkit
+
+import (
+	"testing"
+	"time"
+)
+
+func countSources(s *Scope) int {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	return len(s.sources)
+}
+
+func TestScopeRemove(t *testing.T) {
+	mon := NewRegistry().ScopeNamed("remove")
+	tag := NewSeriesTag("tenant", "a")
+	m := mon.Meter("requests", tag)
+	mon.FuncNamed("requests", tag)
+	mon.Gauge("gauge", func() float64 { return 1 })
+	chained := NewCounter(NewSeriesKey("chained"))
+	mon.Chain(chained)
+
+	if !mon.Remove("requests", tag) {
+		t.Fatal("expected requests to be removed")
+	}
+	if mon.Remove("requests", tag) {
+		t.Fatal("expected requests to be gone")
+	}
+	m.ticker.mtx.Lock()
+	_, ticking := m.ticker.meters[m]
+	m.ticker.mtx.Unlock()
+	if ticking {
+		t.Fatal("expected removed meter to be unregistered from the ticker")
+	}
+	if mon.Meter("requests", tag) == m {
+		t.Fatal("expected a new meter")
+	}
+
+	if !mon.Remove("gauge") {
+		t.Fatal("expected gauge to be removed")
+	}
+	if !mon.RemoveSource(chained) || len(mon.chains) != 0 {
+		t.Fatal("expected chained source to be removed")
+	}
+	if !mon.RemoveSource(mon.IntVal("val")) || mon.RemoveSource(StatSourceFunc(nil)) {
+		t.Fatal("unexpected RemoveSource result")
+	}
+	if got := countSources(mon); got != 1 {
+		t.Fatalf("expected only the new meter to be left, got %d sources", got)
+	}
+}
+
+func TestScopeTTL(t *testing.T) {
+	r := NewRegistry()
+	mon := r.ScopeNamed("ttl")
+	r.SetTTL(time.Minute)
+
+	idle := mon.Counter("idle")
+	active := mon.Meter("active")
+	running := mon.FuncNamed("running")
+	mon.Gauge("gauge", func() float64 { return 1 })
+	stop := running.Observe()
+	defer stop(nil)
+
+	start := time.Now()
+	mon.expire(start)
+	active.Mark(1)
+	mon.expire(start.Add(30 * time.Second))
+	mon.expire(start.Add(61 * time.Second))
+
+	mon.mtx.RLock()
+	_, idleExists := mon.sources[sourceName("", "idle", nil)]
+	_, activeExists := mon.sources[sourceName("", "active", nil)]
+	mon.mtx.RUnlock()
+	if idleExists || !activeExists {
+		t.Fatalf("unexpected sources after expiry: idle %v, active %v", idleExists, activeExists)
+	}
+	if got := countSources(mon); got != 3 {
+		t.Fatalf("expected active, running and gauge to be left, got %d sources", got)
+	}
+	if mon.Counter("idle") == idle {
+		t.Fatal("expected a new counter")
+	}
+
+	mon.SetTTL(-1)
+	mon.expire(start.Add(time.Hour))
+	if got := countSources(mon); got != 4 {
+		t.Fatalf("expected expiry to be disabled, got %d sources", got)
+	}
+}
+
+func TestScopeTTLHeldReference(t *testing.T) {
+	r := NewRegistry()
+	mon := r.ScopeNamed("ttl")
+	r.SetTTL(time.Minute)
+
+	held := mon.Counter("held")
+	start := time.Now()
+	mon.expire(start)
+	if mon.Counter("held") != held {
+		t.Fatal("expected the same counter before expiry")
+	}
+	mon.expire(start.Add(61 * time.Second))
+
+	held.Inc(1)
+	if held.Current() != 1 {
+		t.Fatalf("expected the held counter to keep counting, got %d", held.Current())
+	}
+	current := func() (value float64, found bool) {
+		mon.Stats(func(key SeriesKey, field string, val float64) {
+			if key.Measurement == "held" && field == "value" {
+				value, found = val, true
+			}
+		})
+		return value, found
+	}
+	if _, found := current(); found {
+		t.Fatal("expected the expired counter not to be reported")
+	}
+
+	fresh := mon.Counter("held")
+	if fresh == held {
+		t.Fatal("expected a new counter after expiry")
+	}
+	fresh.Inc(5)
+	if value, found := current(); !found || value != 5 {
+		t.Fatalf("expected only the new counter to be reported, got %v, %v", value, found)
+	}
+}
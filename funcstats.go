@@ -15,6 +15,7 @@
 package monkit
 
 import (
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -35,16 +36,45 @@ type FuncStats struct {
 	// sync/atomic things
 	current         int64
 	highwater       int64
+	recording       int32
 	parentsAndMutex funcSet
+	shards          atomic.Pointer[[]funcShard]
+	slo             atomic.Pointer[sloTracker] // see UseSLO
 
 	// mutex things (reuses mutex from parents)
+	funcRecord
+	window *funcRecord // see drainWindow
+	key    SeriesKey
+}
+
+// funcRecord is what FuncStats records about finished calls. Not threadsafe.
+type funcRecord struct {
 	errors       map[string]int64
 	panics       int64
 	successTimes DurationDist
 	failureTimes DurationDist
-	key          SeriesKey
+	successHist  *bucketCounts
+	failureHist  *bucketCounts
+	// successAt and failureAt are the Unix nanoseconds the Recent values of
+	// successTimes and failureTimes finished at, to keep the latest ones when
+	// merging.
+	successAt int64
+	failureAt int64
+}
+
+// funcShard records calls that finish while another call is being recorded
+// into the FuncStats, so that concurrent calls don't wait on each other. The
+// shards are merged into the FuncStats whenever it is read.
+type funcShard struct {
+	mtx  sync.Mutex
+	used bool
+	funcRecord
 }
 
+// maxFuncShards bounds the number of shards per FuncStats, since every shard
+// carries its own distributions.
+const maxFuncShards = 16
+
 func initFuncStats(f *FuncStats, key SeriesKey) {
 	f.key = key
 	f.errors = map[string]int64{}
@@ -66,10 +96,42 @@ func (f *FuncStats) Reset() {
 	atomic.StoreInt64(&f.current, 0)
 	atomic.StoreInt64(&f.highwater, 0)
 	f.parentsAndMutex.Lock()
-	f.errors = make(map[string]int64, len(f.errors))
-	f.panics = 0
-	f.successTimes.Reset()
-	f.failureTimes.Reset()
+	f.collect()
+	f.funcRecord.reset()
+	f.parentsAndMutex.Unlock()
+	if t := f.slo.Load(); t != nil {
+		t.reset()
+	}
+}
+
+// UseSketch makes the success and failure time distributions estimate
+// quantiles with Sketches of the given relative accuracy instead of the sample
+// reservoirs. Previously observed durations are not part of the Sketches.
+func (f *FuncStats) UseSketch(relativeAccuracy float64) {
+	f.parentsAndMutex.Lock()
+	f.reconfigure(func() {
+		f.successTimes.UseSketch(relativeAccuracy)
+		f.failureTimes.UseSketch(relativeAccuracy)
+	})
+	f.parentsAndMutex.Unlock()
+}
+
+// UseHistogram makes the FuncStats additionally keep Histogram bucket counts
+// of success and failure times in seconds, with the given bucket upper bounds.
+// They are emitted with the "function_times_histogram" measurement and the
+// same tags as the success and failure time distributions. Previously
+// observed durations are not counted.
+func (f *FuncStats) UseHistogram(buckets []float64) {
+	key := f.key
+	key.Measurement += "_times_histogram"
+	var success, failure bucketCounts
+	initBucketCounts(&success, key.WithTag("kind", "success"), buckets)
+	initBucketCounts(&failure, key.WithTag("kind", "failure"), buckets)
+
+	f.parentsAndMutex.Lock()
+	f.reconfigure(func() {
+		f.successHist, f.failureHist = &success, &failure
+	})
 	f.parentsAndMutex.Unlock()
 }
 
@@ -85,23 +147,200 @@ func (f *FuncStats) start(parent *Func) {
 	}
 }
 
-func (f *FuncStats) end(err error, panicked bool, duration time.Duration) {
+// end records a call that took duration and finished at finish. span is the
+// Span of the call, if any, and makes the duration an exemplar of it.
+func (f *FuncStats) end(err error, panicked bool, duration time.Duration, finish time.Time, span *Span) {
 	atomic.AddInt64(&f.current, -1)
+	if t := f.slo.Load(); t != nil {
+		t.record(err, panicked, duration)
+	}
+
+	// the first call to finish records directly, calls finishing at the same
+	// time record into a shard.
+	if atomic.CompareAndSwapInt32(&f.recording, 0, 1) {
+		f.parentsAndMutex.Lock()
+		f.funcRecord.record(err, panicked, duration, finish, span)
+		if f.window != nil {
+			f.window.record(err, panicked, duration, finish, nil)
+		}
+		f.parentsAndMutex.Unlock()
+		atomic.StoreInt32(&f.recording, 0)
+		return
+	}
+
+	shards := f.shards.Load()
+	if shards == nil {
+		shards = f.growShards()
+	}
+	shard := &(*shards)[stripe(len(*shards))]
+	shard.mtx.Lock()
+	shard.record(err, panicked, duration, finish, span)
+	shard.used = true
+	shard.mtx.Unlock()
+}
+
+func (f *FuncStats) growShards() *[]funcShard {
 	f.parentsAndMutex.Lock()
+	defer f.parentsAndMutex.Unlock()
+	if shards := f.shards.Load(); shards != nil {
+		return shards
+	}
+	n := stripeCount
+	if n > maxFuncShards {
+		n = maxFuncShards
+	}
+	shards := make([]funcShard, n)
+	for i := range shards {
+		shards[i].funcRecord = f.funcRecord.empty()
+	}
+	f.shards.Store(&shards)
+	return &shards
+}
+
+// reconfigure changes the distribution or histogram settings of the
+// FuncStats with apply, and makes the shards use the same settings. All
+// shards are locked meanwhile, so no call is recorded with the old settings
+// and lost. f.parentsAndMutex must be held.
+func (f *FuncStats) reconfigure(apply func()) {
+	shards := f.shards.Load()
+	if shards == nil {
+		apply()
+		return
+	}
+	for i := range *shards {
+		shard := &(*shards)[i]
+		shard.mtx.Lock()
+		f.collectShard(shard)
+	}
+	apply()
+	for i := range *shards {
+		shard := &(*shards)[i]
+		shard.funcRecord = f.funcRecord.empty()
+		shard.mtx.Unlock()
+	}
+}
+
+// collect merges the calls recorded in the shards into the FuncStats.
+// f.parentsAndMutex must be held.
+func (f *FuncStats) collect() {
+	shards := f.shards.Load()
+	if shards == nil {
+		return
+	}
+	for i := range *shards {
+		shard := &(*shards)[i]
+		shard.mtx.Lock()
+		f.collectShard(shard)
+		shard.mtx.Unlock()
+	}
+}
+
+// collectShard merges the calls recorded in a shard into the FuncStats. Both
+// f.parentsAndMutex and shard.mtx must be held.
+func (f *FuncStats) collectShard(shard *funcShard) {
+	if shard.used {
+		f.funcRecord.merge(&shard.funcRecord)
+		if f.window != nil {
+			f.window.merge(&shard.funcRecord)
+		}
+		shard.reset()
+		shard.used = false
+	}
+}
+
+func (r *funcRecord) record(err error, panicked bool, duration time.Duration, finish time.Time, span *Span) {
 	if panicked {
-		f.panics += 1
-		f.failureTimes.Insert(duration)
-		f.parentsAndMutex.Unlock()
+		r.panics += 1
+		r.failureTimes.insertSpan(duration, span)
+		r.failureAt = finish.UnixNano()
+		if r.failureHist != nil {
+			r.failureHist.insert(duration.Seconds())
+		}
 		return
 	}
 	if err == nil {
-		f.successTimes.Insert(duration)
-		f.parentsAndMutex.Unlock()
+		r.successTimes.insertSpan(duration, span)
+		r.successAt = finish.UnixNano()
+		if r.successHist != nil {
+			r.successHist.insert(duration.Seconds())
+		}
 		return
 	}
-	f.failureTimes.Insert(duration)
-	f.errors[getErrorName(err)] += 1
-	f.parentsAndMutex.Unlock()
+	r.failureTimes.insertSpan(duration, span)
+	r.failureAt = finish.UnixNano()
+	if r.failureHist != nil {
+		r.failureHist.insert(duration.Seconds())
+	}
+	r.errors[getErrorName(err)] += 1
+}
+
+func (r *funcRecord) merge(other *funcRecord) {
+	for errname, count := range other.errors {
+		r.errors[errname] += count
+	}
+	r.panics += other.panics
+	mergeRecentDist(&r.successTimes, &r.successAt, &other.successTimes, other.successAt)
+	mergeRecentDist(&r.failureTimes, &r.failureAt, &other.failureTimes, other.failureAt)
+	if r.successHist != nil && other.successHist != nil {
+		r.successHist.merge(other.successHist)
+		r.failureHist.merge(other.failureHist)
+	}
+}
+
+// mergeRecentDist merges other into d, where at and otherAt are when their
+// Recent values finished. Unlike DurationDist.merge, it keeps the Recent value
+// that finished last, as a shard can be merged after newer calls were
+// recorded into d.
+func mergeRecentDist(d *DurationDist, at *int64, other *DurationDist, otherAt int64) {
+	recent := d.Recent
+	d.merge(other)
+	if other.Count > 0 && otherAt < *at {
+		d.Recent = recent
+	} else if otherAt > *at {
+		*at = otherAt
+	}
+}
+
+func (r *funcRecord) reset() {
+	r.errors = make(map[string]int64, len(r.errors))
+	r.panics = 0
+	r.successAt, r.failureAt = 0, 0
+	r.successTimes.Reset()
+	r.failureTimes.Reset()
+	if r.successHist != nil {
+		r.successHist.reset()
+		r.failureHist.reset()
+	}
+}
+
+// empty returns a funcRecord with the same settings as r, but nothing
+// recorded.
+func (r *funcRecord) empty() funcRecord {
+	cp := funcRecord{
+		errors:       map[string]int64{},
+		successTimes: *r.successTimes.Copy(),
+		failureTimes: *r.failureTimes.Copy(),
+	}
+	if r.successHist != nil {
+		cp.successHist, cp.failureHist = r.successHist.copy(), r.failureHist.copy()
+	}
+	cp.reset()
+	return cp
+}
+
+func (f *FuncStats) drainWindow() []windowDist {
+	f.parentsAndMutex.Lock()
+	defer f.parentsAndMutex.Unlock()
+	f.collect()
+	var success, failure *DurationDist
+	if f.window != nil {
+		success, failure = &f.window.successTimes, &f.window.failureTimes
+	} else {
+		success, failure = f.successTimes.Copy(), f.failureTimes.Copy()
+	}
+	window := f.funcRecord.empty()
+	f.window = &window
+	return []windowDist{success, failure}
 }
 
 // Current returns how many concurrent instances of this function are currently
@@ -114,6 +353,7 @@ func (f *FuncStats) Highwater() int64 { return atomic.LoadInt64(&f.highwater) }
 // Success returns the number of successes that have been observed
 func (f *FuncStats) Success() (rv int64) {
 	f.parentsAndMutex.Lock()
+	f.collect()
 	rv = f.successTimes.Count
 	f.parentsAndMutex.Unlock()
 	return rv
@@ -122,6 +362,7 @@ func (f *FuncStats) Success() (rv int64) {
 // Panics returns the number of panics that have been observed
 func (f *FuncStats) Panics() (rv int64) {
 	f.parentsAndMutex.Lock()
+	f.collect()
 	rv = f.panics
 	f.parentsAndMutex.Unlock()
 	return rv
@@ -132,6 +373,7 @@ func (f *FuncStats) Panics() (rv int64) {
 // with most error types.
 func (f *FuncStats) Errors() (rv map[string]int64) {
 	f.parentsAndMutex.Lock()
+	f.collect()
 	rv = make(map[string]int64, len(f.errors))
 	for errname, count := range f.errors {
 		rv[errname] = count
@@ -150,6 +392,7 @@ func (f *FuncStats) Stats(cb func(key SeriesKey, field string, val float64)) {
 	cb(f.key, "highwater", float64(f.Highwater()))
 
 	f.parentsAndMutex.Lock()
+	f.collect()
 	panics := f.panics
 	errs := make(map[string]int64, len(f.errors))
 	for errname, count := range f.errors {
@@ -157,6 +400,10 @@ func (f *FuncStats) Stats(cb func(key SeriesKey, field string, val float64)) {
 	}
 	st := f.successTimes.Copy()
 	ft := f.failureTimes.Copy()
+	var sh, fh *bucketCounts
+	if f.successHist != nil {
+		sh, fh = f.successHist.copy(), f.failureHist.copy()
+	}
 	f.parentsAndMutex.Unlock()
 
 	cb(f.key, "successes", float64(st.Count))
@@ -172,11 +419,98 @@ func (f *FuncStats) Stats(cb func(key SeriesKey, field string, val float64)) {
 
 	st.Stats(cb)
 	ft.Stats(cb)
+	if sh != nil {
+		sh.stats(cb)
+		fh.stats(cb)
+	}
+	if t := f.slo.Load(); t != nil {
+		t.stats(f.sloKey(), cb)
+	}
+}
+
+func (f *FuncStats) sloKey() SeriesKey {
+	key := f.key
+	key.Measurement += "_slo"
+	return key
+}
+
+// DescribeStats implements the StatDescriber interface.
+func (f *FuncStats) DescribeStats(cb func(key SeriesKey, field string, info StatInfo)) {
+	cb(f.key, "current", describeField(StatKindGauge, "", "",
+		"number of currently running calls"))
+	cb(f.key, "highwater", describeField(StatKindGauge, "", "",
+		"highest number of concurrently running calls"))
+	cb(f.key, "successes", describeField(StatKindCounter, "", "",
+		"number of calls that returned without an error"))
+	cb(f.key, "count", describeField(StatKindCounter, "", "",
+		"number of calls that returned the error named by the error_name tag"))
+	cb(f.key, "errors", describeField(StatKindCounter, "", "",
+		"number of calls that returned an error"))
+	cb(f.key, "panics", describeField(StatKindCounter, "", "",
+		"number of calls that panicked"))
+	cb(f.key, "failures", describeField(StatKindCounter, "", "",
+		"number of calls that returned an error or panicked"))
+	cb(f.key, "total", describeField(StatKindCounter, "", "",
+		"total number of finished calls"))
+	describeDist(f.successTimes.key, "successful call durations", UnitSeconds, StatKindCounter, cb)
+	describeDist(f.failureTimes.key, "failed call durations", UnitSeconds, StatKindCounter, cb)
+
+	f.parentsAndMutex.Lock()
+	sh, fh := f.successHist, f.failureHist
+	f.parentsAndMutex.Unlock()
+	if sh != nil {
+		describeHistogram(sh.key, "successful call durations", UnitSeconds, cb)
+		describeHistogram(fh.key, "failed call durations", UnitSeconds, cb)
+	}
+	if f.slo.Load() != nil {
+		describeSLO(f.sloKey(), cb)
+	}
+}
+
+// Sketches implements the SketchSource interface, for the success and
+// failure time distributions if UseSketch was called.
+func (f *FuncStats) Sketches(cb func(key SeriesKey, sketch *Sketch)) {
+	f.parentsAndMutex.Lock()
+	f.collect()
+	successes, failures := f.successTimes.Sketch(), f.failureTimes.Sketch()
+	f.parentsAndMutex.Unlock()
+
+	if successes != nil {
+		cb(f.successTimes.key, successes)
+	}
+	if failures != nil {
+		cb(f.failureTimes.key, failures)
+	}
+}
+
+// Exemplars implements the ExemplarSource interface. The durations of calls
+// made with a Span are exemplars of the "count" field of the success and
+// failure time distributions, and of the histogram buckets they fall in, if
+// UseHistogram was called.
+func (f *FuncStats) Exemplars(cb func(key SeriesKey, field string, exemplars []Exemplar)) {
+	f.parentsAndMutex.Lock()
+	f.collect()
+	successes := f.successTimes.exemplars.list()
+	failures := f.failureTimes.exemplars.list()
+	sh, fh := f.successHist, f.failureHist
+	f.parentsAndMutex.Unlock()
+
+	if len(successes) > 0 {
+		cb(f.successTimes.key, "count", successes)
+	}
+	if len(failures) > 0 {
+		cb(f.failureTimes.key, "count", failures)
+	}
+	if sh != nil {
+		sh.exemplars(successes, cb)
+		fh.exemplars(failures, cb)
+	}
 }
 
 // SuccessTimes returns a DurationDist of successes
 func (f *FuncStats) SuccessTimes() *DurationDist {
 	f.parentsAndMutex.Lock()
+	f.collect()
 	d := f.successTimes.Copy()
 	f.parentsAndMutex.Unlock()
 	return d
@@ -185,6 +519,7 @@ func (f *FuncStats) SuccessTimes() *DurationDist {
 // FailureTimes returns a DurationDist of failures (includes panics and errors)
 func (f *FuncStats) FailureTimes() *DurationDist {
 	f.parentsAndMutex.Lock()
+	f.collect()
 	d := f.failureTimes.Copy()
 	f.parentsAndMutex.Unlock()
 	return d
@@ -209,7 +544,7 @@ func (f *FuncStats) Observe() func(errptr *error) {
 		if errptr != nil {
 			err = *errptr
 		}
-		f.end(err, panicked, finish.Sub(start))
+		f.end(err, panicked, finish.Sub(start), finish, nil)
 		if panicked {
 			panic(rec)
 		}
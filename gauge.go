@@ -0,0 +1,142 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monkit
+
+import (
+	"math"
+	"sync/atomic"
+)
+
+// IntGauge is a gauge whose value is set rather than computed by a callback.
+// Should be constructed with NewIntGauge, though the expected usage is like:
+//
+//	var mon = monkit.Package()
+//
+//	func OnConnect() {
+//	  mon.IntGauge("connections").Add(1)
+//	}
+type IntGauge struct {
+	// sync/atomic things
+	value   int64
+	updates int64
+
+	key SeriesKey
+	description
+}
+
+// NewIntGauge creates an IntGauge.
+func NewIntGauge(key SeriesKey) *IntGauge {
+	return &IntGauge{key: key}
+}
+
+// Set sets the value of the gauge.
+func (g *IntGauge) Set(val int64) {
+	atomic.StoreInt64(&g.value, val)
+	atomic.AddInt64(&g.updates, 1)
+}
+
+// Add adds delta to the value of the gauge and returns the new value.
+func (g *IntGauge) Add(delta int64) int64 {
+	val := atomic.AddInt64(&g.value, delta)
+	atomic.AddInt64(&g.updates, 1)
+	return val
+}
+
+// Value returns the value of the gauge.
+func (g *IntGauge) Value() int64 { return atomic.LoadInt64(&g.value) }
+
+// Stats implements the StatSource interface.
+func (g *IntGauge) Stats(cb func(key SeriesKey, field string, val float64)) {
+	cb(g.key, "value", float64(g.Value()))
+}
+
+// DescribeStats implements the StatDescriber interface.
+func (g *IntGauge) DescribeStats(cb func(key SeriesKey, field string, info StatInfo)) {
+	help, unit := g.get()
+	cb(g.key, "value", describeField(StatKindGauge, help, unit, "current value"))
+}
+
+func (g *IntGauge) activity() int64 { return atomic.LoadInt64(&g.updates) }
+
+// FloatGauge is like IntGauge, but for float64 values.
+type FloatGauge struct {
+	// sync/atomic things
+	bits    uint64 // math.Float64bits of the value
+	updates int64
+
+	key SeriesKey
+	description
+}
+
+// NewFloatGauge creates a FloatGauge.
+func NewFloatGauge(key SeriesKey) *FloatGauge {
+	return &FloatGauge{key: key}
+}
+
+// Set sets the value of the gauge.
+func (g *FloatGauge) Set(val float64) {
+	atomic.StoreUint64(&g.bits, math.Float64bits(val))
+	atomic.AddInt64(&g.updates, 1)
+}
+
+// Add adds delta to the value of the gauge and returns the new value.
+func (g *FloatGauge) Add(delta float64) float64 {
+	for {
+		old := atomic.LoadUint64(&g.bits)
+		val := math.Float64frombits(old) + delta
+		if atomic.CompareAndSwapUint64(&g.bits, old, math.Float64bits(val)) {
+			atomic.AddInt64(&g.updates, 1)
+			return val
+		}
+	}
+}
+
+// Value returns the value of the gauge.
+func (g *FloatGauge) Value() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&g.bits))
+}
+
+// Stats implements the StatSource interface.
+func (g *FloatGauge) Stats(cb func(key SeriesKey, field string, val float64)) {
+	cb(g.key, "value", g.Value())
+}
+
+// DescribeStats implements the StatDescriber interface.
+func (g *FloatGauge) DescribeStats(cb func(key SeriesKey, field string, info StatInfo)) {
+	help, unit := g.get()
+	cb(g.key, "value", describeField(StatKindGauge, help, unit, "current value"))
+}
+
+func (g *FloatGauge) activity() int64 { return atomic.LoadInt64(&g.updates) }
+
+// multiGauge is the StatSource registered by Scope.GaugeMulti.
+type multiGauge struct {
+	name string
+	cb   func(emit func(field string, val float64, tags ...SeriesTag))
+}
+
+func (g *multiGauge) Stats(cb func(key SeriesKey, field string, val float64)) {
+	g.cb(func(field string, val float64, tags ...SeriesTag) {
+		cb(NewSeriesKey(g.name).WithTags(tags...), field, val)
+	})
+}
+
+// DescribeStats describes every field the callback emits as a gauge, which
+// requires calling it.
+func (g *multiGauge) DescribeStats(cb func(key SeriesKey, field string, info StatInfo)) {
+	g.Stats(func(key SeriesKey, field string, val float64) {
+		cb(key, field, describeField(StatKindGauge, "", "", "value returned by the gauge callback"))
+	})
+}
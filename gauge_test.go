@@ -0,0 +1,68 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monkit
+
+import (
+	"testing"
+)
+
+func TestGauges(t *testing.T) {
+	r := NewRegistry()
+	mon := r.ScopeNamed("gauges")
+
+	mon.Gauge("plain", func() float64 { return 1 })
+	mon.GaugeTagged("tagged", func() float64 { return 2 }, NewSeriesTag("a", "1"))
+	mon.GaugeTagged("tagged", func() float64 { return 3 }, NewSeriesTag("a", "2"))
+	mon.GaugeMulti("multi", func(emit func(string, float64, ...SeriesTag)) {
+		emit("length", 4, NewSeriesTag("queue", "q"))
+		emit("capacity", 5, NewSeriesTag("queue", "q"))
+	})
+	mon.IntGauge("connections").Add(3)
+	mon.IntGauge("connections").Add(-1)
+	mon.FloatGauge("temperature", NewSeriesTag("room", "b")).Set(1.5)
+	mon.FloatGauge("temperature", NewSeriesTag("room", "b")).Add(.25)
+
+	stats := map[string]float64{}
+	r.Stats(func(key SeriesKey, field string, val float64) {
+		stats[key.WithField(field)] = val
+	})
+	for series, expected := range map[string]float64{
+		"plain,scope=gauges value":              1,
+		"tagged,a=1,scope=gauges value":         2,
+		"tagged,a=2,scope=gauges value":         3,
+		"multi,queue=q,scope=gauges length":     4,
+		"multi,queue=q,scope=gauges capacity":   5,
+		"connections,scope=gauges value":        2,
+		"temperature,room=b,scope=gauges value": 1.75,
+	} {
+		if got, ok := stats[series]; !ok || got != expected {
+			t.Errorf("%s: got %v, expected %v", series, got, expected)
+		}
+	}
+
+	catalog := NewStatCatalog(r)
+	if info, _ := catalog.Lookup(NewSeriesKey("multi"), "length"); info.Kind != StatKindGauge {
+		t.Errorf("unexpected multi gauge description %+v", info)
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected a panic registering a multi gauge over a gauge")
+			}
+		}()
+		mon.GaugeMulti("plain", func(emit func(string, float64, ...SeriesTag)) {})
+	}()
+}
@@ -0,0 +1,260 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monkit
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Histogram keeps cumulative counts of observed values in buckets with
+// explicit upper bounds, like a Prometheus histogram. Constructed using
+// NewHistogram, though its expected usage is like:
+//
+//	var mon = monkit.Package()
+//	var latencyBuckets = monkit.ExponentialBuckets(.001, 2, 12)
+//
+//	func MyFunc() {
+//	  ...
+//	  mon.Histogram("latency", latencyBuckets).ObserveDuration(elapsed)
+//	  ...
+//	}
+//
+// Stats emits the number of values less than or equal to every bucket's upper
+// bound as the "bucket" field, with the bound in the "le" tag. The last
+// bucket's bound is always "+Inf". The "sum" and "count" fields contain the
+// sum and number of all observed values.
+type Histogram struct {
+	mtx    sync.Mutex
+	counts bucketCounts
+	description
+}
+
+// NewHistogram creates a Histogram with the given bucket upper bounds, which
+// must be sorted in increasing order. A final +Inf bucket is always added.
+func NewHistogram(key SeriesKey, buckets []float64) *Histogram {
+	h := &Histogram{}
+	initBucketCounts(&h.counts, key, buckets)
+	return h
+}
+
+// Observe observes a value. NaN values are ignored.
+func (h *Histogram) Observe(val float64) {
+	h.mtx.Lock()
+	h.counts.insert(val)
+	h.mtx.Unlock()
+}
+
+// ObserveDuration observes a duration in seconds.
+func (h *Histogram) ObserveDuration(val time.Duration) {
+	h.Observe(val.Seconds())
+}
+
+// Buckets returns the upper bounds of the buckets, including the final +Inf
+// bound, and the cumulative number of observed values in each bucket.
+func (h *Histogram) Buckets() (bounds []float64, counts []int64) {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	return h.counts.buckets()
+}
+
+// Stats implements the StatSource interface.
+func (h *Histogram) Stats(cb func(key SeriesKey, field string, val float64)) {
+	h.mtx.Lock()
+	counts := h.counts.copy()
+	h.mtx.Unlock()
+
+	counts.stats(cb)
+}
+
+// DescribeStats implements the StatDescriber interface.
+func (h *Histogram) DescribeStats(cb func(key SeriesKey, field string, info StatInfo)) {
+	help, unit := h.get()
+	describeHistogram(h.counts.key, help, unit, cb)
+}
+
+// LinearBuckets returns count bucket upper bounds, starting at start and
+// increasing by width.
+func LinearBuckets(start, width float64, count int) []float64 {
+	if count < 1 || !(width > 0) {
+		panic(fmt.Sprintf("invalid linear buckets: width %v, count %d", width, count))
+	}
+	buckets := make([]float64, count)
+	for i := range buckets {
+		buckets[i] = start + float64(i)*width
+	}
+	return buckets
+}
+
+// ExponentialBuckets returns count bucket upper bounds, starting at start and
+// multiplying by factor.
+func ExponentialBuckets(start, factor float64, count int) []float64 {
+	if count < 1 || !(start > 0) || !(factor > 1) {
+		panic(fmt.Sprintf("invalid exponential buckets: start %v, factor %v, count %d",
+			start, factor, count))
+	}
+	buckets := make([]float64, count)
+	for i := range buckets {
+		buckets[i] = start
+		start *= factor
+	}
+	return buckets
+}
+
+// bucketCounts keeps the state of a Histogram. Not threadsafe.
+type bucketCounts struct {
+	key    SeriesKey
+	bounds []float64 // shared between copies, never modified
+	les    []string  // formatted bounds, shared like bounds
+	counts []int64   // per bucket, not cumulative
+	count  int64
+	sum    float64
+}
+
+// checkBuckets panics if the bucket bounds are not increasing.
+func checkBuckets(buckets []float64) {
+	for i, bound := range buckets {
+		if math.IsNaN(bound) || (i > 0 && bound <= buckets[i-1]) {
+			panic(fmt.Sprintf("histogram buckets are not increasing: %v", buckets))
+		}
+	}
+}
+
+func initBucketCounts(b *bucketCounts, key SeriesKey, buckets []float64) {
+	checkBuckets(buckets)
+	bounds := make([]float64, 0, len(buckets)+1)
+	for _, bound := range buckets {
+		if !math.IsInf(bound, 1) {
+			bounds = append(bounds, bound)
+		}
+	}
+	bounds = append(bounds, math.Inf(1))
+
+	les := make([]string, len(bounds))
+	for i, bound := range bounds {
+		les[i] = formatBound(bound)
+	}
+
+	b.key = key
+	b.bounds = bounds
+	b.les = les
+	b.counts = make([]int64, len(bounds))
+}
+
+// sameBounds returns whether initBucketCounts would create bounds from
+// buckets.
+func sameBounds(bounds, buckets []float64) bool {
+	i := 0
+	for _, bound := range buckets {
+		if math.IsInf(bound, 1) {
+			continue
+		}
+		if i >= len(bounds)-1 || bounds[i] != bound {
+			return false
+		}
+		i++
+	}
+	return i == len(bounds)-1
+}
+
+func formatBound(bound float64) string {
+	if math.IsInf(bound, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(bound, 'g', -1, 64)
+}
+
+func (b *bucketCounts) insert(val float64) {
+	if math.IsNaN(val) {
+		return
+	}
+	b.counts[sort.SearchFloat64s(b.bounds, val)]++
+	b.count++
+	b.sum += val
+}
+
+func (b *bucketCounts) reset() {
+	for i := range b.counts {
+		b.counts[i] = 0
+	}
+	b.count, b.sum = 0, 0
+}
+
+// merge adds the counts of other, which must have the same bounds, to b.
+func (b *bucketCounts) merge(other *bucketCounts) {
+	for i, count := range other.counts {
+		b.counts[i] += count
+	}
+	b.count += other.count
+	b.sum += other.sum
+}
+
+func (b *bucketCounts) copy() *bucketCounts {
+	cp := *b
+	cp.counts = append([]int64(nil), b.counts...)
+	return &cp
+}
+
+func (b *bucketCounts) buckets() (bounds []float64, counts []int64) {
+	bounds = append([]float64(nil), b.bounds...)
+	counts = make([]int64, len(b.counts))
+	var total int64
+	for i, count := range b.counts {
+		total += count
+		counts[i] = total
+	}
+	return bounds, counts
+}
+
+func (b *bucketCounts) stats(cb func(key SeriesKey, field string, val float64)) {
+	var total int64
+	for i, count := range b.counts {
+		total += count
+		cb(b.key.WithTag("le", b.les[i]), "bucket", float64(total))
+	}
+	cb(b.key, "sum", b.sum)
+	cb(b.key, "count", float64(b.count))
+}
+
+// exemplars calls cb with the exemplars of every bucket, which are the given
+// exemplars whose value falls into the bucket.
+func (b *bucketCounts) exemplars(exemplars []Exemplar,
+	cb func(key SeriesKey, field string, exemplars []Exemplar)) {
+	perBucket := make(map[int][]Exemplar)
+	for _, ex := range exemplars {
+		i := sort.SearchFloat64s(b.bounds, ex.Value)
+		perBucket[i] = append(perBucket[i], ex)
+	}
+	for i := range b.bounds {
+		if exemplars := perBucket[i]; len(exemplars) > 0 {
+			cb(b.key.WithTag("le", b.les[i]), "bucket", exemplars)
+		}
+	}
+}
+
+// describeHistogram describes the fields emitted by bucketCounts.stats. help
+// is the user supplied description, if any.
+func describeHistogram(key SeriesKey, help, unit string,
+	cb func(key SeriesKey, field string, info StatInfo)) {
+	cb(key, "bucket", describeField(StatKindHistogram, help, unit,
+		"number of observed values less than or equal to the le tag"))
+	// the sum decreases with negative values, so it's not a counter.
+	cb(key, "sum", describeField(StatKindGauge, help, unit, "sum of observed values"))
+	cb(key, "count", describeField(StatKindHistogram, help, "", "number of observed values"))
+}
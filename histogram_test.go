@@ -0,0 +1,72 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monkit
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestHistogram(t *testing.T) {
+	r := NewRegistry()
+	mon := r.ScopeNamed("histogram")
+	h := mon.Histogram("size", LinearBuckets(10, 10, 3))
+	for _, v := range []float64{1, 10, 15, 25, 100} {
+		h.Observe(v)
+	}
+
+	bounds, counts := h.Buckets()
+	if len(bounds) != 4 || bounds[2] != 30 || !reflect.DeepEqual(counts, []int64{2, 3, 4, 5}) {
+		t.Fatalf("unexpected buckets %v %v", bounds, counts)
+	}
+
+	stats := map[string]float64{}
+	r.Stats(func(key SeriesKey, field string, val float64) {
+		stats[key.WithField(field)] = val
+	})
+	for series, expected := range map[string]float64{
+		"size,le=20,scope=histogram bucket":   3,
+		"size,le=+Inf,scope=histogram bucket": 5,
+		"size,scope=histogram sum":            151,
+		"size,scope=histogram count":          5,
+	} {
+		if got, ok := stats[series]; !ok || got != expected {
+			t.Errorf("%s: got %v, expected %v", series, got, expected)
+		}
+	}
+}
+
+func TestFuncStatsHistogram(t *testing.T) {
+	f := NewFuncStats(NewSeriesKey("function"))
+	f.UseHistogram(ExponentialBuckets(.001, 10, 3))
+	f.end(nil, false, 5*time.Millisecond, time.Now(), nil)
+	f.end(nil, false, time.Second, time.Now(), nil)
+
+	stats := map[string]float64{}
+	f.Stats(func(key SeriesKey, field string, val float64) {
+		stats[key.WithField(field)] = val
+	})
+	for series, expected := range map[string]float64{
+		"function_times_histogram,kind=success,le=0.01 bucket": 1,
+		"function_times_histogram,kind=success,le=+Inf bucket": 2,
+		"function_times_histogram,kind=failure,le=+Inf bucket": 0,
+		"function_times_histogram,kind=success count":          2,
+	} {
+		if got, ok := stats[series]; !ok || got != expected {
+			t.Errorf("%s: got %v, expected %v", series, got, expected)
+		}
+	}
+}
@@ -0,0 +1,275 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monkit
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// HistoryTier is one resolution a History keeps values at: one point per
+// Interval, for as long as Retention.
+type HistoryTier struct {
+	Interval  time.Duration
+	Retention time.Duration
+}
+
+// DefaultHistoryTiers are the tiers a History uses if none are given: a point
+// every 10 seconds for the last hour, and a point every minute for the last
+// day.
+var DefaultHistoryTiers = []HistoryTier{
+	{Interval: 10 * time.Second, Retention: time.Hour},
+	{Interval: time.Minute, Retention: 24 * time.Hour},
+}
+
+// HistoryPoint is the value of a field during one interval of a HistoryTier.
+// For fields of gauge-like kinds, Value is the mean of the values recorded
+// during the interval, for counter and histogram fields it is the last one.
+type HistoryPoint struct {
+	// Time is the start of the interval.
+	Time  time.Time
+	Value float64
+}
+
+// History keeps recent values of every field of a Registry in memory, so
+// that what a statistic looked like a while ago can be looked at. Every tier
+// keeps a bounded ring buffer of points per field. Values are recorded at the
+// Interval of the first tier, and the other tiers downsample them.
+//
+// A History costs memory for every field, so for Registries with many series
+// it can be created for a Registry with CallbackTransformers that filter the
+// fields to keep. Create one with NewHistory, or Registry.RecordHistory.
+type History struct {
+	r     *Registry
+	tiers []HistoryTier
+
+	mtx        sync.Mutex
+	collection int
+	latest     time.Time
+	series     map[string]*seriesHistory
+}
+
+type seriesHistory struct {
+	key        SeriesKey
+	field      string
+	last       bool // keep the last value of intervals instead of the mean
+	collection int  // the collection the field was last seen in
+	tiers      []historyRing
+}
+
+// historyRing is the ring buffer of points of one tier. The last point is
+// the interval that is currently being recorded.
+type historyRing struct {
+	points []HistoryPoint // grows up to size, then wraps
+	size   int
+	next   int // the slot the next point goes to once points is full
+	sum    float64
+	count  int
+}
+
+// NewHistory creates a History of the fields of r with the given tiers, or
+// DefaultHistoryTiers if there are none. The tiers must have increasing
+// intervals, and retentions of at least one interval. Values are only
+// recorded when Record is called, see Run or Registry.RecordHistory to
+// record them in the background.
+func NewHistory(r *Registry, tiers ...HistoryTier) *History {
+	if len(tiers) == 0 {
+		tiers = DefaultHistoryTiers
+	}
+	for i, tier := range tiers {
+		if tier.Interval <= 0 || tier.Retention < tier.Interval ||
+			(i > 0 && tier.Interval <= tiers[i-1].Interval) {
+			panic(fmt.Sprintf("invalid history tiers: %v", tiers))
+		}
+	}
+	return &History{
+		r:      r,
+		tiers:  append([]HistoryTier(nil), tiers...),
+		series: map[string]*seriesHistory{},
+	}
+}
+
+// RecordHistory creates a History of r like NewHistory and runs it until ctx
+// is done. It becomes the History that Registry.History returns, which is
+// how the present package finds it.
+func (r *Registry) RecordHistory(ctx context.Context, tiers ...HistoryTier) *History {
+	h := NewHistory(r, tiers...)
+	r.history.Store(h)
+	go h.Run(ctx)
+	return h
+}
+
+// History returns the History that was last started with RecordHistory, or
+// nil if there is none.
+func (r *Registry) History() *History {
+	return r.history.Load()
+}
+
+// Tiers returns the tiers of the History.
+func (h *History) Tiers() []HistoryTier {
+	return append([]HistoryTier(nil), h.tiers...)
+}
+
+// Run calls Record every Interval of the first tier until ctx is done.
+func (h *History) Run(ctx context.Context) {
+	ticker := time.NewTicker(h.tiers[0].Interval)
+	defer ticker.Stop()
+	h.Record(time.Now())
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			h.Record(now)
+		}
+	}
+}
+
+// Record records the current values of all fields as of now. Fields that have
+// not been recorded for longer than the retention of all tiers are dropped.
+func (h *History) Record(now time.Time) {
+	type value struct {
+		key   SeriesKey
+		field string
+		val   float64
+	}
+	var values []value
+	h.r.Stats(func(key SeriesKey, field string, val float64) {
+		values = append(values, value{key: key, field: field, val: val})
+	})
+
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+
+	h.collection++
+	h.latest = now
+	var catalog *StatCatalog
+	for _, v := range values {
+		name := v.key.WithField(v.field)
+		series, ok := h.series[name]
+		if !ok {
+			// the catalog is only needed for new fields, which are rare.
+			if catalog == nil {
+				catalog = NewStatCatalog(h.r)
+			}
+			info, _ := catalog.Lookup(v.key, v.field)
+			series = &seriesHistory{
+				key:   v.key,
+				field: v.field,
+				last:  info.Kind == StatKindCounter || info.Kind == StatKindHistogram,
+				tiers: make([]historyRing, len(h.tiers)),
+			}
+			for i, tier := range h.tiers {
+				series.tiers[i].size = int(tier.Retention / tier.Interval)
+			}
+			h.series[name] = series
+		}
+		series.collection = h.collection
+		for i, tier := range h.tiers {
+			series.tiers[i].record(now.Truncate(tier.Interval), v.val, series.last)
+		}
+	}
+
+	maxRetention := h.tiers[len(h.tiers)-1].Retention
+	for name, series := range h.series {
+		if series.collection != h.collection && series.latest().Add(maxRetention).Before(now) {
+			delete(h.series, name)
+		}
+	}
+}
+
+func (ring *historyRing) record(interval time.Time, val float64, last bool) {
+	if n := len(ring.points); n > 0 {
+		current := &ring.points[(ring.next+n-1)%n]
+		if current.Time.Equal(interval) {
+			ring.sum += val
+			ring.count++
+			if last {
+				current.Value = val
+			} else {
+				current.Value = ring.sum / float64(ring.count)
+			}
+			return
+		}
+	}
+
+	ring.sum, ring.count = val, 1
+	point := HistoryPoint{Time: interval, Value: val}
+	if len(ring.points) < ring.size {
+		ring.points = append(ring.points, point)
+		return
+	}
+	ring.points[ring.next] = point
+	ring.next = (ring.next + 1) % ring.size
+}
+
+// list returns the points of the ring in order.
+func (ring *historyRing) list() []HistoryPoint {
+	rv := make([]HistoryPoint, 0, len(ring.points))
+	rv = append(rv, ring.points[ring.next:]...)
+	rv = append(rv, ring.points[:ring.next]...)
+	return rv
+}
+
+// latest returns the time of the most recent point.
+func (s *seriesHistory) latest() time.Time {
+	points := s.tiers[0].points
+	if len(points) == 0 {
+		return time.Time{}
+	}
+	return points[(s.tiers[0].next+len(points)-1)%len(points)].Time
+}
+
+// Series calls cb with the key and field of every field in the History,
+// ordered by series and field.
+func (h *History) Series(cb func(key SeriesKey, field string)) {
+	h.mtx.Lock()
+	names := make([]string, 0, len(h.series))
+	for name := range h.series {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	series := make([]*seriesHistory, 0, len(names))
+	for _, name := range names {
+		series = append(series, h.series[name])
+	}
+	h.mtx.Unlock()
+
+	for _, s := range series {
+		cb(s.key, s.field)
+	}
+}
+
+// Points returns the points of the given field of a series in the tier with
+// the given index, ordered by time. Points older than the tier's retention
+// are left out.
+func (h *History) Points(key SeriesKey, field string, tier int) []HistoryPoint {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+
+	series, ok := h.series[key.WithField(field)]
+	if !ok || tier < 0 || tier >= len(h.tiers) {
+		return nil
+	}
+	points := series.tiers[tier].list()
+	cutoff := h.latest.Add(-h.tiers[tier].Retention)
+	for len(points) > 0 && !points[0].Time.After(cutoff) {
+		points = points[1:]
+	}
+	return points
+}
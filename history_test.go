@@ -0,0 +1,119 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monkit
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func pointValues(points []HistoryPoint) []float64 {
+	values := make([]float64, 0, len(points))
+	for _, p := range points {
+		values = append(values, p.Value)
+	}
+	return values
+}
+
+func TestHistory(t *testing.T) {
+	r := NewRegistry()
+	mon := r.ScopeNamed("test")
+	gauge := 0.0
+	mon.Gauge("gauge", func() float64 { return gauge })
+	meter := mon.Meter("meter")
+
+	h := NewHistory(r,
+		HistoryTier{Interval: time.Second, Retention: 4 * time.Second},
+		HistoryTier{Interval: 3 * time.Second, Retention: 9 * time.Second})
+
+	start := time.Unix(999, 0)
+	for i := 0; i < 9; i++ {
+		gauge = float64(i)
+		meter.Mark(1)
+		h.Record(start.Add(time.Duration(i) * time.Second))
+	}
+
+	gaugeKey := NewSeriesKey("gauge").WithTag("scope", "test")
+	meterKey := NewSeriesKey("meter").WithTag("scope", "test")
+
+	// the first tier keeps the last 4 seconds.
+	if got := pointValues(h.Points(gaugeKey, "value", 0)); !reflect.DeepEqual(got, []float64{5, 6, 7, 8}) {
+		t.Fatalf("got %v", got)
+	}
+	points := h.Points(gaugeKey, "value", 0)
+	if !points[0].Time.Equal(time.Unix(1004, 0)) {
+		t.Fatalf("got time %v", points[0].Time)
+	}
+
+	// the second tier has means of gauges, and the last values of counters,
+	// where the intervals start at 999, 1002 and 1005.
+	if got := pointValues(h.Points(gaugeKey, "value", 1)); !reflect.DeepEqual(got, []float64{1, 4, 7}) {
+		t.Fatalf("got %v", got)
+	}
+	if got := pointValues(h.Points(meterKey, "total", 1)); !reflect.DeepEqual(got, []float64{3, 6, 9}) {
+		t.Fatalf("got %v", got)
+	}
+
+	var names []string
+	h.Series(func(key SeriesKey, field string) {
+		names = append(names, key.WithField(field))
+	})
+	if len(names) < 2 || names[0] != "gauge,scope=test value" {
+		t.Fatalf("got %v", names)
+	}
+
+	if h.Points(gaugeKey, "value", 2) != nil || h.Points(NewSeriesKey("missing"), "value", 0) != nil {
+		t.Fatal("expected no points")
+	}
+}
+
+func TestHistoryExpiry(t *testing.T) {
+	r := NewRegistry()
+	mon := r.ScopeNamed("test")
+	h := NewHistory(r, HistoryTier{Interval: time.Second, Retention: 2 * time.Second})
+
+	start := time.Unix(1000, 0)
+	mon.Event("event")
+	h.Record(start)
+	r.scopes = map[string]*Scope{}
+
+	count := func() (n int) {
+		h.Series(func(key SeriesKey, field string) { n++ })
+		return n
+	}
+	h.Record(start.Add(2 * time.Second))
+	if count() == 0 {
+		t.Fatal("fields expired too early")
+	}
+	h.Record(start.Add(3 * time.Second))
+	if count() != 0 {
+		t.Fatal("fields did not expire")
+	}
+}
+
+func TestRecordHistory(t *testing.T) {
+	r := NewRegistry()
+	if r.History() != nil {
+		t.Fatal("unexpected History")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	h := r.RecordHistory(ctx, HistoryTier{Interval: time.Hour, Retention: time.Hour})
+	if r.WithTransformers().History() != h {
+		t.Fatal("History not found")
+	}
+}
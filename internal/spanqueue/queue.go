@@ -0,0 +1,291 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package spanqueue collects the finished spans of a Registry's traces in a
+// bounded queue, and exports them in batches. It is shared by the trace
+// exporters.
+package spanqueue
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/spacemonkeygo/monkit/v3"
+	"github.com/spacemonkeygo/monkit/v3/collect"
+)
+
+// ErrAlreadyRun is returned by Run if the Queue was run before.
+var ErrAlreadyRun = errors.New("already run")
+
+// Options configure a Queue. The zero value of every option picks a default.
+type Options struct {
+	// Sample decides which traces are collected. Defaults to all of them.
+	Sample func(t *monkit.Trace) bool
+	// QueueSize is the most finished spans kept waiting to be exported.
+	// Defaults to 2048.
+	QueueSize int
+	// DropOldest makes a full queue drop its oldest spans for new ones,
+	// rather than dropping the new ones.
+	DropOldest bool
+	// BatchSize is the most spans exported at once. Defaults to 512.
+	BatchSize int
+	// BatchTimeout is the longest a span waits for its batch to fill up
+	// before it is exported. Defaults to 5 seconds.
+	BatchTimeout time.Duration
+}
+
+func (opts Options) withDefaults() Options {
+	if opts.Sample == nil {
+		opts.Sample = func(*monkit.Trace) bool { return true }
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = 2048
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 512
+	}
+	if opts.BatchTimeout <= 0 {
+		opts.BatchTimeout = 5 * time.Second
+	}
+	return opts
+}
+
+// ExportFunc exports a batch of spans.
+type ExportFunc func(ctx context.Context, batch []*collect.FinishedSpan) error
+
+// Queue collects finished spans and passes them to an ExportFunc in batches.
+// Create one with New, and call Run to collect the traces of its Registry.
+//
+// Spans of batches that fail to export are put back at the front of the
+// queue and retried with the next export, so they are only lost once the
+// queue is full and its drop policy drops them, or when Run returns. Spans of
+// an export that failed after it was delivered are exported twice.
+type Queue struct {
+	r      *monkit.Registry
+	opts   Options
+	export ExportFunc
+
+	sent    *monkit.Meter
+	dropped *monkit.Meter
+	failed  *monkit.Meter
+
+	// exportMtx serializes exports.
+	exportMtx sync.Mutex
+
+	mtx     sync.Mutex
+	ring    []*collect.FinishedSpan // a ring buffer of len(ring) == QueueSize
+	head    int                     // index of the oldest span
+	queued  int                     // number of spans in the ring
+	running bool
+	stopped bool
+	full    chan struct{} // signaled when the queue has a full batch
+}
+
+// New creates a Queue for the traces of r that exports spans with export.
+// The Queue reports the meters spans_sent, spans_dropped and
+// span_export_failures in mon.
+func New(r *monkit.Registry, mon *monkit.Scope, opts Options, export ExportFunc) *Queue {
+	opts = opts.withDefaults()
+	return &Queue{
+		r:      r,
+		opts:   opts,
+		export: export,
+
+		sent:    mon.Meter("spans_sent"),
+		dropped: mon.Meter("spans_dropped"),
+		failed:  mon.Meter("span_export_failures"),
+
+		ring: make([]*collect.FinishedSpan, opts.QueueSize),
+		full: make(chan struct{}, 1),
+	}
+}
+
+// Run observes the Registry's new traces, picked by Options.Sample, and
+// exports their spans as they finish, until ctx is done. It then exports the
+// queued spans one last time, and spans that finish afterwards are ignored.
+// A Queue can only be run once.
+func (q *Queue) Run(ctx context.Context) error {
+	q.mtx.Lock()
+	if q.running || q.stopped {
+		q.mtx.Unlock()
+		return ErrAlreadyRun
+	}
+	q.running = true
+	q.mtx.Unlock()
+
+	cancel := q.r.ObserveTraces(func(t *monkit.Trace) {
+		if q.opts.Sample(t) {
+			t.ObserveSpansCtx(q)
+		}
+	})
+
+	ticker := time.NewTicker(q.opts.BatchTimeout)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			cancel()
+			q.mtx.Lock()
+			// the observers of live traces can't all be found to remove
+			// them, so they are turned into no-ops instead.
+			q.stopped = true
+			q.mtx.Unlock()
+
+			flushCtx, flushCancel := context.WithTimeout(context.Background(), q.opts.BatchTimeout)
+			defer flushCancel()
+			err := q.Flush(flushCtx)
+			q.mtx.Lock()
+			lost := q.queued
+			q.clear()
+			q.mtx.Unlock()
+			q.dropped.Mark(lost)
+			return err
+		case <-ticker.C:
+		case <-q.full:
+		}
+		_ = q.Flush(ctx)
+	}
+}
+
+// Start implements monkit.SpanCtxObserver.
+func (q *Queue) Start(ctx context.Context, s *monkit.Span) context.Context {
+	return ctx
+}
+
+// Finish implements monkit.SpanCtxObserver. It queues the Span to be
+// exported, or drops it or the oldest queued one if the queue is full.
+func (q *Queue) Finish(ctx context.Context, s *monkit.Span, err error, panicked bool, finish time.Time) {
+	q.mtx.Lock()
+	if q.stopped {
+		q.mtx.Unlock()
+		return
+	}
+	dropped := q.push(&collect.FinishedSpan{Span: s, Err: err, Panicked: panicked, Finish: finish})
+	full := q.queued >= q.opts.BatchSize
+	q.mtx.Unlock()
+
+	if dropped {
+		q.dropped.Mark(1)
+	}
+	if full {
+		select {
+		case q.full <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Flush exports the queued spans in batches of at most BatchSize, until the
+// queue is empty or an export fails. It returns the error of the failed
+// export, whose spans are put back into the queue.
+func (q *Queue) Flush(ctx context.Context) error {
+	q.exportMtx.Lock()
+	defer q.exportMtx.Unlock()
+
+	for {
+		q.mtx.Lock()
+		batch := q.take(q.opts.BatchSize)
+		q.mtx.Unlock()
+		if len(batch) == 0 {
+			return nil
+		}
+
+		if err := q.export(ctx, batch); err != nil {
+			q.failed.Mark(1)
+			q.mtx.Lock()
+			dropped := q.requeue(batch)
+			q.mtx.Unlock()
+			q.dropped.Mark(dropped)
+			return err
+		}
+		q.sent.Mark(len(batch))
+	}
+}
+
+// Queued returns the spans waiting to be exported, oldest first.
+func (q *Queue) Queued() []*collect.FinishedSpan {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+	spans := make([]*collect.FinishedSpan, q.queued)
+	for i := range spans {
+		spans[i] = q.ring[(q.head+i)%len(q.ring)]
+	}
+	return spans
+}
+
+// push adds s as the newest span, and returns whether a span was dropped
+// for it. q.mtx must be held.
+func (q *Queue) push(s *collect.FinishedSpan) (dropped bool) {
+	if q.queued == len(q.ring) {
+		if !q.opts.DropOldest {
+			return true
+		}
+		q.ring[q.head] = nil
+		q.head = (q.head + 1) % len(q.ring)
+		q.queued--
+		dropped = true
+	}
+	q.ring[(q.head+q.queued)%len(q.ring)] = s
+	q.queued++
+	return dropped
+}
+
+// take removes and returns up to n of the oldest spans. q.mtx must be held.
+func (q *Queue) take(n int) []*collect.FinishedSpan {
+	if n > q.queued {
+		n = q.queued
+	}
+	batch := make([]*collect.FinishedSpan, n)
+	for i := range batch {
+		batch[i] = q.ring[q.head]
+		q.ring[q.head] = nil
+		q.head = (q.head + 1) % len(q.ring)
+	}
+	q.queued -= n
+	return batch
+}
+
+// requeue puts batch back as the oldest spans, following the drop policy if
+// the queue is too full for all of it, and returns the number of dropped
+// spans. q.mtx must be held.
+func (q *Queue) requeue(batch []*collect.FinishedSpan) (dropped int) {
+	for i := len(batch) - 1; i >= 0; i-- {
+		if q.queued == len(q.ring) {
+			if q.opts.DropOldest {
+				// the rest of the batch is older than all queued spans.
+				return dropped + i + 1
+			}
+			newest := (q.head + q.queued - 1) % len(q.ring)
+			q.ring[newest] = nil
+			q.queued--
+			dropped++
+		}
+		q.head = (q.head - 1 + len(q.ring)) % len(q.ring)
+		q.ring[q.head] = batch[i]
+		q.queued++
+	}
+	return dropped
+}
+
+// clear drops all queued spans. q.mtx must be held.
+func (q *Queue) clear() {
+	for i := range q.ring {
+		q.ring[i] = nil
+	}
+	q.head, q.queued = 0, 0
+}
+
+var _ monkit.SpanCtxObserver = (*Queue)(nil)
@@ -0,0 +1,101 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanqueue
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/spacemonkeygo/monkit/v3"
+	"github.com/spacemonkeygo/monkit/v3/collect"
+)
+
+func finish(q *Queue, mon *monkit.Scope, names ...string) {
+	for _, name := range names {
+		ctx := context.Background()
+		mon.TaskNamed(name)(&ctx)(nil)
+		q.Finish(ctx, monkit.SpanFromCtx(ctx), nil, false, time.Now())
+	}
+}
+
+func names(spans []*collect.FinishedSpan) (out string) {
+	for _, fs := range spans {
+		out += fs.Span.Func().ShortName()
+	}
+	return out
+}
+
+func TestQueue(t *testing.T) {
+	r := monkit.NewRegistry()
+	mon := r.ScopeNamed("test")
+	for _, dropOldest := range []bool{false, true} {
+		q := New(r, mon, Options{QueueSize: 3, DropOldest: dropOldest}, nil)
+		// wrap around the ring a few times.
+		finish(q, mon, "a", "b", "c", "d", "e", "f", "g")
+		expected := "abc"
+		if dropOldest {
+			expected = "efg"
+		}
+		if got := names(q.Queued()); got != expected {
+			t.Fatalf("drop oldest %v: got %q queued", dropOldest, got)
+		}
+	}
+}
+
+func TestQueueRetry(t *testing.T) {
+	r := monkit.NewRegistry()
+	mon := r.ScopeNamed("test")
+	for _, dropOldest := range []bool{false, true} {
+		var exported string
+		fail := true
+		q := New(r, mon, Options{QueueSize: 4, BatchSize: 2, DropOldest: dropOldest},
+			func(ctx context.Context, batch []*collect.FinishedSpan) error {
+				if fail {
+					return errors.New("unavailable")
+				}
+				exported += names(batch)
+				return nil
+			})
+
+		finish(q, mon, "a", "b", "c")
+		if err := q.Flush(context.Background()); err == nil {
+			t.Fatal("expected an error")
+		}
+		if got := names(q.Queued()); got != "abc" {
+			t.Fatalf("drop oldest %v: got %q queued after a failure", dropOldest, got)
+		}
+
+		// the failed batch is put back into a full queue.
+		finish(q, mon, "d", "e")
+		_ = q.Flush(context.Background())
+		expected := "abcd"
+		if dropOldest {
+			expected = "bcde"
+		}
+		if got := names(q.Queued()); got != expected {
+			t.Fatalf("drop oldest %v: got %q queued after a retry", dropOldest, got)
+		}
+
+		fail = false
+		if err := q.Flush(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if exported != expected || len(q.Queued()) != 0 {
+			t.Fatalf("drop oldest %v: exported %q, %d left", dropOldest, exported, len(q.Queued()))
+		}
+	}
+}
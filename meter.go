@@ -15,6 +15,8 @@
 package monkit
 
 import (
+	"fmt"
+	"math"
 	"sync"
 	"time"
 
@@ -27,9 +29,40 @@ const (
 )
 
 var (
-	defaultTicker = ticker{}
+	tickersMtx sync.Mutex
+	tickers    = map[time.Duration]*ticker{}
 )
 
+// ewmaWindows are the windows of the exponentially weighted moving average
+// rates reported by Meters, along with their field names.
+var ewmaWindows = [...]struct {
+	field  string
+	window time.Duration
+}{
+	{"rate_1m", time.Minute},
+	{"rate_5m", 5 * time.Minute},
+	{"rate_15m", 15 * time.Minute},
+}
+
+// MeterWindow configures the sliding window a Meter computes its rate over.
+// The window is Ticks buckets of Tick duration each, and it only moves
+// forward while events are marked, so that rare events do not just have a
+// zero rate.
+type MeterWindow struct {
+	Tick  time.Duration
+	Ticks int
+}
+
+// DefaultMeterWindow is the window used by Meters unless configured
+// otherwise: 24 ticks of 10 minutes.
+var DefaultMeterWindow = MeterWindow{Tick: timePerTick, Ticks: ticksToKeep}
+
+func (w MeterWindow) validate() {
+	if w.Tick <= 0 || w.Ticks < 1 {
+		panic(fmt.Sprintf("invalid meter window %+v", w))
+	}
+}
+
 type meterBucket struct {
 	count int64
 	start time.Time
@@ -49,21 +82,45 @@ type meterBucket struct {
 //	  meter.Mark(4) // 4 things happened
 //	  ...
 //	}
+//
+// Besides the rate over its sliding window, a Meter reports exponentially
+// weighted moving averages of the rate over 1, 5 and 15 minutes, as the
+// rate_1m, rate_5m and rate_15m fields.
 type Meter struct {
+	// marked events not yet added to the current slice
+	pending adder
+
 	mtx    sync.Mutex
 	total  int64
-	slices [ticksToKeep]meterBucket
+	slices []meterBucket
+	ticker *ticker
 	key    SeriesKey
+	events *eventHook // nil unless created through a Scope
+	description
+
+	// ewma state, updated whenever the stats are read.
+	ewma     [len(ewmaWindows)]float64
+	ewmaSeen int64 // total events accounted for in ewma
+	ewmaLast time.Time
 }
 
-// NewMeter constructs a Meter
+// NewMeter constructs a Meter with the DefaultMeterWindow.
 func NewMeter(key SeriesKey) *Meter {
-	rv := &Meter{key: key}
+	return NewMeterWithWindow(key, DefaultMeterWindow)
+}
+
+// NewMeterWithWindow constructs a Meter that computes its rate over the given
+// window.
+func NewMeterWithWindow(key SeriesKey, window MeterWindow) *Meter {
+	window.validate()
+	rv := &Meter{key: key, slices: make([]meterBucket, window.Ticks)}
 	now := monotime.Now()
-	for i := 0; i < ticksToKeep; i++ {
+	for i := range rv.slices {
 		rv.slices[i].start = now
 	}
-	defaultTicker.register(rv)
+	rv.ewmaLast = now
+	rv.ticker = tickerFor(window.Tick)
+	rv.ticker.register(rv)
 	return rv
 }
 
@@ -72,65 +129,103 @@ func NewMeter(key SeriesKey) *Meter {
 // Useful when monitoring a counter that has overflowed.
 func (e *Meter) Reset(new_total int64) {
 	e.mtx.Lock()
+	e.pending.Drain()
 	e.total = new_total
 	now := monotime.Now()
 	for i := range e.slices {
 		e.slices[i].count = 0
 		e.slices[i].start = now
 	}
+	e.ewma = [len(ewmaWindows)]float64{}
+	e.ewmaSeen = new_total
+	e.ewmaLast = now
 	e.mtx.Unlock()
 }
 
 // SetTotal sets the initial total count of the meter.
 func (e *Meter) SetTotal(total int64) {
 	e.mtx.Lock()
+	// the new total is not a burst of events.
+	e.ewmaSeen += total - e.total
 	e.total = total
 	e.mtx.Unlock()
 }
 
 // Mark marks amount events occurring in the current time window.
 func (e *Meter) Mark(amount int) {
-	e.mtx.Lock()
-	e.slices[ticksToKeep-1].count += int64(amount)
-	e.mtx.Unlock()
+	e.pending.Add(int64(amount))
+	e.events.mark(e.key, int64(amount))
 }
 
 // Mark64 marks amount events occurring in the current time window (int64 version).
 func (e *Meter) Mark64(amount int64) {
-	e.mtx.Lock()
-	e.slices[ticksToKeep-1].count += amount
-	e.mtx.Unlock()
+	e.pending.Add(amount)
+	e.events.mark(e.key, amount)
+}
+
+// flush adds the pending events to the current slice. e.mtx must be held.
+func (e *Meter) flush() {
+	e.slices[len(e.slices)-1].count += e.pending.Drain()
 }
 
 func (e *Meter) tick(now time.Time) {
 	e.mtx.Lock()
+	e.flush()
 	// only advance meter buckets if something happened. otherwise
 	// rare events will always just have zero rates.
-	if e.slices[ticksToKeep-1].count != 0 {
+	last := len(e.slices) - 1
+	if e.slices[last].count != 0 {
 		e.total += e.slices[0].count
-		copy(e.slices[:], e.slices[1:])
-		e.slices[ticksToKeep-1] = meterBucket{count: 0, start: now}
+		copy(e.slices, e.slices[1:])
+		e.slices[last] = meterBucket{count: 0, start: now}
 	}
 	e.mtx.Unlock()
 }
 
 func (e *Meter) stats(now time.Time) (rate float64, total int64) {
+	rate, total, _ = e.allStats(now)
+	return rate, total
+}
+
+// allStats returns the rate over the sliding window, the total and the
+// moving average rates, after updating the latter.
+func (e *Meter) allStats(now time.Time) (rate float64, total int64,
+	ewma [len(ewmaWindows)]float64) {
 	current := int64(0)
 	e.mtx.Lock()
+	e.flush()
 	start := e.slices[0].start
-	for i := 0; i < ticksToKeep; i++ {
+	for i := range e.slices {
 		current += e.slices[i].count
 	}
-	total = e.total
+	total = e.total + current
+	e.updateEWMA(now, total)
+	ewma = e.ewma
 	e.mtx.Unlock()
-	total += current
 	duration := now.Sub(start).Seconds()
 	if duration > 0 {
 		rate = float64(current) / duration
 	} else {
 		rate = 0
 	}
-	return rate, total
+	return rate, total, ewma
+}
+
+// updateEWMA folds the events since the last update into the moving
+// averages, treating them as having happened at a constant rate since then.
+// e.mtx must be held.
+func (e *Meter) updateEWMA(now time.Time, total int64) {
+	elapsed := now.Sub(e.ewmaLast)
+	if elapsed <= 0 {
+		return
+	}
+	rate := float64(total-e.ewmaSeen) / elapsed.Seconds()
+	for i, w := range ewmaWindows {
+		decay := math.Exp(-elapsed.Seconds() / w.window.Seconds())
+		e.ewma[i] = decay*e.ewma[i] + (1-decay)*rate
+	}
+	e.ewmaSeen = total
+	e.ewmaLast = now
 }
 
 // Rate returns the rate over the internal sliding window
@@ -147,9 +242,27 @@ func (e *Meter) Total() float64 {
 
 // Stats implements the StatSource interface
 func (e *Meter) Stats(cb func(key SeriesKey, field string, val float64)) {
-	rate, total := e.stats(monotime.Now())
+	rate, total, ewma := e.allStats(monotime.Now())
 	cb(e.key, "rate", rate)
 	cb(e.key, "total", float64(total))
+	for i, w := range ewmaWindows {
+		cb(e.key, w.field, ewma[i])
+	}
+}
+
+// DescribeStats implements the StatDescriber interface. The unit given to
+// Describe is the unit of the marked amounts.
+func (e *Meter) DescribeStats(cb func(key SeriesKey, field string, info StatInfo)) {
+	help, unit := e.get()
+	cb(e.key, "rate", describeField(StatKindGauge, help, perSecond(unit),
+		"events per second over the sliding window"))
+	cb(e.key, "total", describeField(StatKindCounter, help, unit,
+		"total number of events"))
+	for _, w := range ewmaWindows {
+		cb(e.key, w.field, describeField(StatKindGauge, help, perSecond(unit),
+			fmt.Sprintf("exponentially weighted moving average of events per second over %v",
+				w.window)))
+	}
 }
 
 // DiffMeter is a StatSource that shows the difference between
@@ -180,31 +293,87 @@ func (m *DiffMeter) Stats(cb func(key SeriesKey, field string, val float64)) {
 	cb(m.key, "total", float64(total1-total2))
 }
 
+// DescribeStats implements the StatDescriber interface.
+func (m *DiffMeter) DescribeStats(cb func(key SeriesKey, field string, info StatInfo)) {
+	cb(m.key, "rate", describeField(StatKindGauge, "", "",
+		"difference between the event rates of two meters"))
+	cb(m.key, "total", describeField(StatKindGauge, "", "",
+		"difference between the event totals of two meters"))
+}
+
+// perSecond returns the unit of a rate of values with the given unit.
+func perSecond(unit string) string {
+	if unit == "" {
+		return ""
+	}
+	return unit + "_per_second"
+}
+
+// ticker advances the windows of all Meters with the same tick duration. Its
+// goroutine only runs while Meters are registered.
 type ticker struct {
-	mtx     sync.Mutex
-	started bool
-	meters  []*Meter
+	interval time.Duration
+	mtx      sync.Mutex
+	meters   map[*Meter]struct{}
+	stop     chan struct{}
+}
+
+// tickerFor returns the shared ticker for the given tick duration.
+func tickerFor(interval time.Duration) *ticker {
+	tickersMtx.Lock()
+	defer tickersMtx.Unlock()
+	t, exists := tickers[interval]
+	if !exists {
+		t = &ticker{interval: interval, meters: map[*Meter]struct{}{}}
+		tickers[interval] = t
+	}
+	return t
 }
 
 func (t *ticker) register(m *Meter) {
 	t.mtx.Lock()
-	if !t.started {
-		t.started = true
-		go t.run()
+	t.meters[m] = struct{}{}
+	if t.stop == nil {
+		t.stop = make(chan struct{})
+		go t.run(t.stop)
+	}
+	t.mtx.Unlock()
+}
+
+// unregister stops ticking m, so that it can be garbage collected. The
+// ticker's goroutine exits once no Meters are left.
+func (t *ticker) unregister(m *Meter) {
+	t.mtx.Lock()
+	delete(t.meters, m)
+	if len(t.meters) == 0 && t.stop != nil {
+		close(t.stop)
+		t.stop = nil
 	}
-	t.meters = append(t.meters, m)
 	t.mtx.Unlock()
 }
 
-func (t *ticker) run() {
+func (t *ticker) run(stop <-chan struct{}) {
+	tick := time.NewTicker(t.interval)
+	defer tick.Stop()
 	for {
-		time.Sleep(timePerTick)
-		t.mtx.Lock()
-		meters := t.meters // this is safe since we only use append
-		t.mtx.Unlock()
-		now := monotime.Now()
-		for _, m := range meters {
-			m.tick(now)
+		select {
+		case <-stop:
+			return
+		case <-tick.C:
+			t.tickAll(monotime.Now())
 		}
 	}
 }
+
+// tickAll advances the windows of all registered Meters.
+func (t *ticker) tickAll(now time.Time) {
+	t.mtx.Lock()
+	meters := make([]*Meter, 0, len(t.meters))
+	for m := range t.meters {
+		meters = append(meters, m)
+	}
+	t.mtx.Unlock()
+	for _, m := range meters {
+		m.tick(now)
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monkit
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestMeterWindow(t *testing.T) {
+	window := MeterWindow{Tick: time.Hour + time.Second, Ticks: 2}
+	m := NewMeterWithWindow(NewSeriesKey("meter"), window)
+	defer m.ticker.unregister(m)
+
+	start := m.slices[0].start
+	m.Mark(10)
+	m.ticker.tickAll(start.Add(10 * time.Second))
+	m.Mark(10)
+	m.ticker.tickAll(start.Add(20 * time.Second))
+	m.Mark(10)
+
+	// the first bucket fell out of the window.
+	rate, total := m.stats(start.Add(30 * time.Second))
+	if total != 30 || rate != 1 {
+		t.Fatalf("got rate %v and total %v, expected 1 and 30", rate, total)
+	}
+}
+
+func TestMeterEWMA(t *testing.T) {
+	m := NewMeter(NewSeriesKey("meter"))
+	defer m.ticker.unregister(m)
+
+	start := m.ewmaLast
+	m.Mark(600)
+	_, _, ewma := m.allStats(start.Add(time.Minute))
+	for i, w := range ewmaWindows {
+		expected := 10 * (1 - math.Exp(-time.Minute.Seconds()/w.window.Seconds()))
+		if math.Abs(ewma[i]-expected) > 1e-9 {
+			t.Errorf("%s: got %v, expected %v", w.field, ewma[i], expected)
+		}
+	}
+
+	// without events, the averages decay.
+	_, _, decayed := m.allStats(start.Add(2 * time.Minute))
+	if expected := ewma[0] * math.Exp(-1); math.Abs(decayed[0]-expected) > 1e-9 {
+		t.Errorf("got decayed rate_1m %v, expected %v", decayed[0], expected)
+	}
+
+	// setting the total is not counted as events.
+	m.SetTotal(1e6)
+	_, _, set := m.allStats(start.Add(3 * time.Minute))
+	if set[0] > decayed[0] {
+		t.Errorf("rate_1m increased from %v to %v after SetTotal", decayed[0], set[0])
+	}
+}
+
+func TestTickerStops(t *testing.T) {
+	m := NewMeterWithWindow(NewSeriesKey("meter"), MeterWindow{Tick: time.Hour + 2*time.Second, Ticks: 1})
+	m.ticker.mtx.Lock()
+	running := m.ticker.stop != nil
+	m.ticker.mtx.Unlock()
+	if !running {
+		t.Fatal("expected the ticker to run")
+	}
+	m.ticker.unregister(m)
+	m.ticker.mtx.Lock()
+	running = m.ticker.stop != nil
+	m.ticker.mtx.Unlock()
+	if running {
+		t.Fatal("expected the ticker to stop")
+	}
+}
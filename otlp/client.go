@@ -0,0 +1,92 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Encoding is how a Client encodes requests.
+type Encoding int
+
+const (
+	// Protobuf encodes requests in the protobuf wire format.
+	Protobuf Encoding = iota
+	// JSON encodes requests in OTLP/JSON.
+	JSON
+)
+
+// Client pushes requests to an OTLP/HTTP receiver, such as an OpenTelemetry
+// Collector.
+type Client struct {
+	// Endpoint is the base URL of the receiver, such as
+	// "http://localhost:4318". Metrics are posted to Endpoint + "/v1/metrics"
+	// and traces to Endpoint + "/v1/traces".
+	Endpoint string
+	// Encoding is the encoding of the requests. Defaults to Protobuf.
+	Encoding Encoding
+	// Header is added to the requests, such as for authentication.
+	Header http.Header
+	// HTTPClient is used for the requests, http.DefaultClient if nil.
+	HTTPClient *http.Client
+}
+
+func (c *Client) post(ctx context.Context, path string, msg protoMessage) error {
+	var body []byte
+	var contentType string
+	switch c.Encoding {
+	case Protobuf:
+		body, contentType = msg.appendProto(nil), "application/x-protobuf"
+	case JSON:
+		var err error
+		if body, err = json.Marshal(msg); err != nil {
+			return err
+		}
+		contentType = "application/json"
+	default:
+		return fmt.Errorf("otlp: unknown encoding %d", c.Encoding)
+	}
+
+	url := strings.TrimSuffix(c.Endpoint, "/") + path
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for key, values := range c.Header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<20))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("otlp: post %s: %s", url, resp.Status)
+	}
+	return nil
+}
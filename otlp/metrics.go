@@ -0,0 +1,382 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlp
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/spacemonkeygo/monkit/v3"
+	"github.com/spacemonkeygo/monkit/v3/report"
+)
+
+// scopeName is the instrumentation scope of exported metrics and spans.
+const scopeName = "github.com/spacemonkeygo/monkit/v3"
+
+// MetricsExporter converts monkit statistics into OTLP metrics and pushes
+// them with a Client. It implements report.Sink:
+//
+//	exporter := otlp.NewMetricsExporter(monkit.Default, &otlp.Client{
+//	  Endpoint: "http://localhost:4318",
+//	})
+//	rep := report.NewReporter(monkit.Default, exporter, report.Options{})
+//
+// Fields of kind monkit.StatKindCounter, such as the totals of Meters, become
+// cumulative monotonic Sums, fields of kind monkit.StatKindHistogram
+// cumulative Sums, and all other fields Gauges, named after their measurement
+// and field, such as "function.total". Distributions become a Summary named after their
+// measurement, with the count, sum and quantile fields, or an
+// ExponentialHistogram if they keep a Sketch (see monkit.SketchSource). The
+// Registry's tags are sent as resource attributes rather than with every
+// data point.
+//
+// The start time of a cumulative data point is the time of the collection
+// before its series was first sent, as its value may have grown since then.
+// A value that is lower than the previous one of its series was reset, such
+// as by FuncStats.Reset or by expiring, and starts at the time of the
+// previous one.
+type MetricsExporter struct {
+	r       *monkit.Registry
+	client  *Client
+	created time.Time
+
+	mtx     sync.Mutex
+	prev    time.Time // time of the collection before last
+	last    time.Time // time of the latest collection
+	expired time.Time // when the starts were last expired
+	starts  map[string]*seriesStart
+}
+
+// seriesStart is when the cumulative value of a series started.
+type seriesStart struct {
+	start time.Time
+	value float64
+	at    time.Time // time of the latest value
+}
+
+// startExpiry is how long the start of a series is kept after its latest
+// value.
+const startExpiry = time.Hour
+
+// NewMetricsExporter creates a MetricsExporter for the statistics of r.
+func NewMetricsExporter(r *monkit.Registry, client *Client) *MetricsExporter {
+	now := time.Now()
+	return &MetricsExporter{
+		r:       r,
+		client:  client,
+		created: now,
+		prev:    now,
+		last:    now,
+		expired: now,
+		starts:  map[string]*seriesStart{},
+	}
+}
+
+// Send implements report.Sink. The Sketches of distributions are read from the
+// Registry as of the call.
+func (e *MetricsExporter) Send(ctx context.Context, points []report.Point) error {
+	return e.client.post(ctx, "/v1/metrics", e.request(points))
+}
+
+// series is the points of one series collected at the same time.
+type series struct {
+	key    monkit.SeriesKey
+	points []report.Point
+}
+
+func (e *MetricsExporter) request(points []report.Point) *exportMetricsRequest {
+	resourceTags := map[string]string{}
+	res := resource{}
+	for _, tag := range e.r.Tags() {
+		resourceTags[tag.Key] = tag.Val
+		res.Attributes = append(res.Attributes, stringAttr(tag.Key, tag.Val))
+	}
+
+	var sketches map[string]*monkit.Sketch
+	var all []*series
+	byID := map[string]*series{}
+	for _, p := range points {
+		if math.IsNaN(p.Value) || math.IsInf(p.Value, 0) {
+			continue
+		}
+		id := p.Key.String() + " " + p.Time.String()
+		s, ok := byID[id]
+		if !ok {
+			s = &series{key: p.Key}
+			all = append(all, s)
+			byID[id] = s
+		}
+		s.points = append(s.points, p)
+
+		if p.Info.Kind == monkit.StatKindQuantile && sketches == nil {
+			sketches = map[string]*monkit.Sketch{}
+			e.r.Sketches(func(key monkit.SeriesKey, sketch *monkit.Sketch) {
+				sketches[key.String()] = sketch
+			})
+		}
+	}
+
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	e.expireLocked()
+
+	b := metricsBuilder{start: e.startLocked, byName: map[string]*metric{}}
+	for _, s := range all {
+		attrs := attributes(s.key, resourceTags)
+		rest := b.distribution(s, attrs, sketches[s.key.String()])
+		for _, p := range rest {
+			b.number(p, attrs)
+		}
+	}
+
+	return &exportMetricsRequest{ResourceMetrics: []resourceMetrics{{
+		Resource: res,
+		ScopeMetrics: []scopeMetrics{{
+			Scope:   instrumentationScope{Name: scopeName},
+			Metrics: b.metrics,
+		}},
+	}}}
+}
+
+// startLocked returns the start time of the cumulative value of the series
+// and field with the given id, at the given time. e.mtx must be held.
+func (e *MetricsExporter) startLocked(id string, value float64, at time.Time) time.Time {
+	if at.After(e.last) {
+		e.prev, e.last = e.last, at
+	}
+	s, ok := e.starts[id]
+	switch {
+	case !ok:
+		s = &seriesStart{start: e.created}
+		if e.prev.Before(at) {
+			s.start = e.prev
+		}
+		e.starts[id] = s
+	case value < s.value:
+		s.start = s.at
+	}
+	s.value, s.at = value, at
+	return s.start
+}
+
+// expireLocked forgets the starts of series without values for startExpiry.
+// It only looks for them once per startExpiry. e.mtx must be held.
+func (e *MetricsExporter) expireLocked() {
+	if e.last.Sub(e.expired) < startExpiry {
+		return
+	}
+	e.expired = e.last
+	for id, s := range e.starts {
+		if e.last.Sub(s.at) >= startExpiry {
+			delete(e.starts, id)
+		}
+	}
+}
+
+// attributes returns the tags of key as attributes, leaving out the ones that
+// are resource attributes.
+func attributes(key monkit.SeriesKey, resourceTags map[string]string) []keyValue {
+	all := key.Tags.All()
+	keys := make([]string, 0, len(all))
+	for k, v := range all {
+		if rv, ok := resourceTags[k]; !ok || rv != v {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	attrs := make([]keyValue, 0, len(keys))
+	for _, k := range keys {
+		attrs = append(attrs, stringAttr(k, all[k]))
+	}
+	return attrs
+}
+
+// metricsBuilder collects data points into metrics, one per name and type.
+type metricsBuilder struct {
+	// start returns the start time of a cumulative value, see
+	// MetricsExporter.startLocked.
+	start   func(id string, value float64, at time.Time) time.Time
+	metrics []*metric
+	byName  map[string]*metric
+}
+
+func (b *metricsBuilder) metric(name, typ string, info monkit.StatInfo, init func(m *metric)) *metric {
+	id := typ + " " + name
+	m, ok := b.byName[id]
+	if !ok {
+		m = &metric{Name: name, Description: info.Help, Unit: ucum(info.Unit)}
+		init(m)
+		b.metrics = append(b.metrics, m)
+		b.byName[id] = m
+	}
+	return m
+}
+
+// number adds p as a data point of a Sum or a Gauge.
+func (b *metricsBuilder) number(p report.Point, attrs []keyValue) {
+	name := p.Key.Measurement + "." + p.Field
+	dp := numberDataPoint{Attributes: attrs, TimeUnixNano: nanos(p.Time), AsDouble: p.Value}
+	switch p.Info.Kind {
+	case monkit.StatKindCounter, monkit.StatKindHistogram:
+		// a histogram's sum can decrease.
+		m := b.metric(name, "sum", p.Info, func(m *metric) {
+			m.Sum = &sum{AggregationTemporality: aggregationTemporalityCumulative,
+				IsMonotonic: p.Info.Kind == monkit.StatKindCounter}
+		})
+		dp.StartTimeUnixNano = nanos(b.start(p.Key.String()+" "+p.Field, p.Value, p.Time))
+		m.Sum.DataPoints = append(m.Sum.DataPoints, dp)
+	default:
+		m := b.metric(name, "gauge", p.Info, func(m *metric) { m.Gauge = &gauge{} })
+		m.Gauge.DataPoints = append(m.Gauge.DataPoints, dp)
+	}
+}
+
+// distribution adds the series as a Summary, or an ExponentialHistogram if
+// sketch is not nil, if it has quantile fields. It returns the points that
+// are not part of the distribution.
+func (b *metricsBuilder) distribution(s *series, attrs []keyValue, sketch *monkit.Sketch) (rest []report.Point) {
+	var count, total float64
+	var quantiles []valueAtQuantile
+	var unit string
+	for _, p := range s.points {
+		switch {
+		case p.Info.Kind == monkit.StatKindQuantile:
+			quantiles = append(quantiles, valueAtQuantile{Quantile: p.Info.Quantile, Value: p.Value})
+			unit = p.Info.Unit
+		case p.Field == "count":
+			count = p.Value
+		case p.Field == "sum":
+			total = p.Value
+		default:
+			rest = append(rest, p)
+		}
+	}
+	if len(quantiles) == 0 {
+		return s.points
+	}
+	// the help of the fields describes the fields, not the distribution.
+	info := monkit.StatInfo{Unit: unit}
+	when := s.points[0].Time
+	start := b.start(s.key.String(), count, when)
+
+	if sketch != nil {
+		m := b.metric(s.key.Measurement, "histogram", info, func(m *metric) {
+			m.ExponentialHistogram = &exponentialHistogram{AggregationTemporality: aggregationTemporalityCumulative}
+		})
+		dp := sketchDataPoint(sketch)
+		dp.Attributes, dp.StartTimeUnixNano, dp.TimeUnixNano = attrs, nanos(start), nanos(when)
+		dp.Sum = &total
+		m.ExponentialHistogram.DataPoints = append(m.ExponentialHistogram.DataPoints, dp)
+		return rest
+	}
+
+	sort.Slice(quantiles, func(i, j int) bool { return quantiles[i].Quantile < quantiles[j].Quantile })
+	m := b.metric(s.key.Measurement, "summary", info, func(m *metric) { m.Summary = &summary{} })
+	m.Summary.DataPoints = append(m.Summary.DataPoints, summaryDataPoint{
+		Attributes:        attrs,
+		StartTimeUnixNano: nanos(start),
+		TimeUnixNano:      nanos(when),
+		Count:             fixed64(count),
+		Sum:               total,
+		QuantileValues:    quantiles,
+	})
+	return rest
+}
+
+// sketchDataPoint converts sketch into an exponential histogram with the
+// highest scale whose buckets are at least as wide as the Sketch's bins, so
+// that every bin lands in a single bucket or two adjacent ones. The count of
+// each bin is added to the bucket its midpoint falls in.
+func sketchDataPoint(sketch *monkit.Sketch) exponentialHistogramDataPoint {
+	alpha := sketch.RelativeAccuracy()
+	gamma := (1 + alpha) / (1 - alpha)
+	scale := int32(math.Floor(-math.Log2(math.Log2(gamma))))
+	if scale > 20 {
+		scale = 20
+	}
+	if scale < -10 {
+		scale = -10
+	}
+	scaleFactor := math.Ldexp(1, int(scale))
+
+	dp := exponentialHistogramDataPoint{Scale: scale, Count: fixed64(sketch.Count())}
+	min, max := sketch.Min(), sketch.Max()
+	dp.Min, dp.Max = &min, &max
+
+	pos, neg := map[int32]uint64{}, map[int32]uint64{}
+	sketch.Buckets(func(lower, upper float64, count int64) {
+		mid := math.Sqrt(math.Abs(lower * upper))
+		switch {
+		case lower < 0 && upper > 0:
+			dp.ZeroCount += fixed64(count)
+			dp.ZeroThreshold = upper
+		case upper <= 0:
+			neg[bucketIndex(mid, scaleFactor)] += uint64(count)
+		default:
+			pos[bucketIndex(mid, scaleFactor)] += uint64(count)
+		}
+	})
+	dp.Positive = denseBuckets(pos)
+	dp.Negative = denseBuckets(neg)
+	return dp
+}
+
+// bucketIndex returns the index of the bucket of the exponential histogram
+// that holds v, where bucket i holds values in (base^i, base^(i+1)] and
+// base = 2^(1/scaleFactor).
+func bucketIndex(v, scaleFactor float64) int32 {
+	return int32(math.Ceil(math.Log2(v)*scaleFactor)) - 1
+}
+
+func denseBuckets(counts map[int32]uint64) buckets {
+	if len(counts) == 0 {
+		return buckets{}
+	}
+	lo, hi := int32(math.MaxInt32), int32(math.MinInt32)
+	for i := range counts {
+		if i < lo {
+			lo = i
+		}
+		if i > hi {
+			hi = i
+		}
+	}
+	bk := buckets{Offset: lo, BucketCounts: make(uint64s, hi-lo+1)}
+	for i, count := range counts {
+		bk.BucketCounts[i-lo] = count
+	}
+	return bk
+}
+
+// ucum returns the UCUM unit of a monkit unit.
+func ucum(u string) string {
+	switch u {
+	case monkit.UnitSeconds:
+		return "s"
+	case monkit.UnitBytes:
+		return "By"
+	}
+	return u
+}
+
+func nanos(t time.Time) fixed64 {
+	if t.IsZero() {
+		return 0
+	}
+	return fixed64(t.UnixNano())
+}
@@ -0,0 +1,242 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlp
+
+// The OTLP messages of metrics.
+
+type exportMetricsRequest struct {
+	ResourceMetrics []resourceMetrics `json:"resourceMetrics"`
+}
+
+func (r *exportMetricsRequest) appendProto(b []byte) []byte {
+	for i := range r.ResourceMetrics {
+		b = appendMessageField(b, 1, &r.ResourceMetrics[i])
+	}
+	return b
+}
+
+type resourceMetrics struct {
+	Resource     resource       `json:"resource"`
+	ScopeMetrics []scopeMetrics `json:"scopeMetrics"`
+}
+
+func (r *resourceMetrics) appendProto(b []byte) []byte {
+	b = appendMessageField(b, 1, &r.Resource)
+	for i := range r.ScopeMetrics {
+		b = appendMessageField(b, 2, &r.ScopeMetrics[i])
+	}
+	return b
+}
+
+type scopeMetrics struct {
+	Scope   instrumentationScope `json:"scope"`
+	Metrics []*metric            `json:"metrics"`
+}
+
+func (s *scopeMetrics) appendProto(b []byte) []byte {
+	b = appendMessageField(b, 1, &s.Scope)
+	for _, m := range s.Metrics {
+		b = appendMessageField(b, 2, m)
+	}
+	return b
+}
+
+// metric has one of Gauge, Sum, ExponentialHistogram and Summary set.
+type metric struct {
+	Name                 string                `json:"name"`
+	Description          string                `json:"description,omitempty"`
+	Unit                 string                `json:"unit,omitempty"`
+	Gauge                *gauge                `json:"gauge,omitempty"`
+	Sum                  *sum                  `json:"sum,omitempty"`
+	ExponentialHistogram *exponentialHistogram `json:"exponentialHistogram,omitempty"`
+	Summary              *summary              `json:"summary,omitempty"`
+}
+
+func (m *metric) appendProto(b []byte) []byte {
+	b = appendStringField(b, 1, m.Name)
+	b = appendStringField(b, 2, m.Description)
+	b = appendStringField(b, 3, m.Unit)
+	switch {
+	case m.Gauge != nil:
+		b = appendMessageField(b, 5, m.Gauge)
+	case m.Sum != nil:
+		b = appendMessageField(b, 7, m.Sum)
+	case m.ExponentialHistogram != nil:
+		b = appendMessageField(b, 10, m.ExponentialHistogram)
+	case m.Summary != nil:
+		b = appendMessageField(b, 11, m.Summary)
+	}
+	return b
+}
+
+// aggregationTemporalityCumulative is the temporality of all monkit
+// statistics, which are totals since they were created.
+const aggregationTemporalityCumulative = 2
+
+type gauge struct {
+	DataPoints []numberDataPoint `json:"dataPoints"`
+}
+
+func (g *gauge) appendProto(b []byte) []byte {
+	for i := range g.DataPoints {
+		b = appendMessageField(b, 1, &g.DataPoints[i])
+	}
+	return b
+}
+
+type sum struct {
+	DataPoints             []numberDataPoint `json:"dataPoints"`
+	AggregationTemporality int               `json:"aggregationTemporality"`
+	IsMonotonic            bool              `json:"isMonotonic"`
+}
+
+func (s *sum) appendProto(b []byte) []byte {
+	for i := range s.DataPoints {
+		b = appendMessageField(b, 1, &s.DataPoints[i])
+	}
+	b = appendVarintField(b, 2, int64(s.AggregationTemporality))
+	return appendBoolField(b, 3, s.IsMonotonic)
+}
+
+type numberDataPoint struct {
+	Attributes        []keyValue `json:"attributes,omitempty"`
+	StartTimeUnixNano fixed64    `json:"startTimeUnixNano,omitempty"`
+	TimeUnixNano      fixed64    `json:"timeUnixNano"`
+	AsDouble          float64    `json:"asDouble"`
+}
+
+func (p *numberDataPoint) appendProto(b []byte) []byte {
+	b = appendFixed64Field(b, 2, uint64(p.StartTimeUnixNano))
+	b = appendFixed64Field(b, 3, uint64(p.TimeUnixNano))
+	b = appendDoubleField(b, 4, p.AsDouble)
+	for i := range p.Attributes {
+		b = appendMessageField(b, 7, &p.Attributes[i])
+	}
+	return b
+}
+
+type summary struct {
+	DataPoints []summaryDataPoint `json:"dataPoints"`
+}
+
+func (s *summary) appendProto(b []byte) []byte {
+	for i := range s.DataPoints {
+		b = appendMessageField(b, 1, &s.DataPoints[i])
+	}
+	return b
+}
+
+type summaryDataPoint struct {
+	Attributes        []keyValue        `json:"attributes,omitempty"`
+	StartTimeUnixNano fixed64           `json:"startTimeUnixNano,omitempty"`
+	TimeUnixNano      fixed64           `json:"timeUnixNano"`
+	Count             fixed64           `json:"count"`
+	Sum               float64           `json:"sum"`
+	QuantileValues    []valueAtQuantile `json:"quantileValues,omitempty"`
+}
+
+func (p *summaryDataPoint) appendProto(b []byte) []byte {
+	b = appendFixed64Field(b, 2, uint64(p.StartTimeUnixNano))
+	b = appendFixed64Field(b, 3, uint64(p.TimeUnixNano))
+	b = appendFixed64Field(b, 4, uint64(p.Count))
+	if p.Sum != 0 {
+		b = appendDoubleField(b, 5, p.Sum)
+	}
+	for i := range p.QuantileValues {
+		b = appendMessageField(b, 6, &p.QuantileValues[i])
+	}
+	for i := range p.Attributes {
+		b = appendMessageField(b, 7, &p.Attributes[i])
+	}
+	return b
+}
+
+type valueAtQuantile struct {
+	Quantile float64 `json:"quantile"`
+	Value    float64 `json:"value"`
+}
+
+func (v *valueAtQuantile) appendProto(b []byte) []byte {
+	if v.Quantile != 0 {
+		b = appendDoubleField(b, 1, v.Quantile)
+	}
+	if v.Value != 0 {
+		b = appendDoubleField(b, 2, v.Value)
+	}
+	return b
+}
+
+type exponentialHistogram struct {
+	DataPoints             []exponentialHistogramDataPoint `json:"dataPoints"`
+	AggregationTemporality int                             `json:"aggregationTemporality"`
+}
+
+func (h *exponentialHistogram) appendProto(b []byte) []byte {
+	for i := range h.DataPoints {
+		b = appendMessageField(b, 1, &h.DataPoints[i])
+	}
+	return appendVarintField(b, 2, int64(h.AggregationTemporality))
+}
+
+type exponentialHistogramDataPoint struct {
+	Attributes        []keyValue `json:"attributes,omitempty"`
+	StartTimeUnixNano fixed64    `json:"startTimeUnixNano,omitempty"`
+	TimeUnixNano      fixed64    `json:"timeUnixNano"`
+	Count             fixed64    `json:"count"`
+	Sum               *float64   `json:"sum,omitempty"`
+	Scale             int32      `json:"scale"`
+	ZeroCount         fixed64    `json:"zeroCount"`
+	Positive          buckets    `json:"positive"`
+	Negative          buckets    `json:"negative"`
+	Min               *float64   `json:"min,omitempty"`
+	Max               *float64   `json:"max,omitempty"`
+	ZeroThreshold     float64    `json:"zeroThreshold,omitempty"`
+}
+
+func (p *exponentialHistogramDataPoint) appendProto(b []byte) []byte {
+	for i := range p.Attributes {
+		b = appendMessageField(b, 1, &p.Attributes[i])
+	}
+	b = appendFixed64Field(b, 2, uint64(p.StartTimeUnixNano))
+	b = appendFixed64Field(b, 3, uint64(p.TimeUnixNano))
+	b = appendFixed64Field(b, 4, uint64(p.Count))
+	if p.Sum != nil {
+		b = appendDoubleField(b, 5, *p.Sum)
+	}
+	b = appendSint32Field(b, 6, p.Scale)
+	b = appendFixed64Field(b, 7, uint64(p.ZeroCount))
+	b = appendMessageField(b, 8, &p.Positive)
+	b = appendMessageField(b, 9, &p.Negative)
+	if p.Min != nil {
+		b = appendDoubleField(b, 12, *p.Min)
+	}
+	if p.Max != nil {
+		b = appendDoubleField(b, 13, *p.Max)
+	}
+	if p.ZeroThreshold != 0 {
+		b = appendDoubleField(b, 14, p.ZeroThreshold)
+	}
+	return b
+}
+
+type buckets struct {
+	Offset       int32   `json:"offset"`
+	BucketCounts uint64s `json:"bucketCounts"`
+}
+
+func (bk *buckets) appendProto(b []byte) []byte {
+	b = appendSint32Field(b, 1, bk.Offset)
+	return appendPackedUint64s(b, 2, bk.BucketCounts)
+}
@@ -0,0 +1,115 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlp
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+)
+
+// The OTLP messages shared by metrics and traces, with their protobuf field
+// numbers and their OTLP/JSON field names.
+
+// keyValue is an attribute.
+type keyValue struct {
+	Key   string   `json:"key"`
+	Value anyValue `json:"value"`
+}
+
+func (kv *keyValue) appendProto(b []byte) []byte {
+	b = appendStringField(b, 1, kv.Key)
+	return appendMessageField(b, 2, &kv.Value)
+}
+
+// anyValue is the value of an attribute. One of its fields is set.
+type anyValue struct {
+	StringValue *string `json:"stringValue,omitempty"`
+	BoolValue   *bool   `json:"boolValue,omitempty"`
+}
+
+func (v *anyValue) appendProto(b []byte) []byte {
+	switch {
+	case v.StringValue != nil:
+		// written even if empty, as the field of a oneof.
+		b = appendTag(b, 1, wireBytes)
+		b = binary.AppendUvarint(b, uint64(len(*v.StringValue)))
+		return append(b, *v.StringValue...)
+	case v.BoolValue != nil:
+		b = appendTag(b, 2, wireVarint)
+		if *v.BoolValue {
+			return append(b, 1)
+		}
+		return append(b, 0)
+	}
+	return b
+}
+
+func stringAttr(key, value string) keyValue {
+	return keyValue{Key: key, Value: anyValue{StringValue: &value}}
+}
+
+func boolAttr(key string, value bool) keyValue {
+	return keyValue{Key: key, Value: anyValue{BoolValue: &value}}
+}
+
+type resource struct {
+	Attributes []keyValue `json:"attributes"`
+}
+
+func (r *resource) appendProto(b []byte) []byte {
+	for i := range r.Attributes {
+		b = appendMessageField(b, 1, &r.Attributes[i])
+	}
+	return b
+}
+
+type instrumentationScope struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+func (s *instrumentationScope) appendProto(b []byte) []byte {
+	b = appendStringField(b, 1, s.Name)
+	return appendStringField(b, 2, s.Version)
+}
+
+// fixed64 is a 64 bit integer, which OTLP/JSON encodes as a decimal string.
+type fixed64 uint64
+
+func (v fixed64) MarshalJSON() ([]byte, error) {
+	return json.Marshal(strconv.FormatUint(uint64(v), 10))
+}
+
+// uint64s are repeated 64 bit integers, encoded as decimal strings in
+// OTLP/JSON.
+type uint64s []uint64
+
+func (v uint64s) MarshalJSON() ([]byte, error) {
+	strs := make([]string, len(v))
+	for i, n := range v {
+		strs[i] = strconv.FormatUint(n, 10)
+	}
+	return json.Marshal(strs)
+}
+
+// id is a trace or span id, which OTLP/JSON encodes in hex rather than
+// base64.
+type id []byte
+
+func (v id) MarshalJSON() ([]byte, error) {
+	return json.Marshal(hex.EncodeToString(v))
+}
@@ -0,0 +1,312 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlp
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/spacemonkeygo/monkit/v3"
+	"github.com/spacemonkeygo/monkit/v3/report"
+)
+
+// receiver is a local OTLP/HTTP receiver that keeps the bodies of requests.
+func receiver(t *testing.T, path string) (url string, bodies chan []byte) {
+	bodies = make(chan []byte, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != path {
+			http.NotFound(w, req)
+			return
+		}
+		body, _ := io.ReadAll(req.Body)
+		bodies <- body
+	}))
+	t.Cleanup(server.Close)
+	return server.URL, bodies
+}
+
+// decode decodes a protobuf message into its fields by number. Varints and
+// fixed values are uint64s, length delimited fields []byte.
+func decode(t *testing.T, b []byte) map[int][]interface{} {
+	t.Helper()
+	fields := map[int][]interface{}{}
+	for len(b) > 0 {
+		tag, n := binary.Uvarint(b)
+		if n <= 0 {
+			t.Fatal("bad tag")
+		}
+		b = b[n:]
+		num := int(tag >> 3)
+		switch tag & 7 {
+		case wireVarint:
+			v, n := binary.Uvarint(b)
+			if n <= 0 {
+				t.Fatal("bad varint")
+			}
+			fields[num], b = append(fields[num], v), b[n:]
+		case wireFixed64:
+			fields[num], b = append(fields[num], binary.LittleEndian.Uint64(b)), b[8:]
+		case wireFixed32:
+			fields[num], b = append(fields[num], uint64(binary.LittleEndian.Uint32(b))), b[4:]
+		case wireBytes:
+			size, n := binary.Uvarint(b)
+			if n <= 0 || int(size) > len(b)-n {
+				t.Fatal("bad length")
+			}
+			fields[num], b = append(fields[num], b[n:n+int(size)]), b[n+int(size):]
+		default:
+			t.Fatalf("bad wire type %d", tag&7)
+		}
+	}
+	return fields
+}
+
+func testRegistry() *monkit.Registry {
+	r := monkit.NewRegistry()
+	r.SetTags(monkit.NewSeriesTag("host", "a"))
+	mon := r.ScopeNamed("test")
+	mon.Meter("requests").Mark(3)
+	mon.Counter("queue").Inc(2)
+	for _, d := range []time.Duration{time.Millisecond, 10 * time.Millisecond, time.Second} {
+		mon.DurationVal("latency").Observe(d)
+		mon.DurationValSketch("sketched").Observe(d)
+	}
+	return r
+}
+
+func TestMetricsJSON(t *testing.T) {
+	url, bodies := receiver(t, "/v1/metrics")
+	r := testRegistry()
+	exporter := NewMetricsExporter(r, &Client{Endpoint: url, Encoding: JSON})
+	if err := exporter.Send(context.Background(), report.Collect(r, time.Now())); err != nil {
+		t.Fatal(err)
+	}
+
+	var req struct {
+		ResourceMetrics []struct {
+			Resource struct {
+				Attributes []keyValue
+			}
+			ScopeMetrics []struct {
+				Metrics []map[string]json.RawMessage
+			}
+		}
+	}
+	if err := json.Unmarshal(<-bodies, &req); err != nil {
+		t.Fatal(err)
+	}
+	rm := req.ResourceMetrics[0]
+	if attrs := rm.Resource.Attributes; len(attrs) != 1 || attrs[0].Key != "host" || *attrs[0].Value.StringValue != "a" {
+		t.Fatalf("got resource %+v", attrs)
+	}
+
+	metrics := map[string]map[string]json.RawMessage{}
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		var name string
+		_ = json.Unmarshal(m["name"], &name)
+		metrics[name] = m
+	}
+	check := func(name, typ, expected string) {
+		t.Helper()
+		m, ok := metrics[name]
+		if !ok {
+			t.Fatalf("no metric %s", name)
+		}
+		var data struct {
+			DataPoints []map[string]json.RawMessage
+		}
+		if err := json.Unmarshal(m[typ], &data); err != nil || len(data.DataPoints) != 1 {
+			t.Fatalf("%s is not a %s: %s", name, typ, m[typ])
+		}
+		dp := data.DataPoints[0]
+		if _, ok := dp["attributes"]; !ok {
+			t.Fatalf("%s has no attributes", name)
+		}
+		for field, value := range dp {
+			if field == "count" || field == "asDouble" {
+				if string(value) != expected {
+					t.Fatalf("%s %s: got %s, expected %s", name, field, value, expected)
+				}
+			}
+		}
+	}
+	check("requests.total", "sum", "3")
+	check("queue.value", "gauge", "2")
+	check("latency", "summary", `"3"`)
+	check("sketched", "exponentialHistogram", `"3"`)
+	if string(metrics["latency"]["unit"]) != `"s"` {
+		t.Fatalf("got unit %s", metrics["latency"]["unit"])
+	}
+	if _, ok := metrics["latency.r50"]; ok {
+		t.Fatal("quantiles exported as gauges")
+	}
+}
+
+func TestMetricsProtobuf(t *testing.T) {
+	url, bodies := receiver(t, "/v1/metrics")
+	r := testRegistry()
+	exporter := NewMetricsExporter(r, &Client{Endpoint: url})
+	if err := exporter.Send(context.Background(), report.Collect(r, time.Now())); err != nil {
+		t.Fatal(err)
+	}
+
+	req := decode(t, <-bodies)
+	rm := decode(t, req[1][0].([]byte))
+	res := decode(t, rm[1][0].([]byte))
+	host := decode(t, res[1][0].([]byte))
+	if string(host[1][0].([]byte)) != "host" {
+		t.Fatalf("got resource %v", res)
+	}
+	sm := decode(t, rm[2][0].([]byte))
+	if scope := decode(t, sm[1][0].([]byte)); string(scope[1][0].([]byte)) != scopeName {
+		t.Fatalf("got scope %v", scope)
+	}
+
+	found := map[string]bool{}
+	for _, raw := range sm[2] {
+		m := decode(t, raw.([]byte))
+		name := string(m[1][0].([]byte))
+		switch name {
+		case "requests.total":
+			s := decode(t, m[7][0].([]byte))
+			dp := decode(t, s[1][0].([]byte))
+			if math.Float64frombits(dp[4][0].(uint64)) != 3 || s[2][0].(uint64) != 2 || s[3][0].(uint64) != 1 {
+				t.Fatalf("got sum %v, data point %v", s, dp)
+			}
+		case "latency":
+			s := decode(t, m[11][0].([]byte))
+			dp := decode(t, s[1][0].([]byte))
+			if dp[4][0].(uint64) != 3 || len(dp[6]) == 0 {
+				t.Fatalf("got summary data point %v", dp)
+			}
+		case "sketched":
+			h := decode(t, m[10][0].([]byte))
+			dp := decode(t, h[1][0].([]byte))
+			pos := decode(t, dp[8][0].([]byte))
+			var total uint64
+			for counts := pos[2][0].([]byte); len(counts) > 0; {
+				v, n := binary.Uvarint(counts)
+				total, counts = total+v, counts[n:]
+			}
+			if dp[4][0].(uint64) != 3 || total != 3 || math.Float64frombits(dp[13][0].(uint64)) != 1 {
+				t.Fatalf("got histogram data point %v", dp)
+			}
+		}
+		found[name] = true
+	}
+	for _, name := range []string{"requests.total", "queue.value", "latency", "sketched"} {
+		if !found[name] {
+			t.Errorf("no metric %s", name)
+		}
+	}
+}
+
+func TestSketchDataPoint(t *testing.T) {
+	sketch := monkit.NewSketch(0.01)
+	values := []float64{-2, 0, 0.001, 0.5, 1, 1000}
+	for _, v := range values {
+		sketch.Insert(v)
+	}
+	dp := sketchDataPoint(sketch)
+	if dp.Scale != 5 || dp.Count != 6 || dp.ZeroCount != 1 {
+		t.Fatalf("got %+v", dp)
+	}
+	// every value is counted in its own bucket or, by the sketch's
+	// accuracy, a neighbouring one.
+	counts := dp.Positive.BucketCounts
+	for _, v := range values[2:] {
+		i := int(bucketIndex(v, math.Ldexp(1, int(dp.Scale))) - dp.Positive.Offset)
+		var near uint64
+		for j := i - 1; j <= i+1; j++ {
+			if j >= 0 && j < len(counts) {
+				near += counts[j]
+			}
+		}
+		if near == 0 {
+			t.Fatalf("%v not counted near bucket %d", v, i)
+		}
+	}
+	var total uint64
+	for _, count := range counts {
+		total += count
+	}
+	if total != 4 {
+		t.Fatalf("got %d positive values", total)
+	}
+	if len(dp.Negative.BucketCounts) != 1 || dp.Negative.BucketCounts[0] != 1 {
+		t.Fatalf("got negative buckets %+v", dp.Negative)
+	}
+}
+
+func TestMessageLength(t *testing.T) {
+	// lengths of more than one byte move the message.
+	long := make([]byte, 300)
+	for i := range long {
+		long[i] = 'x'
+	}
+	kv := stringAttr("key", string(long))
+	b := appendMessageField([]byte{1}, 2, &kv)
+	outer := decode(t, b[1:])
+	inner := decode(t, outer[2][0].([]byte))
+	value := decode(t, inner[2][0].([]byte))
+	if string(inner[1][0].([]byte)) != "key" || string(value[1][0].([]byte)) != string(long) {
+		t.Fatalf("got %v", inner)
+	}
+}
+
+func TestMetricsStartTime(t *testing.T) {
+	e := NewMetricsExporter(monkit.NewRegistry(), &Client{})
+	t1 := time.Now().Add(time.Minute)
+	t2, t3 := t1.Add(time.Minute), t1.Add(2*time.Minute)
+	key := monkit.NewSeriesKey("function")
+	point := func(field string, kind monkit.StatKind, val float64, at time.Time) report.Point {
+		return report.Point{Key: key, Field: field, Value: val, Time: at, Info: monkit.StatInfo{Kind: kind}}
+	}
+	// starts returns the start times of the sums of a request, and whether
+	// they are monotonic.
+	starts := func(points ...report.Point) map[string]string {
+		got := map[string]string{}
+		for _, m := range e.request(points).ResourceMetrics[0].ScopeMetrics[0].Metrics {
+			got[m.Name] = fmt.Sprint(m.Sum.DataPoints[0].StartTimeUnixNano, m.Sum.IsMonotonic)
+		}
+		return got
+	}
+	start := func(at time.Time, monotonic bool) string {
+		return fmt.Sprint(nanos(at), monotonic)
+	}
+
+	got := starts(point("total", monkit.StatKindCounter, 5, t1))
+	if got["function.total"] != start(e.created, true) {
+		t.Fatalf("got %v at first", got)
+	}
+	// new series start at the previous collection, and resets at the
+	// previous value.
+	got = starts(point("total", monkit.StatKindCounter, 7, t2), point("bucket", monkit.StatKindHistogram, 1, t2))
+	if got["function.total"] != start(e.created, true) || got["function.bucket"] != start(t1, false) {
+		t.Fatalf("got %v after a new series", got)
+	}
+	got = starts(point("total", monkit.StatKindCounter, 2, t3))
+	if got["function.total"] != start(t2, true) {
+		t.Fatalf("got %v after a reset", got)
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlp
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// The protobuf wire format, as much of it as OTLP needs. Fields with their
+// default value are left out, as proto3 does, except where noted.
+
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+// protoMessage is a message that can be encoded in the protobuf wire format.
+type protoMessage interface {
+	appendProto(b []byte) []byte
+}
+
+func appendTag(b []byte, num, wireType int) []byte {
+	return binary.AppendUvarint(b, uint64(num)<<3|uint64(wireType))
+}
+
+func appendUvarintField(b []byte, num int, v uint64) []byte {
+	if v == 0 {
+		return b
+	}
+	return binary.AppendUvarint(appendTag(b, num, wireVarint), v)
+}
+
+func appendVarintField(b []byte, num int, v int64) []byte {
+	return appendUvarintField(b, num, uint64(v))
+}
+
+func appendSint32Field(b []byte, num int, v int32) []byte {
+	return appendUvarintField(b, num, uint64(uint32(v<<1)^uint32(v>>31)))
+}
+
+func appendBoolField(b []byte, num int, v bool) []byte {
+	if !v {
+		return b
+	}
+	return appendUvarintField(b, num, 1)
+}
+
+func appendFixed64Field(b []byte, num int, v uint64) []byte {
+	if v == 0 {
+		return b
+	}
+	return binary.LittleEndian.AppendUint64(appendTag(b, num, wireFixed64), v)
+}
+
+func appendFixed32Field(b []byte, num int, v uint32) []byte {
+	if v == 0 {
+		return b
+	}
+	return binary.LittleEndian.AppendUint32(appendTag(b, num, wireFixed32), v)
+}
+
+// appendDoubleField appends v even if it is 0, for fields in a oneof or
+// marked optional, where presence matters.
+func appendDoubleField(b []byte, num int, v float64) []byte {
+	return binary.LittleEndian.AppendUint64(appendTag(b, num, wireFixed64), math.Float64bits(v))
+}
+
+func appendBytesField(b []byte, num int, v []byte) []byte {
+	if len(v) == 0 {
+		return b
+	}
+	b = binary.AppendUvarint(appendTag(b, num, wireBytes), uint64(len(v)))
+	return append(b, v...)
+}
+
+func appendStringField(b []byte, num int, v string) []byte {
+	if v == "" {
+		return b
+	}
+	b = binary.AppendUvarint(appendTag(b, num, wireBytes), uint64(len(v)))
+	return append(b, v...)
+}
+
+// appendMessageField appends m even if it is empty, as the presence of
+// message fields matters.
+func appendMessageField(b []byte, num int, m protoMessage) []byte {
+	b = appendTag(b, num, wireBytes)
+	// reserve one byte for the length, which fits most small messages, and
+	// make room for a longer length afterwards.
+	start := len(b)
+	b = append(b, 0)
+	b = m.appendProto(b)
+	size := uint64(len(b) - start - 1)
+	var length [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(length[:], size)
+	if n > 1 {
+		b = append(b, length[1:n]...)
+		copy(b[start+n:], b[start+1:len(b)-n+1])
+	}
+	copy(b[start:], length[:n])
+	return b
+}
+
+func appendPackedUint64s(b []byte, num int, vals []uint64) []byte {
+	if len(vals) == 0 {
+		return b
+	}
+	var packed []byte
+	for _, v := range vals {
+		packed = binary.AppendUvarint(packed, v)
+	}
+	return appendBytesField(b, num, packed)
+}
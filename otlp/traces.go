@@ -0,0 +1,222 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlp
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spacemonkeygo/monkit/v3"
+	"github.com/spacemonkeygo/monkit/v3/collect"
+	"github.com/spacemonkeygo/monkit/v3/internal/spanqueue"
+)
+
+// TraceOptions configure a TraceExporter. The zero value of every option
+// picks a default.
+type TraceOptions struct {
+	// ServiceName is sent as the service.name resource attribute, along with
+	// the Registry's tags.
+	ServiceName string
+	// Sample decides which traces are exported. Defaults to all of them.
+	Sample func(t *monkit.Trace) bool
+	// QueueSize is the most finished spans kept waiting to be exported.
+	// Defaults to 2048.
+	QueueSize int
+	// DropOldest makes a full queue drop its oldest spans for new ones,
+	// rather than dropping the new ones.
+	DropOldest bool
+	// BatchSize is the most spans exported in one request. Defaults to 512.
+	BatchSize int
+	// BatchTimeout is the longest a span waits for its batch to fill up
+	// before it is exported. Defaults to 5 seconds.
+	BatchTimeout time.Duration
+}
+
+// TraceExporter converts finished Spans into OTLP spans and pushes them with
+// a Client. Create one with NewTraceExporter, and call Run to export the
+// traces of its Registry.
+//
+// Spans are named after the full name of their Func, and grouped by the name
+// of their Scope as the instrumentation scope. Their args are sent as the
+// attribute "monkit.args" and their annotations as attributes of their own.
+// The Registry's constant tags are resource attributes, and the constant tags
+// of a Span's Scope that differ from them are attributes of the Span (see
+// Span.Tags), unless an annotation has the same name. Spans that fail have an error status and an "exception" event, and spans
+// that panic have an error status with the message "panic". The 64 bit ids of
+// monkit traces are the low bytes of the 128 bit OTLP trace ids.
+//
+// Spans of requests that fail are put back at the front of the queue and
+// retried with the next export, so they are only lost once the queue is full
+// and TraceOptions.DropOldest picks them to be dropped, or when Run returns.
+// Spans of a request that failed after it was received are sent twice.
+type TraceExporter struct {
+	r      *monkit.Registry
+	client *Client
+	opts   TraceOptions
+	queue  *spanqueue.Queue
+}
+
+// NewTraceExporter creates a TraceExporter for the traces of r.
+func NewTraceExporter(r *monkit.Registry, client *Client, opts TraceOptions) *TraceExporter {
+	e := &TraceExporter{r: r, client: client, opts: opts}
+	e.queue = spanqueue.New(r, r.ScopeNamed(scopeName+"/otlp"), spanqueue.Options{
+		Sample:       opts.Sample,
+		QueueSize:    opts.QueueSize,
+		DropOldest:   opts.DropOldest,
+		BatchSize:    opts.BatchSize,
+		BatchTimeout: opts.BatchTimeout,
+	}, func(ctx context.Context, batch []*collect.FinishedSpan) error {
+		return e.client.post(ctx, "/v1/traces", e.request(batch))
+	})
+	return e
+}
+
+// Run observes the Registry's new traces, picked by TraceOptions.Sample, and
+// exports their spans as they finish, until ctx is done. It then exports the
+// queued spans one last time, and spans that finish afterwards are ignored.
+// A TraceExporter can only be run once.
+func (e *TraceExporter) Run(ctx context.Context) error {
+	err := e.queue.Run(ctx)
+	if errors.Is(err, spanqueue.ErrAlreadyRun) {
+		return errors.New("otlp: trace exporter already run")
+	}
+	return err
+}
+
+// Start implements monkit.SpanCtxObserver.
+func (e *TraceExporter) Start(ctx context.Context, s *monkit.Span) context.Context {
+	return e.queue.Start(ctx, s)
+}
+
+// Finish implements monkit.SpanCtxObserver. It queues the Span to be
+// exported, or drops it or the oldest queued one if the queue is full.
+func (e *TraceExporter) Finish(ctx context.Context, s *monkit.Span, err error, panicked bool, finish time.Time) {
+	e.queue.Finish(ctx, s, err, panicked, finish)
+}
+
+// Flush exports the queued spans in batches of at most BatchSize, until the
+// queue is empty or a request fails. It returns the error of the failed
+// request, whose spans are put back into the queue.
+func (e *TraceExporter) Flush(ctx context.Context) error {
+	return e.queue.Flush(ctx)
+}
+
+func (e *TraceExporter) request(batch []*collect.FinishedSpan) *exportTraceRequest {
+	res := resource{}
+	if e.opts.ServiceName != "" {
+		res.Attributes = append(res.Attributes, stringAttr("service.name", e.opts.ServiceName))
+	}
+	resourceTags := map[string]string{}
+	for _, tag := range e.r.Tags() {
+		res.Attributes = append(res.Attributes, stringAttr(tag.Key, tag.Val))
+		resourceTags[tag.Key] = tag.Val
+	}
+
+	rs := resourceSpans{Resource: res}
+	byScope := map[string]int{}
+	for _, fs := range batch {
+		name := fs.Span.Func().Scope().Name()
+		i, ok := byScope[name]
+		if !ok {
+			i = len(rs.ScopeSpans)
+			rs.ScopeSpans = append(rs.ScopeSpans, scopeSpans{Scope: instrumentationScope{Name: name}})
+			byScope[name] = i
+		}
+		rs.ScopeSpans[i].Spans = append(rs.ScopeSpans[i].Spans, convertSpan(fs, resourceTags))
+	}
+	return &exportTraceRequest{ResourceSpans: []resourceSpans{rs}}
+}
+
+// convertSpan converts fs, leaving out the constant tags that are in
+// resourceTags with the same value.
+func convertSpan(fs *collect.FinishedSpan, resourceTags map[string]string) span {
+	s := fs.Span
+	out := span{
+		TraceID:           traceID(s.Trace().Id()),
+		SpanID:            spanID(s.Id()),
+		Name:              s.Func().FullName(),
+		Kind:              spanKindInternal,
+		StartTimeUnixNano: nanos(s.Start()),
+		EndTimeUnixNano:   nanos(fs.Finish),
+	}
+	if parent, ok := s.ParentId(); ok {
+		out.ParentSpanID = spanID(parent)
+	}
+
+	if args := s.Args(); len(args) > 0 {
+		out.Attributes = append(out.Attributes, stringAttr("monkit.args", joinArgs(args)))
+	}
+	annotations := s.Annotations()
+	annotated := make(map[string]bool, len(annotations))
+	for _, annotation := range annotations {
+		annotated[annotation.Name] = true
+	}
+	for _, tag := range s.Tags() {
+		if val, ok := resourceTags[tag.Key]; (ok && val == tag.Val) || annotated[tag.Key] {
+			continue
+		}
+		out.Attributes = append(out.Attributes, stringAttr(tag.Key, tag.Val))
+	}
+	for _, annotation := range annotations {
+		out.Attributes = append(out.Attributes, stringAttr(annotation.Name, annotation.Value))
+	}
+	if s.Orphaned() {
+		out.Attributes = append(out.Attributes, boolAttr("monkit.orphaned", true))
+	}
+
+	switch {
+	case fs.Panicked:
+		out.Status = status{Code: statusCodeError, Message: "panic"}
+	case fs.Err != nil:
+		out.Status = status{Code: statusCodeError, Message: fs.Err.Error()}
+		out.Events = append(out.Events, event{
+			TimeUnixNano: nanos(fs.Finish),
+			Name:         "exception",
+			Attributes: []keyValue{
+				stringAttr("exception.type", fmt.Sprintf("%T", fs.Err)),
+				stringAttr("exception.message", fs.Err.Error()),
+			},
+		})
+	}
+	return out
+}
+
+func joinArgs(args []string) string {
+	b := []byte{'['}
+	for i, arg := range args {
+		if i > 0 {
+			b = append(b, ", "...)
+		}
+		b = append(b, arg...)
+	}
+	return string(append(b, ']'))
+}
+
+func traceID(v int64) id {
+	b := make([]byte, 16)
+	binary.BigEndian.PutUint64(b[8:], uint64(v))
+	return b
+}
+
+func spanID(v int64) id {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, uint64(v))
+	return b
+}
+
+var _ monkit.SpanCtxObserver = (*TraceExporter)(nil)
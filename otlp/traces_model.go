@@ -0,0 +1,116 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlp
+
+// The OTLP messages of traces.
+
+type exportTraceRequest struct {
+	ResourceSpans []resourceSpans `json:"resourceSpans"`
+}
+
+func (r *exportTraceRequest) appendProto(b []byte) []byte {
+	for i := range r.ResourceSpans {
+		b = appendMessageField(b, 1, &r.ResourceSpans[i])
+	}
+	return b
+}
+
+type resourceSpans struct {
+	Resource   resource     `json:"resource"`
+	ScopeSpans []scopeSpans `json:"scopeSpans"`
+}
+
+func (r *resourceSpans) appendProto(b []byte) []byte {
+	b = appendMessageField(b, 1, &r.Resource)
+	for i := range r.ScopeSpans {
+		b = appendMessageField(b, 2, &r.ScopeSpans[i])
+	}
+	return b
+}
+
+type scopeSpans struct {
+	Scope instrumentationScope `json:"scope"`
+	Spans []span               `json:"spans"`
+}
+
+func (s *scopeSpans) appendProto(b []byte) []byte {
+	b = appendMessageField(b, 1, &s.Scope)
+	for i := range s.Spans {
+		b = appendMessageField(b, 2, &s.Spans[i])
+	}
+	return b
+}
+
+// spanKindInternal is the kind of all monkit spans, as monkit doesn't know
+// whether they serve or make remote calls.
+const spanKindInternal = 1
+
+// statusCodeError is the status code of failed spans.
+const statusCodeError = 2
+
+type span struct {
+	TraceID           id         `json:"traceId"`
+	SpanID            id         `json:"spanId"`
+	ParentSpanID      id         `json:"parentSpanId,omitempty"`
+	Name              string     `json:"name"`
+	Kind              int        `json:"kind"`
+	StartTimeUnixNano fixed64    `json:"startTimeUnixNano"`
+	EndTimeUnixNano   fixed64    `json:"endTimeUnixNano"`
+	Attributes        []keyValue `json:"attributes,omitempty"`
+	Events            []event    `json:"events,omitempty"`
+	Status            status     `json:"status"`
+}
+
+func (s *span) appendProto(b []byte) []byte {
+	b = appendBytesField(b, 1, s.TraceID)
+	b = appendBytesField(b, 2, s.SpanID)
+	b = appendBytesField(b, 4, s.ParentSpanID)
+	b = appendStringField(b, 5, s.Name)
+	b = appendVarintField(b, 6, int64(s.Kind))
+	b = appendFixed64Field(b, 7, uint64(s.StartTimeUnixNano))
+	b = appendFixed64Field(b, 8, uint64(s.EndTimeUnixNano))
+	for i := range s.Attributes {
+		b = appendMessageField(b, 9, &s.Attributes[i])
+	}
+	for i := range s.Events {
+		b = appendMessageField(b, 11, &s.Events[i])
+	}
+	return appendMessageField(b, 15, &s.Status)
+}
+
+type event struct {
+	TimeUnixNano fixed64    `json:"timeUnixNano"`
+	Name         string     `json:"name"`
+	Attributes   []keyValue `json:"attributes,omitempty"`
+}
+
+func (e *event) appendProto(b []byte) []byte {
+	b = appendFixed64Field(b, 1, uint64(e.TimeUnixNano))
+	b = appendStringField(b, 2, e.Name)
+	for i := range e.Attributes {
+		b = appendMessageField(b, 3, &e.Attributes[i])
+	}
+	return b
+}
+
+type status struct {
+	Message string `json:"message,omitempty"`
+	Code    int    `json:"code,omitempty"`
+}
+
+func (s *status) appendProto(b []byte) []byte {
+	b = appendStringField(b, 2, s.Message)
+	return appendVarintField(b, 3, int64(s.Code))
+}
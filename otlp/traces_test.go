@@ -0,0 +1,193 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/spacemonkeygo/monkit/v3"
+)
+
+type jsonSpan struct {
+	TraceID      string `json:"traceId"`
+	SpanID       string `json:"spanId"`
+	ParentSpanID string `json:"parentSpanId"`
+	Name         string
+	Attributes   []keyValue
+	Events       []struct{ Name string }
+	Status       struct {
+		Message string
+		Code    int
+	}
+	StartTimeUnixNano string
+	EndTimeUnixNano   string
+}
+
+func decodeSpans(t *testing.T, body []byte) (resourceAttrs []keyValue, spans map[string]jsonSpan) {
+	t.Helper()
+	var req struct {
+		ResourceSpans []struct {
+			Resource struct {
+				Attributes []keyValue
+			}
+			ScopeSpans []struct {
+				Scope struct{ Name string }
+				Spans []jsonSpan
+			}
+		}
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		t.Fatal(err)
+	}
+	spans = map[string]jsonSpan{}
+	for _, rs := range req.ResourceSpans {
+		resourceAttrs = rs.Resource.Attributes
+		for _, ss := range rs.ScopeSpans {
+			if ss.Scope.Name != "test" {
+				t.Fatalf("got scope %q", ss.Scope.Name)
+			}
+			for _, s := range ss.Spans {
+				spans[s.Name] = s
+			}
+		}
+	}
+	return resourceAttrs, spans
+}
+
+func attr(attrs []keyValue, key string) string {
+	for _, kv := range attrs {
+		if kv.Key == key && kv.Value.StringValue != nil {
+			return *kv.Value.StringValue
+		}
+	}
+	return ""
+}
+
+func work(mon *monkit.Scope) {
+	ctx := context.Background()
+	var err error
+	defer mon.TaskNamed("parent")(&ctx, "arg", 5)(&err)
+	monkit.SpanFromCtx(ctx).Annotate("user", "alice")
+
+	func() {
+		ctx, err := ctx, error(nil)
+		defer mon.TaskNamed("child")(&ctx)(&err)
+		err = errors.New("child failed")
+	}()
+
+	func() {
+		ctx := ctx
+		defer func() { _ = recover() }()
+		defer mon.TaskNamed("boom")(&ctx)(nil)
+		panic("boom")
+	}()
+}
+
+func TestTraceExporter(t *testing.T) {
+	url, bodies := receiver(t, "/v1/traces")
+	r := monkit.NewRegistry()
+	r.SetTags(monkit.NewSeriesTag("host", "a"), monkit.NewSeriesTag("region", "eu"))
+	mon := r.ScopeNamed("test")
+	mon.SetTags(monkit.NewSeriesTag("host", "b"), monkit.NewSeriesTag("user", "nobody"))
+	e := NewTraceExporter(r, &Client{Endpoint: url, Encoding: JSON}, TraceOptions{
+		ServiceName:  "svc",
+		BatchTimeout: 50 * time.Millisecond,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error, 1)
+	go func() { errs <- e.Run(ctx) }()
+
+	// traces are only observed once Run registered the exporter.
+	var body []byte
+	for deadline := time.Now().Add(5 * time.Second); body == nil; {
+		work(mon)
+		select {
+		case body = <-bodies:
+		case <-time.After(10 * time.Millisecond):
+			if time.Now().After(deadline) {
+				t.Fatal("nothing exported")
+			}
+		}
+	}
+	cancel()
+	if err := <-errs; err != nil {
+		t.Fatal(err)
+	}
+
+	resourceAttrs, spans := decodeSpans(t, body)
+	for len(spans) < 3 {
+		_, more := decodeSpans(t, <-bodies)
+		for name, s := range more {
+			spans[name] = s
+		}
+	}
+	if attr(resourceAttrs, "service.name") != "svc" || attr(resourceAttrs, "host") != "a" {
+		t.Fatalf("got resource %v", resourceAttrs)
+	}
+
+	parent, child, boom := spans["test.parent"], spans["test.child"], spans["test.boom"]
+	if len(parent.TraceID) != 32 || len(parent.SpanID) != 16 || parent.ParentSpanID != "" ||
+		child.TraceID != parent.TraceID || child.ParentSpanID != parent.SpanID ||
+		boom.ParentSpanID != parent.SpanID {
+		t.Fatalf("got ids %+v %+v %+v", parent, child, boom)
+	}
+	if attr(parent.Attributes, "monkit.args") != `["arg", 5]` || attr(parent.Attributes, "user") != "alice" ||
+		parent.Status.Code != 0 || parent.StartTimeUnixNano == "" || parent.EndTimeUnixNano == "" {
+		t.Fatalf("got parent %+v", parent)
+	}
+	// the scope's tags that differ from the resource's are span attributes,
+	// unless they are annotated.
+	keys := map[string]int{}
+	for _, kv := range parent.Attributes {
+		keys[kv.Key]++
+	}
+	if attr(parent.Attributes, "host") != "b" || keys["region"] != 0 || keys["user"] != 1 ||
+		attr(resourceAttrs, "region") != "eu" {
+		t.Fatalf("got parent attributes %v", parent.Attributes)
+	}
+	if child.Status.Code != statusCodeError || child.Status.Message != "child failed" ||
+		len(child.Events) != 1 || child.Events[0].Name != "exception" {
+		t.Fatalf("got child %+v", child)
+	}
+	if boom.Status.Code != statusCodeError || boom.Status.Message != "panic" {
+		t.Fatalf("got boom %+v", boom)
+	}
+
+	// a request canceled by Run may have been received before it failed, in
+	// which case its spans are sent again by the final flush.
+	for drained := false; !drained; {
+		select {
+		case <-bodies:
+		case <-time.After(50 * time.Millisecond):
+			drained = true
+		}
+	}
+
+	// spans finishing after Run returned are ignored.
+	work(mon)
+	if err := e.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case body := <-bodies:
+		t.Fatalf("got %s", body)
+	default:
+	}
+}
@@ -0,0 +1,32 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package present
+
+import (
+	"io"
+
+	"github.com/spacemonkeygo/monkit/v3"
+	"github.com/spacemonkeygo/monkit/v3/alert"
+)
+
+// AlertsJSON writes the pending and firing alerts of all alert.Engines that
+// are running for the Registry to w in a JSON format. See alert.Active.
+func AlertsJSON(r *monkit.Registry, w io.Writer) error {
+	lw := newListWriter(w)
+	for _, a := range alert.Active(r) {
+		lw.elem(a)
+	}
+	return lw.done()
+}
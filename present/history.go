@@ -0,0 +1,174 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package present
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/spacemonkeygo/monkit/v3"
+)
+
+const (
+	sparklineWidth  = 240
+	sparklineHeight = 32
+)
+
+func registryHistory(r *monkit.Registry) (*monkit.History, error) {
+	h := r.History()
+	if h == nil {
+		return nil, errNotFound.New("no history is recorded, see Registry.RecordHistory")
+	}
+	return h, nil
+}
+
+// StatsHistoryJSON writes the points of every tier of every field that
+// matcher matches in the Registry's History to w in a JSON format. Points are
+// pairs of the start of their interval in seconds since the Unix epoch and
+// the value, which is null if it is not finite. See Registry.RecordHistory.
+func StatsHistoryJSON(r *monkit.Registry, w io.Writer,
+	matcher func(key monkit.SeriesKey, field string) bool) (err error) {
+	type tier struct {
+		Interval  float64       `json:"interval"`
+		Retention float64       `json:"retention"`
+		Points    [][2]*float64 `json:"points"`
+	}
+	type entry struct {
+		Measurement string            `json:"measurement"`
+		Tags        map[string]string `json:"tags"`
+		Field       string            `json:"field"`
+		Tiers       []tier            `json:"tiers"`
+	}
+
+	h, err := registryHistory(r)
+	if err != nil {
+		return err
+	}
+	tiers := h.Tiers()
+	lw := newListWriter(w)
+	h.Series(func(key monkit.SeriesKey, field string) {
+		if !matcher(key, field) {
+			return
+		}
+		e := entry{
+			Measurement: key.Measurement,
+			Tags:        key.Tags.All(),
+			Field:       field,
+			Tiers:       make([]tier, 0, len(tiers)),
+		}
+		for i, t := range tiers {
+			points := h.Points(key, field, i)
+			out := tier{
+				Interval:  t.Interval.Seconds(),
+				Retention: t.Retention.Seconds(),
+				Points:    make([][2]*float64, 0, len(points)),
+			}
+			for _, p := range points {
+				at := float64(p.Time.Unix())
+				var val *float64
+				if value := p.Value; !math.IsNaN(value) && !math.IsInf(value, 0) {
+					val = &value
+				}
+				out.Points = append(out.Points, [2]*float64{&at, val})
+			}
+			e.Tiers = append(e.Tiers, out)
+		}
+		lw.elem(e)
+	})
+	return lw.done()
+}
+
+// StatsHistoryHTML writes an HTML page to w with a sparkline of the points in
+// the tier with the given index of every field that matcher matches in the
+// Registry's History. See Registry.RecordHistory.
+func StatsHistoryHTML(r *monkit.Registry, w io.Writer,
+	matcher func(key monkit.SeriesKey, field string) bool, tier int) (err error) {
+	h, err := registryHistory(r)
+	if err != nil {
+		return err
+	}
+	tiers := h.Tiers()
+	if tier < 0 || tier >= len(tiers) {
+		return errBadRequest.New("history has no tier %d", tier)
+	}
+
+	_, err = fmt.Fprintf(w, `<!DOCTYPE html>
+<html>
+	<head>
+		<meta charset="utf-8">
+		<title>Monkit history</title>
+	</head>
+	<body>
+		<p>A point every %v for %v.</p>
+		<table style="font-family: monospace;">
+`, tiers[tier].Interval, tiers[tier].Retention)
+	if err != nil {
+		return err
+	}
+	h.Series(func(key monkit.SeriesKey, field string) {
+		if err != nil || !matcher(key, field) {
+			return
+		}
+		points := h.Points(key, field, tier)
+		last := math.NaN()
+		if len(points) > 0 {
+			last = points[len(points)-1].Value
+		}
+		_, err = fmt.Fprintf(w, "\t\t\t<tr><td>%s</td><td>%s</td><td>%g</td></tr>\n",
+			html.EscapeString(key.WithField(field)), sparkline(points), last)
+	})
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprint(w, "\t\t</table>\n\t</body>\n</html>\n")
+	return err
+}
+
+// sparkline returns an inline SVG image of the finite values of points.
+func sparkline(points []monkit.HistoryPoint) string {
+	min, max := math.Inf(1), math.Inf(-1)
+	for _, p := range points {
+		if !math.IsNaN(p.Value) && !math.IsInf(p.Value, 0) {
+			min, max = math.Min(min, p.Value), math.Max(max, p.Value)
+		}
+	}
+
+	var coords []string
+	if len(points) > 0 && min <= max {
+		start, end := points[0].Time, points[len(points)-1].Time
+		span := end.Sub(start).Seconds()
+		for _, p := range points {
+			if math.IsNaN(p.Value) || math.IsInf(p.Value, 0) {
+				continue
+			}
+			x, y := 0.0, 0.5
+			if span > 0 {
+				x = p.Time.Sub(start).Seconds() / span
+			}
+			if max > min {
+				y = (p.Value - min) / (max - min)
+			}
+			coords = append(coords, fmt.Sprintf("%.1f,%.1f",
+				x*sparklineWidth, (1-y)*(sparklineHeight-2)+1))
+		}
+	}
+
+	return fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d">`+
+		`<polyline fill="none" stroke="steelblue" stroke-width="1" points="%s"/></svg>`,
+		sparklineWidth, sparklineHeight, strings.Join(coords, " "))
+}
@@ -0,0 +1,76 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package present
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spacemonkeygo/monkit/v3"
+)
+
+func TestStatsHistory(t *testing.T) {
+	reg := monkit.NewRegistry()
+	mon := reg.ScopeNamed("test")
+	mon.Counter("calls").Inc(1)
+
+	get := func(url string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		HTTP(reg).ServeHTTP(rec, httptest.NewRequest("GET", url, nil))
+		return rec
+	}
+	if rec := get("/stats/history"); rec.Code != http.StatusNotFound {
+		t.Fatalf("got status %d without a history", rec.Code)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	h := reg.RecordHistory(ctx, monkit.HistoryTier{Interval: time.Minute, Retention: time.Hour})
+	h.Record(time.Now())
+
+	rec := get("/stats/history?series=" + "calls.*value")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("got status %d: %s", rec.Code, rec.Body.String())
+	}
+	var entries []struct {
+		Measurement string
+		Field       string
+		Tiers       []struct {
+			Interval float64
+			Points   [][2]*float64
+		}
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &entries); err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Field != "value" || entries[0].Tiers[0].Interval != 60 ||
+		len(entries[0].Tiers[0].Points) != 1 || *entries[0].Tiers[0].Points[0][1] != 1 {
+		t.Fatalf("unexpected history: %s", rec.Body.String())
+	}
+
+	rec = get("/stats/history/html?tier=0")
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "calls,scope=test value") ||
+		!strings.Contains(rec.Body.String(), "<polyline") {
+		t.Fatalf("unexpected page: %s", rec.Body.String())
+	}
+	if rec := get("/stats/history/html?tier=1"); rec.Code != http.StatusBadRequest {
+		t.Fatalf("got status %d for a missing tier", rec.Code)
+	}
+}
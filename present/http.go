@@ -32,7 +32,8 @@ func HTTP(r *monkit.Registry) http.Handler {
 }
 
 func (h handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
-	p, contentType, err := FromRequest(h.Registry, req.URL.Path, req.URL.Query())
+	p, contentType, err := fromRequest(h.Registry, req.URL.Path, req.URL.Query(),
+		req.Header.Get("Accept"))
 	if err != nil {
 		http.Error(w, err.Error(), getStatusCode(err, 500))
 		return
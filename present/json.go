@@ -35,11 +35,12 @@ func formatSpan(s *monkit.Span) interface{} {
 		Trace struct {
 			Id int64 `json:"id"`
 		} `json:"trace"`
-		Start       int64      `json:"start"`
-		Elapsed     int64      `json:"elapsed"`
-		Orphaned    bool       `json:"orphaned"`
-		Args        []string   `json:"args"`
-		Annotations [][]string `json:"annotations"`
+		Start       int64             `json:"start"`
+		Elapsed     int64             `json:"elapsed"`
+		Orphaned    bool              `json:"orphaned"`
+		Args        []string          `json:"args"`
+		Annotations [][]string        `json:"annotations"`
+		Tags        map[string]string `json:"tags,omitempty"`
 	}{}
 
 	js.Id = s.Id()
@@ -61,6 +62,7 @@ func formatSpan(s *monkit.Span) interface{} {
 		js.Annotations = append(js.Annotations,
 			[]string{annotation.Name, annotation.Value})
 	}
+	js.Tags = spanTags(s)
 	return js
 }
 
@@ -75,13 +77,14 @@ func formatFinishedSpan(s *collect.FinishedSpan) interface{} {
 		Trace struct {
 			Id int64 `json:"id"`
 		} `json:"trace"`
-		Start       int64      `json:"start"`
-		Finish      int64      `json:"finish"`
-		Orphaned    bool       `json:"orphaned"`
-		Err         string     `json:"err"`
-		Panicked    bool       `json:"panicked"`
-		Args        []string   `json:"args"`
-		Annotations [][]string `json:"annotations"`
+		Start       int64             `json:"start"`
+		Finish      int64             `json:"finish"`
+		Orphaned    bool              `json:"orphaned"`
+		Err         string            `json:"err"`
+		Panicked    bool              `json:"panicked"`
+		Args        []string          `json:"args"`
+		Annotations [][]string        `json:"annotations"`
+		Tags        map[string]string `json:"tags,omitempty"`
 	}{}
 	js.Id = s.Span.Id()
 	if parent_id, ok := s.Span.ParentId(); ok {
@@ -107,9 +110,23 @@ func formatFinishedSpan(s *collect.FinishedSpan) interface{} {
 		js.Annotations = append(js.Annotations,
 			[]string{annotation.Name, annotation.Value})
 	}
+	js.Tags = spanTags(s.Span)
 	return js
 }
 
+// spanTags returns the constant tags of a Span, or nil if there are none.
+func spanTags(s *monkit.Span) map[string]string {
+	tags := s.Tags()
+	if len(tags) == 0 {
+		return nil
+	}
+	rv := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		rv[tag.Key] = tag.Val
+	}
+	return rv
+}
+
 type durationStats struct {
 	Average          time.Duration            `json:"average"`
 	ReservoirAverage time.Duration            `json:"reservoir_average"`
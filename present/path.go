@@ -59,10 +59,29 @@ func curry(reg *monkit.Registry,
 //   - /funcs/json         - returns the result of FuncsJSON
 //   - /stats, /stats/text - returns the result of StatsText
 //   - /stats/json         - returns the result of StatsJSON
+//   - /stats/catalog      - returns the result of StatsCatalog
+//   - /stats/cardinality  - returns the result of StatsCardinality
+//   - /stats/exemplars    - returns the result of StatsExemplars
+//   - /stats/history, /stats/history/json - returns the result of
+//     StatsHistoryJSON
+//   - /stats/history/html - returns the result of StatsHistoryHTML
+//   - /alerts             - returns the result of AlertsJSON
+//   - /slo, /slo/text     - returns the result of SLOText
+//   - /slo/json           - returns the result of SLOJSON
+//   - /metrics            - returns the result of StatsPrometheus, or of
+//     StatsOpenMetrics if the format=openmetrics query
+//     parameter is given
 //   - /trace/svg          - returns the result of TraceQuerySVG
 //   - /trace/json         - returns the result of TraceQueryJSON
+//   - /trace/zipkin       - returns the result of TraceQueryZipkin
 //   - /trace/remote       - returns trace id or redirect
 //
+// The history paths take an optional series query parameter, a regular
+// expression that selects the fields whose history is returned by matching
+// it against their series key and field name, like "function,name=work
+// successes". /stats/history/html also takes the index of the tier to show as
+// the optional tier query parameter.
+//
 // The last two paths are worth discussing in more detail, as they take
 // query parameters. All trace endpoints require at least one of the following
 // two query parameters:
@@ -83,6 +102,13 @@ func curry(reg *monkit.Registry,
 // two) to every monitored function.
 func FromRequest(reg *monkit.Registry, path string, query url.Values) (
 	f Result, contentType string, err error) {
+	return fromRequest(reg, path, query, "")
+}
+
+// fromRequest is like FromRequest, but also takes the value of the Accept
+// header to pick between formats where more than one is available.
+func fromRequest(reg *monkit.Registry, path string, query url.Values,
+	accept string) (f Result, contentType string, err error) {
 
 	defer func() {
 		if err != nil {
@@ -136,8 +162,44 @@ func FromRequest(reg *monkit.Registry, path string, query url.Values) (
 			return func(w io.Writer) error {
 				return StatsJSON(reg, w)
 			}, "application/json; charset=utf-8", nil
+		case "catalog":
+			return curry(reg, StatsCatalog), "application/json; charset=utf-8", nil
+		case "cardinality":
+			return curry(reg, StatsCardinality), "application/json; charset=utf-8", nil
+		case "exemplars":
+			return curry(reg, StatsExemplars), "application/json; charset=utf-8", nil
+		case "history":
+			return historyFromRequest(reg, rest, query)
+		}
+
+	case "alerts":
+		return curry(reg, AlertsJSON), "application/json; charset=utf-8", nil
+
+	case "slo":
+		switch second {
+		case "", "text":
+			return curry(reg, SLOText), "text/plain; charset=utf-8", nil
+		case "json":
+			return curry(reg, SLOJSON), "application/json; charset=utf-8", nil
 		}
 
+	case "metrics":
+		openMetrics := acceptsOpenMetrics(accept)
+		switch query.Get("format") {
+		case "":
+		case "prometheus":
+			openMetrics = false
+		case "openmetrics":
+			openMetrics = true
+		default:
+			return nil, "", errBadRequest.New("unknown metrics format %#v",
+				query.Get("format"))
+		}
+		if openMetrics {
+			return curry(reg, StatsOpenMetrics), openMetricsContentType, nil
+		}
+		return curry(reg, StatsPrometheus), prometheusContentType, nil
+
 	case "trace":
 		regexStr := query.Get("regex")
 		traceIdStr := query.Get("trace_id")
@@ -202,6 +264,10 @@ func FromRequest(reg *monkit.Registry, path string, query url.Values) (
 			return func(w io.Writer) error {
 				return TraceQueryJSON(reg, w, spanMatcher)
 			}, "application/json; charset=utf-8", nil
+		case "zipkin":
+			return func(w io.Writer) error {
+				return TraceQueryZipkin(reg, w, spanMatcher)
+			}, "application/json; charset=utf-8", nil
 		case "remote":
 			viz := query.Get("viz")
 			if viz != "" && (!strings.HasPrefix(viz, "http:") && !strings.HasPrefix(viz, "https:")) {
@@ -276,6 +342,48 @@ func FromRequest(reg *monkit.Registry, path string, query url.Values) (
 	return nil, "", errNotFound.New("path not found: %s", path)
 }
 
+func historyFromRequest(reg *monkit.Registry, path string, query url.Values) (
+	f Result, contentType string, err error) {
+	h, err := registryHistory(reg)
+	if err != nil {
+		return nil, "", err
+	}
+
+	matcher := func(monkit.SeriesKey, string) bool { return true }
+	if series := query.Get("series"); series != "" {
+		re, err := regexp.Compile(series)
+		if err != nil {
+			return nil, "", errBadRequest.New("invalid series regex %#v: %v", series, err)
+		}
+		matcher = func(key monkit.SeriesKey, field string) bool {
+			return re.MatchString(key.WithField(field))
+		}
+	}
+
+	_, rest := shift(path)
+	switch second, _ := shift(rest); second {
+	case "", "json":
+		return func(w io.Writer) error {
+			return StatsHistoryJSON(reg, w, matcher)
+		}, "application/json; charset=utf-8", nil
+	case "html":
+		tier := 0
+		if query.Get("tier") != "" {
+			tier, err = strconv.Atoi(query.Get("tier"))
+			if err != nil {
+				return nil, "", errBadRequest.New("invalid tier %#v: %v", query.Get("tier"), err)
+			}
+		}
+		if tier < 0 || tier >= len(h.Tiers()) {
+			return nil, "", errBadRequest.New("history has no tier %d", tier)
+		}
+		return func(w io.Writer) error {
+			return StatsHistoryHTML(reg, w, matcher, tier)
+		}, "text/html; charset=utf-8", nil
+	}
+	return nil, "", errNotFound.New("path not found: %s", path)
+}
+
 var vizRedirectHTML = template.Must(template.New("vizredirect").Parse(`
 <html><head>
 <meta http-equiv="refresh" content="0;url={{ . }}" />
@@ -314,8 +422,32 @@ func writeIndex(w io.Writer) error {
 			<dt><a href="stats/svg">/stats/svg</a></dt>
 			<dd>Statistics about all observed functions, scopes and values.</dd>
 
+			<dt><a href="stats/catalog">/stats/catalog</a></dt>
+			<dd>The kind, unit and description of every statistic.</dd>
+
+			<dt><a href="stats/cardinality">/stats/cardinality</a></dt>
+			<dd>The number of series of every measurement, and how often the cardinality limit was hit.</dd>
+
+			<dt><a href="stats/exemplars">/stats/exemplars</a></dt>
+			<dd>Recent and highest observed values of distributions, along with the trace and span they were observed in.</dd>
+
+			<dt><a href="stats/history">/stats/history</a></dt>
+			<dt><a href="stats/history/html">/stats/history/html</a></dt>
+			<dd>Recent values of every statistic, if the history is recorded with <code>Registry.RecordHistory</code>. The <code>?series=</code> query argument selects statistics by a regular expression, and <code>?tier=</code> picks the resolution of the sparklines.</dd>
+
+			<dt><a href="alerts">/alerts</a></dt>
+			<dd>Pending and firing alerts of the running alert engines.</dd>
+
+			<dt><a href="slo">/slo</a></dt>
+			<dt><a href="slo/json">/slo/json</a></dt>
+			<dd>Burn rates and remaining error budgets of the functions with service level objectives.</dd>
+
+			<dt><a href="metrics">/metrics</a></dt>
+			<dd>Statistics in the Prometheus or OpenMetrics text format, depending on the <code>Accept</code> header or the <code>?format=</code> query argument.</dd>
+
 			<dt><a href="trace/json">/trace/json</a></dt>
 			<dt><a href="trace/svg">/trace/svg</a></dt>
+			<dt><a href="trace/zipkin">/trace/zipkin</a></dt>
 			<dd>Trace the next scope that matches one of the <code>?regex=</code> or <code>?trace_id=</code> query arguments. By default, regular expressions are matched ahead of time against all known Funcs, but perhaps the Func you want to trace hasn't been observed by the process yet, in which case the regex will fail to match anything. You can turn off this preselection behavior by providing <code>&preselect=false</code> as an additional query param. Be advised that until a trace completes, whether or not it has started, it adds a small amount of overhead (a comparison or two) to every monitored function.</dd>
 		</dl>
 	</body>
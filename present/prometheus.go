@@ -0,0 +1,374 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package present
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"mime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spacemonkeygo/monkit/v3"
+)
+
+const (
+	prometheusContentType  = "text/plain; version=0.0.4; charset=utf-8"
+	openMetricsContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+)
+
+type promSample struct {
+	name     string
+	labels   string
+	group    string // labels without the histogram le label
+	value    float64
+	exemplar *monkit.Exemplar
+}
+
+type promFamily struct {
+	name    string
+	typ     string
+	help    string
+	samples []promSample
+}
+
+// StatsPrometheus writes all of the name/value statistics pairs the Registry
+// knows to w in the Prometheus text exposition format. Each field becomes its
+// own metric named after the measurement and field, and the SeriesKey tags
+// become labels. Metric types and help texts are taken from the Registry's
+// StatDescriber implementation.
+func StatsPrometheus(r *monkit.Registry, w io.Writer) error {
+	return writePrometheus(r, w, false)
+}
+
+// StatsOpenMetrics is like StatsPrometheus, but writes the OpenMetrics text
+// format instead. Counters and histogram buckets with exemplars (see
+// monkit.ExemplarSource) are annotated with the exemplar with the highest
+// value, labeled with its hex encoded trace and span ids.
+func StatsOpenMetrics(r *monkit.Registry, w io.Writer) error {
+	return writePrometheus(r, w, true)
+}
+
+func writePrometheus(r *monkit.Registry, w io.Writer, openMetrics bool) error {
+	bw := bufio.NewWriter(w)
+	for _, family := range collectPrometheus(r, openMetrics) {
+		name := family.name
+		if openMetrics && family.typ == "counter" {
+			name = strings.TrimSuffix(name, "_total")
+		}
+		if family.help != "" {
+			_, _ = bw.WriteString("# HELP ")
+			_, _ = bw.WriteString(name)
+			_ = bw.WriteByte(' ')
+			writePrometheusHelp(bw, family.help, openMetrics)
+			_ = bw.WriteByte('\n')
+		}
+		_, _ = bw.WriteString("# TYPE ")
+		_, _ = bw.WriteString(name)
+		_ = bw.WriteByte(' ')
+		_, _ = bw.WriteString(family.typ)
+		_ = bw.WriteByte('\n')
+		for _, sample := range family.samples {
+			_, _ = bw.WriteString(sample.name)
+			_, _ = bw.WriteString(sample.labels)
+			_ = bw.WriteByte(' ')
+			_, _ = bw.WriteString(formatPrometheusValue(sample.value))
+			if ex := sample.exemplar; ex != nil {
+				_, _ = fmt.Fprintf(bw, ` # {trace_id="%016x",span_id="%016x"} %s %s`,
+					uint64(ex.TraceId), uint64(ex.SpanId), formatPrometheusValue(ex.Value),
+					formatPrometheusTimestamp(ex.Time))
+			}
+			_ = bw.WriteByte('\n')
+		}
+	}
+	if openMetrics {
+		_, _ = bw.WriteString("# EOF\n")
+	}
+	return bw.Flush()
+}
+
+func collectPrometheus(r *monkit.Registry, openMetrics bool) []*promFamily {
+	var exemplars map[string]monkit.Exemplar
+	if openMetrics {
+		exemplars = collectExemplars(r)
+	}
+	families := map[string]*promFamily{}
+	histograms := map[string]bool{} // family names and groups with buckets
+	add := func(key monkit.SeriesKey, field string, val float64, info monkit.StatInfo) {
+		// histogram fields are grouped into a single family named after the
+		// measurement, so bucket, sum and count become its samples.
+		name := sanitizePrometheusName(key.Measurement + "_" + field)
+		sampleName := name
+		if info.Kind == monkit.StatKindHistogram {
+			name = sanitizePrometheusName(key.Measurement)
+		}
+		family, ok := families[name]
+		if !ok {
+			family = &promFamily{
+				name: name,
+				typ:  prometheusType(info.Kind, openMetrics),
+				help: info.Help,
+			}
+			if info.Unit != "" {
+				family.help += " (" + info.Unit + ")"
+			}
+			families[name] = family
+		}
+		if openMetrics && family.typ == "counter" && !strings.HasSuffix(name, "_total") {
+			sampleName += "_total"
+		}
+		tags := key.Tags.All()
+		sample := promSample{
+			name:   sampleName,
+			labels: prometheusLabels(tags),
+			value:  val,
+		}
+		sample.group = sample.labels
+		if family.typ == "counter" || (family.typ == "histogram" && field == "bucket") {
+			if ex, ok := exemplars[key.WithField(field)]; ok {
+				sample.exemplar = &ex
+			}
+		}
+		if _, ok := tags["le"]; ok && info.Kind == monkit.StatKindHistogram {
+			group := make(map[string]string, len(tags)-1)
+			for name, value := range tags {
+				if name != "le" {
+					group[name] = value
+				}
+			}
+			sample.group = prometheusLabels(group)
+			histograms[name+" "+sample.group] = true
+		}
+		family.samples = append(family.samples, sample)
+	}
+
+	// sums and counts belong to the histogram of their series even if they
+	// are not described as histogram fields, such as the sums of histograms,
+	// which can decrease, so they are added once the buckets are known.
+	type total struct {
+		key   monkit.SeriesKey
+		field string
+		val   float64
+		info  monkit.StatInfo
+	}
+	var totals []total
+	r.DescribedStats(func(key monkit.SeriesKey, field string, val float64, info monkit.StatInfo) {
+		if field == "sum" || field == "count" {
+			totals = append(totals, total{key: key, field: field, val: val, info: info})
+			return
+		}
+		add(key, field, val, info)
+	})
+	for _, t := range totals {
+		name := sanitizePrometheusName(t.key.Measurement)
+		if histograms[name+" "+prometheusLabels(t.key.Tags.All())] {
+			t.info.Kind = monkit.StatKindHistogram
+		}
+		add(t.key, t.field, t.val, t.info)
+	}
+
+	sorted := make([]*promFamily, 0, len(families))
+	for _, family := range families {
+		// histograms emit their buckets in increasing order, followed by the
+		// sum and count, which is kept within every group.
+		sort.SliceStable(family.samples, func(i, j int) bool {
+			return family.samples[i].group < family.samples[j].group
+		})
+		sorted = append(sorted, family)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].name < sorted[j].name })
+	return sorted
+}
+
+// collectExemplars returns the exemplar with the highest value of every
+// series and field.
+func collectExemplars(r *monkit.Registry) map[string]monkit.Exemplar {
+	highest := map[string]monkit.Exemplar{}
+	r.Exemplars(func(key monkit.SeriesKey, field string, exemplars []monkit.Exemplar) {
+		name := key.WithField(field)
+		for _, ex := range exemplars {
+			if current, ok := highest[name]; !ok || ex.Value > current.Value {
+				highest[name] = ex
+			}
+		}
+	})
+	return highest
+}
+
+// prometheusType returns the metric type used for fields of the given kind.
+func prometheusType(kind monkit.StatKind, openMetrics bool) string {
+	switch kind {
+	case monkit.StatKindCounter:
+		return "counter"
+	case monkit.StatKindGauge, monkit.StatKindQuantile:
+		return "gauge"
+	case monkit.StatKindHistogram:
+		return "histogram"
+	}
+	if openMetrics {
+		return "unknown"
+	}
+	return "untyped"
+}
+
+func writePrometheusHelp(bw *bufio.Writer, help string, openMetrics bool) {
+	for i := 0; i < len(help); i++ {
+		switch {
+		case help[i] == '\\':
+			_, _ = bw.WriteString(`\\`)
+		case help[i] == '\n':
+			_, _ = bw.WriteString(`\n`)
+		case help[i] == '"' && openMetrics:
+			_, _ = bw.WriteString(`\"`)
+		default:
+			_ = bw.WriteByte(help[i])
+		}
+	}
+}
+
+func prometheusLabels(all map[string]string) string {
+	if len(all) == 0 {
+		return ""
+	}
+	names := make(map[string]string, len(all))
+	keys := make([]string, 0, len(all))
+	for key := range all {
+		name := sanitizePrometheusLabel(key)
+		if _, exists := names[name]; !exists {
+			keys = append(keys, name)
+		}
+		names[name] = all[key]
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, name := range keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(name)
+		b.WriteString(`="`)
+		writePrometheusLabelValue(&b, names[name])
+		b.WriteByte('"')
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+func writePrometheusLabelValue(b *strings.Builder, val string) {
+	for i := 0; i < len(val); i++ {
+		switch val[i] {
+		case '\\':
+			b.WriteString(`\\`)
+		case '"':
+			b.WriteString(`\"`)
+		case '\n':
+			b.WriteString(`\n`)
+		default:
+			b.WriteByte(val[i])
+		}
+	}
+}
+
+// sanitizePrometheusName turns name into a valid metric name, matching
+// [a-zA-Z_:][a-zA-Z0-9_:]*.
+func sanitizePrometheusName(name string) string {
+	return sanitizePrometheus(name, true)
+}
+
+// sanitizePrometheusLabel turns name into a valid label name, matching
+// [a-zA-Z_][a-zA-Z0-9_]* and not starting with the reserved "__" prefix.
+func sanitizePrometheusLabel(name string) string {
+	name = sanitizePrometheus(name, false)
+	if strings.HasPrefix(name, "__") {
+		name = "x" + name
+	}
+	return name
+}
+
+func sanitizePrometheus(name string, allowColon bool) string {
+	if name == "" {
+		return "_"
+	}
+	var b strings.Builder
+	b.Grow(len(name) + 1)
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', c == '_',
+			allowColon && c == ':':
+			b.WriteByte(c)
+		case '0' <= c && c <= '9':
+			if i == 0 {
+				b.WriteByte('_')
+			}
+			b.WriteByte(c)
+		default:
+			b.WriteByte('_')
+		}
+	}
+	return b.String()
+}
+
+func formatPrometheusValue(val float64) string {
+	switch {
+	case math.IsNaN(val):
+		return "NaN"
+	case math.IsInf(val, 1):
+		return "+Inf"
+	case math.IsInf(val, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(val, 'g', -1, 64)
+}
+
+// formatPrometheusTimestamp formats t as seconds since the epoch.
+func formatPrometheusTimestamp(t time.Time) string {
+	return strconv.FormatFloat(float64(t.UnixNano())/1e9, 'f', -1, 64)
+}
+
+// acceptsOpenMetrics parses an HTTP Accept header and returns true if the
+// client prefers the OpenMetrics text format over the Prometheus one.
+func acceptsOpenMetrics(accept string) bool {
+	var openMetricsQ, textQ float64 = -1, -1
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if qs, ok := params["q"]; ok {
+			if parsed, err := strconv.ParseFloat(qs, 64); err == nil {
+				q = parsed
+			}
+		}
+		switch mediaType {
+		case "application/openmetrics-text":
+			if q > openMetricsQ {
+				openMetricsQ = q
+			}
+		case "text/plain":
+			if q > textQ {
+				textQ = q
+			}
+		}
+	}
+	return openMetricsQ > 0 && openMetricsQ >= textQ
+}
@@ -0,0 +1,185 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package present
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spacemonkeygo/monkit/v3"
+)
+
+func TestStatsPrometheus(t *testing.T) {
+	reg := monkit.NewRegistry()
+	mon := reg.ScopeNamed("example.com/pkg")
+	mon.Meter("hit 3", monkit.NewSeriesTag("some-tag", `a "b"`)).Mark(2)
+	mon.Counter("calls").Inc(1)
+	mon.Histogram("size", []float64{1, 10}).Observe(5)
+	raw := mon.RawValk(monkit.NewSeriesKey("raw"), monkit.Sum, monkit.Count)
+	raw.UseBuckets([]float64{1})
+	raw.Observe(2)
+
+	var buf bytes.Buffer
+	if err := StatsPrometheus(reg, &buf); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, expected := range []string{
+		"# TYPE calls_value gauge\n",
+		`calls_value{scope="example.com/pkg"} 1` + "\n",
+		"# HELP hit_3_total total number of events\n",
+		"# TYPE hit_3_total counter\n",
+		`hit_3_total{scope="example.com/pkg",some_tag="a \"b\""} 2` + "\n",
+		"# TYPE size histogram\n" +
+			`size_bucket{le="1",scope="example.com/pkg"} 0` + "\n" +
+			`size_bucket{le="10",scope="example.com/pkg"} 1` + "\n" +
+			`size_bucket{le="+Inf",scope="example.com/pkg"} 1` + "\n" +
+			`size_sum{scope="example.com/pkg"} 5` + "\n" +
+			`size_count{scope="example.com/pkg"} 1` + "\n",
+		// the sum and count of aggregations are part of the histogram.
+		"# TYPE raw histogram\n" +
+			`raw_bucket{le="1",scope="example.com/pkg"} 0` + "\n" +
+			`raw_bucket{le="+Inf",scope="example.com/pkg"} 1` + "\n" +
+			`raw_sum{scope="example.com/pkg"} 2` + "\n" +
+			`raw_count{scope="example.com/pkg"} 1` + "\n",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Fatalf("expected %q in output:\n%s", expected, out)
+		}
+	}
+	if strings.Contains(out, "# TYPE raw_sum") || strings.Contains(out, "# TYPE size_sum") {
+		t.Fatalf("sums outside of their histograms:\n%s", out)
+	}
+
+	buf.Reset()
+	if err := StatsOpenMetrics(reg, &buf); err != nil {
+		t.Fatal(err)
+	}
+	out = buf.String()
+	for _, expected := range []string{
+		"# TYPE hit_3 counter\n",
+		`hit_3_total{scope="example.com/pkg",some_tag="a \"b\""} 2` + "\n",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Fatalf("expected %q in output:\n%s", expected, out)
+		}
+	}
+	if !strings.HasSuffix(out, "# EOF\n") {
+		t.Fatalf("missing EOF marker:\n%s", out)
+	}
+}
+
+func TestStatsPrometheusTransformed(t *testing.T) {
+	reg := monkit.NewRegistry()
+	reg.ScopeNamed("example.com/pkg").Meter("hits").Mark(2)
+	renamed := reg.WithTransformers(monkit.CallbackTransformerFunc(
+		func(cb func(monkit.SeriesKey, string, float64)) func(monkit.SeriesKey, string, float64) {
+			return func(key monkit.SeriesKey, field string, val float64) {
+				key.Measurement = "requests"
+				cb(key, field, val)
+			}
+		}))
+
+	var buf bytes.Buffer
+	if err := StatsPrometheus(renamed, &buf); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, expected := range []string{
+		"# HELP requests_total total number of events\n",
+		"# TYPE requests_total counter\n",
+		`requests_total{scope="example.com/pkg"} 2` + "\n",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Fatalf("expected %q in output:\n%s", expected, out)
+		}
+	}
+}
+
+func TestMetricsNegotiation(t *testing.T) {
+	handler := HTTP(monkit.NewRegistry())
+	for _, test := range []struct {
+		accept      string
+		contentType string
+	}{
+		{"", prometheusContentType},
+		{"text/plain", prometheusContentType},
+		{"application/openmetrics-text;version=1.0.0,text/plain;version=0.0.4;q=0.5", openMetricsContentType},
+		{"application/openmetrics-text;q=0.2,text/plain;q=0.8", prometheusContentType},
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		req.Header.Set("Accept", test.accept)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if got := rec.Header().Get("Content-Type"); got != test.contentType {
+			t.Errorf("accept %q: got %q, expected %q", test.accept, got, test.contentType)
+		}
+	}
+}
+
+func TestExemplars(t *testing.T) {
+	reg := monkit.NewRegistry()
+	mon := reg.ScopeNamed("pkg")
+	mon.DurationVal("latency").Observe(time.Second)
+
+	ctx := context.Background()
+	func() {
+		defer mon.TaskNamed("req")(&ctx)(nil)
+		mon.DurationVal("latency").ObserveCtx(ctx, 2*time.Second)
+	}()
+	span := monkit.SpanFromCtx(ctx)
+	ids := fmt.Sprintf(`{trace_id="%016x",span_id="%016x"}`,
+		uint64(span.Trace().Id()), uint64(span.Id()))
+
+	var buf bytes.Buffer
+	if err := StatsOpenMetrics(reg, &buf); err != nil {
+		t.Fatal(err)
+	}
+	expected := `latency_count_total{scope="pkg"} 2 # ` + ids + " 2 "
+	if !strings.Contains(buf.String(), expected) {
+		t.Fatalf("expected %q in output:\n%s", expected, buf.String())
+	}
+
+	buf.Reset()
+	if err := StatsExemplars(reg, &buf); err != nil {
+		t.Fatal(err)
+	}
+	var entries []struct {
+		Measurement string  `json:"measurement"`
+		Field       string  `json:"field"`
+		Value       float64 `json:"value"`
+		TraceId     string  `json:"trace_id"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &entries); err != nil {
+		t.Fatal(err)
+	}
+	var found bool
+	for _, entry := range entries {
+		if entry.Measurement == "latency" {
+			found = entry.Field == "count" && entry.Value == 2 &&
+				entry.TraceId == fmt.Sprintf("%016x", uint64(span.Trace().Id()))
+		}
+	}
+	if !found {
+		t.Fatalf("missing latency exemplar in %s", buf.String())
+	}
+}
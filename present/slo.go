@@ -0,0 +1,116 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package present
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/spacemonkeygo/monkit/v3"
+)
+
+type funcSLO struct {
+	f      *monkit.Func
+	status monkit.SLOStatus
+}
+
+// funcSLOs returns the Funcs of r with an SLO, ordered by name.
+func funcSLOs(r *monkit.Registry) []funcSLO {
+	var slos []funcSLO
+	r.Funcs(func(f *monkit.Func) {
+		if status, ok := f.SLOStatus(); ok {
+			slos = append(slos, funcSLO{f: f, status: status})
+		}
+	})
+	sort.Slice(slos, func(i, j int) bool { return slos[i].f.FullName() < slos[j].f.FullName() })
+	return slos
+}
+
+// SLOText writes the SLO, burn rates and remaining error budget of every Func
+// of the Registry that has an SLO to w in a text format. See
+// monkit.FuncStats.UseSLO.
+func SLOText(r *monkit.Registry, w io.Writer) (err error) {
+	for _, slo := range funcSLOs(r) {
+		status := slo.status
+		_, err = fmt.Fprintf(w, "%s\n target: %g", slo.f.FullName(), status.SLO.Target)
+		if err != nil {
+			return err
+		}
+		if status.SLO.Latency > 0 {
+			_, err = fmt.Fprintf(w, " of calls succeeding within %s", status.SLO.Latency)
+		} else {
+			_, err = fmt.Fprint(w, " of calls succeeding")
+		}
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprint(w, "\n burn rates:")
+		if err != nil {
+			return err
+		}
+		for i, window := range monkit.SLOWindows() {
+			_, err = fmt.Fprintf(w, " %s: %.3f", window.Name, status.BurnRates[i])
+			if err != nil {
+				return err
+			}
+		}
+		_, err = fmt.Fprintf(w, "\n error budget remaining: %.2f%% (%d of %d calls good)\n",
+			status.ErrorBudgetRemaining*100, status.Good, status.Total)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SLOJSON writes the SLO, burn rates and remaining error budget of every Func
+// of the Registry that has an SLO to w in a JSON format.
+func SLOJSON(r *monkit.Registry, w io.Writer) (err error) {
+	type entry struct {
+		Func                 string             `json:"func"`
+		Target               float64            `json:"target"`
+		Latency              float64            `json:"latency,omitempty"`
+		Period               float64            `json:"period"`
+		BurnRates            map[string]float64 `json:"burn_rates"`
+		Good                 int64              `json:"good"`
+		Total                int64              `json:"total"`
+		ErrorBudgetRemaining float64            `json:"error_budget_remaining"`
+	}
+
+	lw := newListWriter(w)
+	for _, slo := range funcSLOs(r) {
+		status := slo.status
+		period := status.SLO.Period
+		if period <= 0 {
+			period = monkit.DefaultSLOPeriod
+		}
+		e := entry{
+			Func:                 slo.f.FullName(),
+			Target:               status.SLO.Target,
+			Latency:              status.SLO.Latency.Seconds(),
+			Period:               period.Seconds(),
+			BurnRates:            map[string]float64{},
+			Good:                 status.Good,
+			Total:                status.Total,
+			ErrorBudgetRemaining: status.ErrorBudgetRemaining,
+		}
+		for i, window := range monkit.SLOWindows() {
+			e.BurnRates[window.Name] = status.BurnRates[i]
+		}
+		lw.elem(e)
+	}
+	return lw.done()
+}
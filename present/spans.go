@@ -100,6 +100,12 @@ func outputTextSpan(w io.Writer, s *monkit.Span, indent string) (err error) {
 			return err
 		}
 	}
+	if tags := s.Tags(); len(tags) > 0 {
+		_, err = fmt.Fprintf(w, "%s  tags: %s\n", indent, formatTags(tags))
+		if err != nil {
+			return err
+		}
+	}
 	s.Children(func(s *monkit.Span) {
 		if err != nil {
 			return
@@ -134,3 +140,12 @@ func SpansJSON(r *monkit.Registry, w io.Writer) (err error) {
 	})
 	return lw.done()
 }
+
+// formatTags formats tags like key=value,key2=value2.
+func formatTags(tags []monkit.SeriesTag) string {
+	parts := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		parts = append(parts, tag.Key+"="+tag.Val)
+	}
+	return strings.Join(parts, ",")
+}
@@ -17,6 +17,8 @@ package present
 import (
 	"fmt"
 	"io"
+	"sort"
+	"time"
 
 	"github.com/spacemonkeygo/monkit/v3"
 )
@@ -45,3 +47,106 @@ func StatsJSON(r *monkit.Registry, w io.Writer) (err error) {
 	})
 	return lw.done()
 }
+
+// StatsCatalog writes every series and field the Registry knows to w in a
+// JSON format, along with the kind, unit and help text the Registry's
+// StatDescriber implementation reports for it.
+func StatsCatalog(r *monkit.Registry, w io.Writer) (err error) {
+	type entry struct {
+		Measurement string            `json:"measurement"`
+		Tags        map[string]string `json:"tags"`
+		Field       string            `json:"field"`
+		Kind        string            `json:"kind"`
+		Unit        string            `json:"unit,omitempty"`
+		Help        string            `json:"help,omitempty"`
+		Quantile    *float64          `json:"quantile,omitempty"`
+	}
+
+	catalog := monkit.NewStatCatalog(r)
+	lw := newListWriter(w)
+	r.Stats(func(key monkit.SeriesKey, field string, val float64) {
+		info, _ := catalog.Lookup(key, field)
+		e := entry{
+			Measurement: key.Measurement,
+			Tags:        key.Tags.All(),
+			Field:       field,
+			Kind:        info.Kind.String(),
+			Unit:        info.Unit,
+			Help:        info.Help,
+		}
+		if info.Kind == monkit.StatKindQuantile {
+			quantile := info.Quantile
+			e.Quantile = &quantile
+		}
+		lw.elem(e)
+	})
+	return lw.done()
+}
+
+// StatsCardinality writes the number of series of every measurement in every
+// Scope of the Registry to w in a JSON format, along with the Scope's
+// cardinality limit and how many tag combinations were collapsed into the
+// overflow series because of it.
+func StatsCardinality(r *monkit.Registry, w io.Writer) (err error) {
+	type entry struct {
+		Scope       string `json:"scope"`
+		Measurement string `json:"measurement"`
+		Series      int    `json:"series"`
+		Limit       int    `json:"limit,omitempty"`
+		Overflows   int64  `json:"overflows"`
+	}
+
+	var scopes []*monkit.Scope
+	r.Scopes(func(s *monkit.Scope) { scopes = append(scopes, s) })
+	sort.Slice(scopes, func(i, j int) bool { return scopes[i].Name() < scopes[j].Name() })
+
+	lw := newListWriter(w)
+	for _, s := range scopes {
+		limit := s.CardinalityLimit()
+		if limit < 0 {
+			limit = 0
+		}
+		for _, c := range s.Cardinality() {
+			lw.elem(entry{
+				Scope:       s.Name(),
+				Measurement: c.Measurement,
+				Series:      c.Series,
+				Limit:       limit,
+				Overflows:   c.Overflows,
+			})
+		}
+	}
+	return lw.done()
+}
+
+// StatsExemplars writes the exemplars of every series and field the Registry
+// knows to w in a JSON format, with the trace and span ids in hex, so that
+// statistics can be traced back to the traces that contributed to them. See
+// monkit.ExemplarSource.
+func StatsExemplars(r *monkit.Registry, w io.Writer) (err error) {
+	type entry struct {
+		Measurement string            `json:"measurement"`
+		Tags        map[string]string `json:"tags"`
+		Field       string            `json:"field"`
+		Value       float64           `json:"value"`
+		Time        time.Time         `json:"time"`
+		TraceId     string            `json:"trace_id"`
+		SpanId      string            `json:"span_id"`
+	}
+
+	lw := newListWriter(w)
+	r.Exemplars(func(key monkit.SeriesKey, field string, exemplars []monkit.Exemplar) {
+		for _, ex := range exemplars {
+			lw.elem(entry{
+				Measurement: key.Measurement,
+				Tags:        key.Tags.All(),
+				Field:       field,
+				Value:       ex.Value,
+				Time:        ex.Time,
+				TraceId:     fmt.Sprintf("%016x", uint64(ex.TraceId)),
+				SpanId:      fmt.Sprintf("%016x", uint64(ex.SpanId)),
+			})
+		}
+	})
+	return lw.done()
+}
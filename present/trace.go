@@ -25,6 +25,7 @@ import (
 
 	"github.com/spacemonkeygo/monkit/v3"
 	"github.com/spacemonkeygo/monkit/v3/collect"
+	"github.com/spacemonkeygo/monkit/v3/zipkin"
 )
 
 const (
@@ -416,6 +417,20 @@ func TraceQueryJSON(reg *monkit.Registry, w io.Writer,
 	return SpansToJSON(w, spans)
 }
 
+// TraceQueryZipkin uses WatchForSpans to write all Spans from 'reg' matching
+// 'matcher' to 'w' in the Zipkin v2 JSON format. See zipkin.Encode.
+func TraceQueryZipkin(reg *monkit.Registry, w io.Writer,
+	matcher func(*monkit.Span) bool) error {
+
+	spans, err := watchForSpansWithKeepalive(context.TODO(),
+		reg, w, matcher, []byte("\n"))
+	if err != nil {
+		return err
+	}
+
+	return zipkin.Encode(w, spans)
+}
+
 // SpansToJSON turns a list of FinishedSpans into JSON format.
 func SpansToJSON(w io.Writer, spans []*collect.FinishedSpan) error {
 	lw := newListWriter(w)
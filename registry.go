@@ -15,8 +15,10 @@
 package monkit
 
 import (
+	"math"
 	"sort"
 	"sync"
+	"sync/atomic"
 )
 
 type traceWatcherRef struct {
@@ -24,8 +26,16 @@ type traceWatcherRef struct {
 }
 
 type registryInternal struct {
-	// sync/atomic things
-	traceWatcher *traceWatcherRef
+	// sync/atomic things. the 64-bit fields come first so they are aligned
+	// on 32-bit platforms.
+	sketchAccuracy uint64 // math.Float64bits of the accuracy, 0 if unset
+	ttl            int64  // time.Duration, see SetTTL
+	limit          int64  // see SetCardinalityLimit
+	traceWatcher   *traceWatcherRef
+	meterWindow    atomic.Value                // MeterWindow, see SetMeterWindow
+	constTags      atomic.Pointer[[]SeriesTag] // see SetTags
+	history        atomic.Pointer[History]     // see RecordHistory
+	events         eventHook                   // see ObserveEvents
 
 	watcherMtx     sync.Mutex
 	watcherCounter int64
@@ -39,6 +49,9 @@ type registryInternal struct {
 
 	orphanMtx sync.Mutex
 	orphans   map[*Span]struct{}
+
+	viewMtx sync.Mutex
+	views   map[*View]struct{}
 }
 
 // Registry encapsulates all of the top-level state for a monitoring system.
@@ -96,6 +109,39 @@ func (r *Registry) ScopeNamed(name string) *Scope {
 	return s
 }
 
+// UseSketches makes all Funcs, Timers, IntVals, FloatVals and DurationVals
+// created from now on estimate quantiles with Sketches of the given relative
+// accuracy instead of sample reservoirs. A relativeAccuracy of 0 goes back to
+// reservoirs for new values. Existing values are unaffected.
+func (r *Registry) UseSketches(relativeAccuracy float64) {
+	if relativeAccuracy != 0 {
+		NewSketch(relativeAccuracy) // validates the accuracy
+	}
+	atomic.StoreUint64(&r.sketchAccuracy, math.Float64bits(relativeAccuracy))
+}
+
+// sketchesAccuracy returns the accuracy configured with UseSketches, or 0.
+func (r *Registry) sketchesAccuracy() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&r.sketchAccuracy))
+}
+
+// SetMeterWindow sets the window of the Meters created through the
+// Registry's Scopes from now on. Existing Meters are unaffected. See
+// MeterWindow.
+func (r *Registry) SetMeterWindow(window MeterWindow) {
+	window.validate()
+	r.meterWindow.Store(window)
+}
+
+// defaultMeterWindow returns the window configured with SetMeterWindow, or
+// DefaultMeterWindow.
+func (r *Registry) defaultMeterWindow() MeterWindow {
+	if window, ok := r.meterWindow.Load().(MeterWindow); ok {
+		return window
+	}
+	return DefaultMeterWindow
+}
+
 func (r *Registry) observeTrace(t *Trace) {
 	watcher := loadTraceWatcherRef(&r.traceWatcher)
 	if watcher != nil {
@@ -228,13 +274,48 @@ func (r *Registry) Funcs(cb func(f *Func)) {
 
 // Stats implements the StatSource interface.
 func (r *Registry) Stats(cb func(key SeriesKey, field string, val float64)) {
-	for _, t := range r.transformers {
-		cb = t.Transform(cb)
-	}
+	cb, flush := r.transform(cb)
 	r.Scopes(func(s *Scope) { s.Stats(cb) })
+	flush()
+}
+
+// DescribeStats implements the StatDescriber interface. The Registry's
+// CallbackTransformers are not applied to the descriptions, see
+// DescribedStats.
+func (r *Registry) DescribeStats(cb func(key SeriesKey, field string, info StatInfo)) {
+	r.Scopes(func(s *Scope) { s.DescribeStats(cb) })
+}
+
+// DescribedStats is like Stats, but also passes the description of every
+// value, as found by a StatCatalog of the Registry. Values the Registry's
+// CallbackTransformers renamed or derived from a value while it was passed
+// to them get that value's description, unless they are described
+// themselves. Values that are only emitted once the transformers are flushed
+// get no description unless they are described themselves.
+func (r *Registry) DescribedStats(cb func(key SeriesKey, field string, val float64, info StatInfo)) {
+	catalog := NewStatCatalog(r)
+
+	var source StatInfo
+	var fromSource bool
+	transformed, flush := r.transform(func(key SeriesKey, field string, val float64) {
+		info, ok := catalog.Lookup(key, field)
+		if !ok && fromSource {
+			info = source
+		}
+		cb(key, field, val, info)
+	})
+	r.Scopes(func(s *Scope) {
+		s.Stats(func(key SeriesKey, field string, val float64) {
+			source, fromSource = catalog.Lookup(key, field)
+			transformed(key, field, val)
+			source, fromSource = StatInfo{}, false
+		})
+	})
+	flush()
 }
 
 var _ StatSource = (*Registry)(nil)
+var _ StatDescriber = (*Registry)(nil)
 
 // Default is the default Registry
 var Default = NewRegistry()
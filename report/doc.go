@@ -0,0 +1,33 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package report periodically pushes the statistics of a Registry to a Sink,
+such as a time series database.
+
+A Reporter collects the Registry's statistics at an interval, buffers the
+values as Points, and passes them to its Sink in batches, retrying with
+backoff when the Sink fails:
+
+	rep := report.NewReporter(monkit.Default, sink, report.Options{
+	  Interval: 10 * time.Second,
+	})
+	go rep.Run(ctx)
+	defer rep.Close()
+
+The Reporter reports on itself in the "github.com/spacemonkeygo/monkit/v3/report"
+Scope of the Registry, with the number of sent and dropped points, send
+failures and send latency, tagged with the name of the Reporter.
+*/
+package report // import "github.com/spacemonkeygo/monkit/v3/report"
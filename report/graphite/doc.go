@@ -0,0 +1,38 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package graphite sends monkit statistics to Graphite in the Carbon plaintext
+protocol, as a report.Sink, over TCP or into a file for offline ingestion.
+
+Series are flattened into dotted paths following Options.Template, a list of
+dot separated parts, each one of
+
+	measurement  the series' measurement
+	field        the field
+	tags         the values of all tags not named elsewhere, ordered by key
+	<key>        the value of the tag <key>, left out if the series has none
+
+The default template "measurement.tags.field" turns the field total of
+"function,name=work,scope=pkg" into
+
+	function.work.pkg.total 5 1700000000
+
+With Options.Tagged, series are sent in the tagged syntax of Graphite 1.1
+instead, as "function.total;name=work;scope=pkg 5 1700000000".
+
+Sending the same points twice, such as when a Send is retried after a broken
+connection, is harmless, as Carbon keeps the last value per path and time.
+*/
+package graphite // import "github.com/spacemonkeygo/monkit/v3/report/graphite"
@@ -0,0 +1,155 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graphite
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spacemonkeygo/monkit/v3"
+	"github.com/spacemonkeygo/monkit/v3/report"
+)
+
+// DefaultTemplate is the default of Options.Template.
+const DefaultTemplate = "measurement.tags.field"
+
+// Options configure how points are formatted.
+type Options struct {
+	// Prefix is prepended to all paths, such as "servers.host1.".
+	Prefix string
+	// Template orders the parts of paths. See the package documentation.
+	// Defaults to DefaultTemplate. It is ignored with Tagged.
+	Template string
+	// Escape makes measurements, fields and tags safe to use as parts of
+	// paths. Defaults to Escape.
+	Escape func(string) string
+	// Tagged sends series in the tagged syntax of Graphite 1.1.
+	Tagged bool
+}
+
+var escaper = strings.NewReplacer(".", "_", " ", "_", "\t", "_", "\n", "_", ";", "_", "=", "_",
+	"~", "_", "!", "_", "^", "_")
+
+// Escape replaces dots, whitespace and the characters Graphite's tagged
+// syntax reserves with underscores.
+func Escape(s string) string {
+	return escaper.Replace(s)
+}
+
+// formatter formats points as Carbon plaintext.
+type formatter struct {
+	opts  Options
+	parts []string
+	named map[string]bool // tag keys named in the template
+}
+
+func newFormatter(opts Options) (*formatter, error) {
+	if opts.Template == "" {
+		opts.Template = DefaultTemplate
+	}
+	if opts.Escape == nil {
+		opts.Escape = Escape
+	}
+	f := &formatter{opts: opts, parts: strings.Split(opts.Template, "."), named: map[string]bool{}}
+	for _, part := range f.parts {
+		switch part {
+		case "":
+			return nil, fmt.Errorf("graphite: empty part in template %q", opts.Template)
+		case "measurement", "field", "tags":
+		default:
+			f.named[part] = true
+		}
+	}
+	return f, nil
+}
+
+// append appends the lines of points to buf. NaN and infinite values are
+// left out.
+func (f *formatter) append(buf []byte, points []report.Point) []byte {
+	for _, p := range points {
+		if math.IsNaN(p.Value) || math.IsInf(p.Value, 0) {
+			continue
+		}
+		buf = append(buf, f.path(p.Key, p.Field)...)
+		buf = append(buf, ' ')
+		buf = strconv.AppendFloat(buf, p.Value, 'g', -1, 64)
+		buf = append(buf, ' ')
+		buf = strconv.AppendInt(buf, p.Time.Unix(), 10)
+		buf = append(buf, '\n')
+	}
+	return buf
+}
+
+// path returns the path of field of the series key.
+func (f *formatter) path(key monkit.SeriesKey, field string) string {
+	esc := f.opts.Escape
+	all := key.Tags.All()
+	keys := make([]string, 0, len(all))
+	for k := range all {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString(f.opts.Prefix)
+
+	if f.opts.Tagged {
+		b.WriteString(esc(key.Measurement))
+		b.WriteByte('.')
+		b.WriteString(esc(field))
+		for _, k := range keys {
+			if all[k] == "" {
+				continue // graphite doesn't allow empty tag values
+			}
+			b.WriteByte(';')
+			b.WriteString(esc(k))
+			b.WriteByte('=')
+			b.WriteString(esc(all[k]))
+		}
+		return b.String()
+	}
+
+	first := true
+	write := func(s string) {
+		if s == "" {
+			return
+		}
+		if !first {
+			b.WriteByte('.')
+		}
+		b.WriteString(esc(s))
+		first = false
+	}
+	for _, part := range f.parts {
+		switch part {
+		case "measurement":
+			write(key.Measurement)
+		case "field":
+			write(field)
+		case "tags":
+			for _, k := range keys {
+				if !f.named[k] {
+					write(all[k])
+				}
+			}
+		default:
+			write(all[part])
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,148 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graphite
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spacemonkeygo/monkit/v3"
+	"github.com/spacemonkeygo/monkit/v3/report"
+)
+
+var now = time.Unix(1700000000, 0)
+
+func testPoints(val float64) []report.Point {
+	key := monkit.NewSeriesKey("function").
+		WithTag("scope", "my.pkg").WithTag("name", "work").WithTag("kind", "")
+	return []report.Point{
+		{Key: key, Field: "total", Value: val, Time: now},
+		{Key: key, Field: "rate", Value: math.NaN(), Time: now},
+	}
+}
+
+func format(t *testing.T, opts Options) string {
+	t.Helper()
+	var buf bytes.Buffer
+	sink, err := NewWriterSink(&buf, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sink.Send(context.Background(), testPoints(5)); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestFormat(t *testing.T) {
+	for _, test := range []struct {
+		opts     Options
+		expected string
+	}{
+		{Options{}, "function.work.my_pkg.total 5 1700000000\n"},
+		{Options{Prefix: "app.", Template: "scope.measurement.name.field"},
+			"app.my_pkg.function.work.total 5 1700000000\n"},
+		{Options{Template: "measurement.scope.tags.field",
+			Escape: func(s string) string { return strings.ReplaceAll(s, ".", "-") }},
+			"function.my-pkg.work.total 5 1700000000\n"},
+		{Options{Tagged: true}, "function.total;name=work;scope=my_pkg 5 1700000000\n"},
+	} {
+		if got := format(t, test.opts); got != test.expected {
+			t.Errorf("%+v: got %q, expected %q", test.opts, got, test.expected)
+		}
+	}
+
+	if _, err := NewWriterSink(&bytes.Buffer{}, Options{Template: "measurement..field"}); err == nil {
+		t.Error("expected error for bad template")
+	}
+}
+
+func TestFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metrics.txt")
+	for _, val := range []float64{1, 2} {
+		sink, err := OpenFile(path, Options{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := sink.Send(context.Background(), testPoints(val)); err != nil {
+			t.Fatal(err)
+		}
+		if err := sink.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "function.work.my_pkg.total 1 1700000000\nfunction.work.my_pkg.total 2 1700000000\n" {
+		t.Fatalf("got %q", data)
+	}
+}
+
+func TestTCPReconnect(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = ln.Close() }()
+
+	// the server reads one line per connection, then hangs up.
+	lines := make(chan string, 10)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			line, _ := bufio.NewReader(conn).ReadString('\n')
+			lines <- line
+			_ = conn.Close()
+		}
+	}()
+
+	sink, err := NewTCPSink(ln.Addr().String(), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = sink.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for _, val := range []float64{1, 2} {
+		if err := sink.Send(ctx, testPoints(val)); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case line := <-lines:
+			if expected := fmt.Sprintf("function.work.my_pkg.total %g 1700000000\n", val); line != expected {
+				t.Fatalf("got %q, expected %q", line, expected)
+			}
+		case <-ctx.Done():
+			t.Fatalf("nothing received for %v", val)
+		}
+		// give the server time to hang up before the next Send.
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graphite
+
+import (
+	"context"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/spacemonkeygo/monkit/v3/report"
+)
+
+// Sink writes points to Carbon over TCP, or to an io.Writer.
+type Sink struct {
+	f *formatter
+
+	mtx    sync.Mutex
+	addr   string    // Carbon's address, if sending over TCP
+	conn   net.Conn  // current connection to addr, if any
+	w      io.Writer // otherwise, where points are written
+	closer io.Closer // closed with the Sink, if any
+}
+
+// NewTCPSink creates a Sink that sends points to the Carbon plaintext
+// listener at addr, such as "localhost:2003". It connects on the first Send,
+// and reconnects whenever the connection breaks.
+func NewTCPSink(addr string, opts Options) (*Sink, error) {
+	f, err := newFormatter(opts)
+	if err != nil {
+		return nil, err
+	}
+	return &Sink{f: f, addr: addr}, nil
+}
+
+// NewWriterSink creates a Sink that writes points to w.
+func NewWriterSink(w io.Writer, opts Options) (*Sink, error) {
+	f, err := newFormatter(opts)
+	if err != nil {
+		return nil, err
+	}
+	return &Sink{f: f, w: w}, nil
+}
+
+// OpenFile creates a Sink that appends points to the file at path, creating
+// it if needed, for ingestion by Carbon later.
+func OpenFile(path string, opts Options) (*Sink, error) {
+	f, err := newFormatter(opts)
+	if err != nil {
+		return nil, err
+	}
+	fh, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &Sink{f: f, w: fh, closer: fh}, nil
+}
+
+// Send implements report.Sink.
+func (s *Sink) Send(ctx context.Context, points []report.Point) error {
+	buf := s.f.append(nil, points)
+	if len(buf) == 0 {
+		return nil
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.addr == "" {
+		_, err := s.w.Write(buf)
+		return err
+	}
+
+	// writes to a connection Carbon closed may only fail after the data is
+	// lost, so the connection is checked first. A failed write on an existing
+	// connection is retried once on a new one.
+	if s.conn != nil && !alive(s.conn) {
+		_ = s.conn.Close()
+		s.conn = nil
+	}
+	for attempt := 0; ; attempt++ {
+		reused := s.conn != nil
+		if !reused {
+			var d net.Dialer
+			conn, err := d.DialContext(ctx, "tcp", s.addr)
+			if err != nil {
+				return err
+			}
+			s.conn = conn
+		}
+
+		deadline, _ := ctx.Deadline()
+		_ = s.conn.SetWriteDeadline(deadline)
+		_, err := s.conn.Write(buf)
+		if err == nil {
+			return nil
+		}
+		_ = s.conn.Close()
+		s.conn = nil
+		if !reused || attempt > 0 || ctx.Err() != nil {
+			return err
+		}
+	}
+}
+
+// Close closes the Sink's connection or file.
+func (s *Sink) Close() error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	var err error
+	if s.conn != nil {
+		err = s.conn.Close()
+		s.conn = nil
+	}
+	if s.closer != nil {
+		if closeErr := s.closer.Close(); err == nil {
+			err = closeErr
+		}
+		s.closer = nil
+	}
+	return err
+}
+
+// alive returns whether Carbon has not closed conn. Carbon never writes to
+// its connections, so a read that doesn't time out means it is gone. The
+// deadline is in the future, as reads past their deadline aren't attempted.
+func alive(conn net.Conn) bool {
+	_ = conn.SetReadDeadline(time.Now().Add(time.Millisecond))
+	var b [1]byte
+	_, err := conn.Read(b[:])
+	_ = conn.SetReadDeadline(time.Time{})
+	netErr, ok := err.(net.Error)
+	return ok && netErr.Timeout()
+}
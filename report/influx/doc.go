@@ -0,0 +1,30 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package influx sends monkit statistics to InfluxDB in its line protocol, as a
+report.Sink:
+
+	sink := &influx.HTTPSink{URL: "http://localhost:8086/write?db=monkit"}
+	rep := report.NewReporter(monkit.Default, sink, report.Options{})
+
+The fields of a series collected at the same time are written on one line,
+with the series key from monkit.SeriesKey.String and a timestamp in
+nanoseconds:
+
+	function,name=mypkg.MyFunc,scope=mypkg total=5,errors=1 1700000000000000000
+
+Values that line protocol can't represent, NaN and infinities, are left out.
+*/
+package influx // import "github.com/spacemonkeygo/monkit/v3/report/influx"
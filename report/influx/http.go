@@ -0,0 +1,75 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package influx
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/spacemonkeygo/monkit/v3/report"
+)
+
+// HTTPSink writes points to the HTTP API of InfluxDB, gzipped.
+type HTTPSink struct {
+	// URL is the write endpoint, including its query, such as
+	// "http://localhost:8086/write?db=monkit" for InfluxDB 1.x or
+	// "http://localhost:8086/api/v2/write?org=o&bucket=b" for 2.x.
+	URL string
+	// Header is added to the requests, such as an Authorization header.
+	Header http.Header
+	// Client is used for the requests, http.DefaultClient if nil.
+	Client *http.Client
+}
+
+// Send implements report.Sink.
+func (s *HTTPSink) Send(ctx context.Context, points []report.Point) error {
+	var body bytes.Buffer
+	zw := gzip.NewWriter(&body)
+	if _, err := zw.Write(Marshal(points)); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, &body)
+	if err != nil {
+		return err
+	}
+	for key, values := range s.Header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	req.Header.Set("Content-Encoding", "gzip")
+
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("influx: write %s: %s: %s", s.URL, resp.Status, bytes.TrimSpace(msg))
+	}
+	return nil
+}
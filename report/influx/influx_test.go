@@ -0,0 +1,122 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package influx
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spacemonkeygo/monkit/v3"
+	"github.com/spacemonkeygo/monkit/v3/report"
+)
+
+var now = time.Unix(1700000000, 5)
+
+func testPoints() []report.Point {
+	key := monkit.NewSeriesKey("function").WithTag("name", "my func").WithTag("scope", "pkg")
+	other := monkit.NewSeriesKey("queue,len").WithTag("empty", "")
+	return []report.Point{
+		{Key: key, Field: "total", Value: 5, Time: now},
+		{Key: other, Field: "value", Value: 1.5, Time: now},
+		{Key: key, Field: "errors", Value: 1, Time: now},
+		{Key: key, Field: "bad=field", Value: math.NaN(), Time: now},
+		{Key: key, Field: "total", Value: 6, Time: now.Add(time.Second)},
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	got := string(Marshal(testPoints()))
+	expected := "" +
+		"function,name=my\\ func,scope=pkg total=5,errors=1 1700000000000000005\n" +
+		"queue\\,len value=1.5 1700000000000000005\n" +
+		"function,name=my\\ func,scope=pkg total=6 1700000001000000005\n"
+	if got != expected {
+		t.Fatalf("got:\n%s\nexpected:\n%s", got, expected)
+	}
+}
+
+func TestUDPSink(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	sink := &UDPSink{Addr: conn.LocalAddr().String(), MaxPacketSize: 65}
+	defer func() { _ = sink.Close() }()
+	if err := sink.Send(context.Background(), testPoints()); err != nil {
+		t.Fatal(err)
+	}
+
+	var lines []string
+	buf := make([]byte, 1<<16)
+	for len(lines) < 4 {
+		_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n > 65 {
+			t.Fatalf("got packet of %d bytes", n)
+		}
+		lines = append(lines, strings.Split(strings.TrimSuffix(string(buf[:n]), "\n"), "\n")...)
+	}
+	// the first series doesn't fit in a packet, so it is split.
+	if lines[0] != "function,name=my\\ func,scope=pkg total=5 1700000000000000005" ||
+		lines[1] != "function,name=my\\ func,scope=pkg errors=1 1700000000000000005" {
+		t.Fatalf("got %q", lines)
+	}
+}
+
+func TestHTTPSink(t *testing.T) {
+	bodies := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Content-Encoding") != "gzip" || req.Header.Get("Authorization") != "Token x" ||
+			req.URL.Query().Get("db") != "monkit" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		zr, err := gzip.NewReader(req.Body)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		body, _ := io.ReadAll(zr)
+		bodies <- string(body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	sink := &HTTPSink{URL: server.URL + "/write?db=monkit", Header: http.Header{"Authorization": {"Token x"}}}
+	if err := sink.Send(context.Background(), testPoints()); err != nil {
+		t.Fatal(err)
+	}
+	if body := <-bodies; body != string(Marshal(testPoints())) {
+		t.Fatalf("got %q", body)
+	}
+
+	sink.Header = nil
+	if err := sink.Send(context.Background(), testPoints()); err == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package influx
+
+import (
+	"bytes"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/spacemonkeygo/monkit/v3"
+	"github.com/spacemonkeygo/monkit/v3/report"
+)
+
+var fieldEscaper = strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `)
+
+// Marshal returns points in line protocol, one line per series and time.
+func Marshal(points []report.Point) []byte {
+	var buf []byte
+	for _, line := range encode(points, 0) {
+		buf = append(buf, line...)
+	}
+	return buf
+}
+
+// encode returns points as lines of line protocol, each ending in a newline.
+// Points of the same series and time share a line, unless that would make
+// the line longer than maxLen, in which case the series is split over
+// several lines. Fields that don't fit on a line of their own are left out.
+// maxLen 0 means no limit.
+func encode(points []report.Point, maxLen int) [][]byte {
+	type line struct {
+		prefix string
+		nanos  int64
+		fields [][]byte
+	}
+	var lines []*line
+	byKey := map[string]*line{}
+
+	for _, p := range points {
+		if math.IsNaN(p.Value) || math.IsInf(p.Value, 0) {
+			continue
+		}
+		prefix := seriesKey(p.Key)
+		nanos := p.Time.UnixNano()
+		id := prefix + " " + strconv.FormatInt(nanos, 10)
+		l, ok := byKey[id]
+		if !ok {
+			l = &line{prefix: prefix, nanos: nanos}
+			lines = append(lines, l)
+			byKey[id] = l
+		}
+		field := []byte(fieldEscaper.Replace(p.Field))
+		field = append(field, '=')
+		field = strconv.AppendFloat(field, p.Value, 'g', -1, 64)
+		l.fields = append(l.fields, field)
+	}
+
+	var out [][]byte
+	for _, l := range lines {
+		suffix := " " + strconv.FormatInt(l.nanos, 10) + "\n"
+		var cur []byte
+		finish := func() {
+			if cur != nil {
+				out = append(out, append(cur, suffix...))
+				cur = nil
+			}
+		}
+		for _, field := range l.fields {
+			if cur != nil && maxLen > 0 && len(cur)+1+len(field)+len(suffix) > maxLen {
+				finish()
+			}
+			if cur == nil {
+				if maxLen > 0 && len(l.prefix)+1+len(field)+len(suffix) > maxLen {
+					continue
+				}
+				cur = append(append([]byte(l.prefix), ' '), field...)
+				continue
+			}
+			cur = append(append(cur, ','), field...)
+		}
+		finish()
+	}
+	return out
+}
+
+// seriesKey returns the escaped series key of key, without the tags with
+// empty values, which line protocol doesn't allow.
+func seriesKey(key monkit.SeriesKey) string {
+	var empty bool
+	for _, v := range key.Tags.All() {
+		empty = empty || v == ""
+	}
+	if empty {
+		kept := map[string]string{}
+		for k, v := range key.Tags.All() {
+			if v != "" {
+				kept[k] = v
+			}
+		}
+		key.Tags = new(monkit.TagSet).SetAll(kept)
+	}
+	return key.String()
+}
+
+// pack groups lines into packets of at most maxLen bytes.
+func pack(lines [][]byte, maxLen int) [][]byte {
+	var packets [][]byte
+	var cur bytes.Buffer
+	for _, line := range lines {
+		if cur.Len() > 0 && cur.Len()+len(line) > maxLen {
+			packets = append(packets, append([]byte(nil), cur.Bytes()...))
+			cur.Reset()
+		}
+		cur.Write(line)
+	}
+	if cur.Len() > 0 {
+		packets = append(packets, cur.Bytes())
+	}
+	return packets
+}
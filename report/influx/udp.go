@@ -0,0 +1,79 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package influx
+
+import (
+	"context"
+	"net"
+	"sync"
+
+	"github.com/spacemonkeygo/monkit/v3/report"
+)
+
+// DefaultMaxPacketSize is the default size limit of the packets of a
+// UDPSink, which keeps them from being fragmented on common networks.
+const DefaultMaxPacketSize = 1400
+
+// UDPSink sends points to the UDP listener of InfluxDB. Lines are packed into
+// packets of at most MaxPacketSize bytes. The fields of a series that don't
+// fit in one packet are split over several lines, and fields that don't fit
+// in a packet on their own are left out.
+type UDPSink struct {
+	// Addr is the host:port InfluxDB listens on.
+	Addr string
+	// MaxPacketSize limits the size of the packets. Defaults to
+	// DefaultMaxPacketSize.
+	MaxPacketSize int
+
+	mtx  sync.Mutex
+	conn net.Conn
+}
+
+// Send implements report.Sink.
+func (s *UDPSink) Send(ctx context.Context, points []report.Point) error {
+	maxLen := s.MaxPacketSize
+	if maxLen <= 0 {
+		maxLen = DefaultMaxPacketSize
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.conn == nil {
+		var d net.Dialer
+		conn, err := d.DialContext(ctx, "udp", s.Addr)
+		if err != nil {
+			return err
+		}
+		s.conn = conn
+	}
+	for _, packet := range pack(encode(points, maxLen), maxLen) {
+		if _, err := s.conn.Write(packet); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close closes the UDPSink's socket.
+func (s *UDPSink) Close() error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
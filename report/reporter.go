@@ -0,0 +1,322 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package report
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/spacemonkeygo/monkit/v3"
+)
+
+// scopeName is the Scope Reporters report on themselves in.
+const scopeName = "github.com/spacemonkeygo/monkit/v3/report"
+
+// Options configure a Reporter. The zero value of every option picks a
+// default.
+type Options struct {
+	// Name identifies the Reporter in its own statistics. Defaults to
+	// "default".
+	Name string
+	// Interval is how often statistics are collected. Defaults to 1 minute.
+	Interval time.Duration
+	// Jitter is the most every collection is delayed by at random, so that
+	// many processes started together don't all report at the same time.
+	// Defaults to no jitter.
+	Jitter time.Duration
+	// BatchSize is the most points passed to a single Send, except that the
+	// fields of a series collected together are never split across batches.
+	// Defaults to 1000.
+	BatchSize int
+	// MaxBuffered is the most points kept waiting to be sent. Once it is
+	// reached, the oldest points are dropped. Defaults to 100000.
+	MaxBuffered int
+	// MinBackoff and MaxBackoff bound the delay before a failed Send is
+	// retried. The delay doubles with every consecutive failure. They
+	// default to 1 second and to Interval.
+	MinBackoff, MaxBackoff time.Duration
+	// FlushTimeout bounds the final flush when the Reporter stops. Defaults
+	// to 10 seconds.
+	FlushTimeout time.Duration
+}
+
+func (opts Options) withDefaults() Options {
+	if opts.Name == "" {
+		opts.Name = "default"
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = time.Minute
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 1000
+	}
+	if opts.MaxBuffered <= 0 {
+		opts.MaxBuffered = 100000
+	}
+	if opts.MinBackoff <= 0 {
+		opts.MinBackoff = time.Second
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = opts.Interval
+	}
+	if opts.MaxBackoff < opts.MinBackoff {
+		opts.MaxBackoff = opts.MinBackoff
+	}
+	if opts.FlushTimeout <= 0 {
+		opts.FlushTimeout = 10 * time.Second
+	}
+	return opts
+}
+
+// Reporter periodically collects the statistics of a Registry and sends them
+// to a Sink. Create one with NewReporter.
+type Reporter struct {
+	r    *monkit.Registry
+	sink Sink
+	opts Options
+
+	sent    *monkit.Meter
+	dropped *monkit.Meter
+	failed  *monkit.Meter
+	latency *monkit.DurationVal
+
+	// sendMtx serializes sending, so batches are sent in order.
+	sendMtx sync.Mutex
+
+	mtx      sync.Mutex
+	buffer   []Point
+	removed  int64 // number of points ever removed from the front of buffer
+	closing  chan struct{}
+	closed   bool
+	running  bool
+	finished chan struct{}
+	finalErr error
+}
+
+// NewReporter creates a Reporter that sends the statistics of r to sink.
+func NewReporter(r *monkit.Registry, sink Sink, opts Options) *Reporter {
+	opts = opts.withDefaults()
+	mon := r.ScopeNamed(scopeName)
+	tag := monkit.NewSeriesTag("reporter", opts.Name)
+	return &Reporter{
+		r:    r,
+		sink: sink,
+		opts: opts,
+
+		sent:    mon.Meter("points_sent", tag),
+		dropped: mon.Meter("points_dropped", tag),
+		failed:  mon.Meter("send_failures", tag),
+		latency: mon.DurationVal("send_latency", tag),
+
+		closing:  make(chan struct{}),
+		finished: make(chan struct{}),
+	}
+}
+
+// Collect collects the Registry's statistics as of now and buffers them to be
+// sent. If that exceeds MaxBuffered, the oldest points are dropped.
+func (rep *Reporter) Collect(now time.Time) {
+	rep.add(Collect(rep.r, now))
+}
+
+func (rep *Reporter) add(points []Point) {
+	rep.mtx.Lock()
+	rep.buffer = append(rep.buffer, points...)
+	dropped := len(rep.buffer) - rep.opts.MaxBuffered
+	if dropped > 0 {
+		rep.removeLocked(dropped)
+	}
+	rep.mtx.Unlock()
+	if dropped > 0 {
+		rep.dropped.Mark(dropped)
+	}
+}
+
+// Buffered returns the number of points waiting to be sent.
+func (rep *Reporter) Buffered() int {
+	rep.mtx.Lock()
+	defer rep.mtx.Unlock()
+	return len(rep.buffer)
+}
+
+// Flush sends the buffered points in batches of at most BatchSize, until
+// they are all sent or a Send fails. Points are only removed from the buffer
+// once they are sent, so a failed batch is sent again by the next Flush.
+func (rep *Reporter) Flush(ctx context.Context) error {
+	rep.sendMtx.Lock()
+	defer rep.sendMtx.Unlock()
+
+	for {
+		rep.mtx.Lock()
+		n := len(rep.buffer)
+		if n > rep.opts.BatchSize {
+			n = rep.opts.BatchSize
+			// keep the fields of a series together, so that Sinks can
+			// combine them, such as into a distribution.
+			for n < len(rep.buffer) && sameSeries(rep.buffer[n-1], rep.buffer[n]) {
+				n++
+			}
+		}
+		batch := append([]Point(nil), rep.buffer[:n]...)
+		removed := rep.removed
+		rep.mtx.Unlock()
+		if len(batch) == 0 {
+			return nil
+		}
+
+		start := time.Now()
+		err := rep.sink.Send(ctx, batch)
+		rep.latency.Observe(time.Since(start))
+		if err != nil {
+			rep.failed.Mark(1)
+			return err
+		}
+		rep.sent.Mark(len(batch))
+
+		// points may have been dropped from the front of the buffer while
+		// sending, so only remove what is left of the batch.
+		rep.mtx.Lock()
+		if left := len(batch) - int(rep.removed-removed); left > 0 {
+			rep.removeLocked(left)
+		}
+		rep.mtx.Unlock()
+	}
+}
+
+// sameSeries returns whether a and b are fields of the same series collected
+// at the same time.
+func sameSeries(a, b Point) bool {
+	return a.Time.Equal(b.Time) && a.Key.Measurement == b.Key.Measurement &&
+		a.Key.String() == b.Key.String()
+}
+
+// removeLocked removes n points from the front of the buffer. rep.mtx must be
+// held.
+func (rep *Reporter) removeLocked(n int) {
+	rep.buffer = append(rep.buffer[:0], rep.buffer[n:]...)
+	rep.removed += int64(n)
+}
+
+// Run collects statistics every Interval, plus a random delay of up to
+// Jitter, and sends them, retrying failed sends with backoff. When ctx is
+// done or the Reporter is closed, it collects and flushes one last time and
+// returns. A Reporter can only be run once.
+func (rep *Reporter) Run(ctx context.Context) error {
+	rep.mtx.Lock()
+	if rep.running || rep.closed {
+		rep.mtx.Unlock()
+		return errors.New("report: reporter already run or closed")
+	}
+	rep.running = true
+	rep.mtx.Unlock()
+	defer close(rep.finished)
+
+	final := func() error {
+		err := rep.final()
+		rep.mtx.Lock()
+		rep.finalErr = err
+		rep.mtx.Unlock()
+		return err
+	}
+
+	collect := time.NewTimer(rep.jitter(rep.opts.Interval))
+	defer collect.Stop()
+	var retry <-chan time.Time
+	var backoff time.Duration
+
+	for {
+		select {
+		case <-ctx.Done():
+			return final()
+		case <-rep.closing:
+			return final()
+		case now := <-collect.C:
+			collect.Reset(rep.jitter(rep.opts.Interval))
+			rep.Collect(now)
+			if retry != nil {
+				// the pending retry sends the new points too.
+				continue
+			}
+		case <-retry:
+			retry = nil
+		}
+
+		if err := rep.Flush(ctx); err != nil {
+			backoff *= 2
+			if backoff < rep.opts.MinBackoff {
+				backoff = rep.opts.MinBackoff
+			}
+			if backoff > rep.opts.MaxBackoff {
+				backoff = rep.opts.MaxBackoff
+			}
+			retry = time.After(backoff)
+		} else {
+			backoff = 0
+		}
+	}
+}
+
+// jitter returns d plus a random delay of up to Jitter.
+func (rep *Reporter) jitter(d time.Duration) time.Duration {
+	if rep.opts.Jitter > 0 {
+		d += time.Duration(rand.Int63n(int64(rep.opts.Jitter)))
+	}
+	return d
+}
+
+// final collects and flushes one last time, within FlushTimeout.
+func (rep *Reporter) final() error {
+	rep.Collect(time.Now())
+	ctx, cancel := context.WithTimeout(context.Background(), rep.opts.FlushTimeout)
+	defer cancel()
+	return rep.Flush(ctx)
+}
+
+// Close stops Run, waiting for its final flush, and closes the Sink if it
+// implements io.Closer. It returns the error of the final flush. If Run was
+// never called, Close flushes the buffered points itself.
+func (rep *Reporter) Close() error {
+	rep.mtx.Lock()
+	if rep.closed {
+		rep.mtx.Unlock()
+		return nil
+	}
+	rep.closed = true
+	running := rep.running
+	rep.mtx.Unlock()
+
+	close(rep.closing)
+	var err error
+	if running {
+		<-rep.finished
+		rep.mtx.Lock()
+		err = rep.finalErr
+		rep.mtx.Unlock()
+	} else {
+		ctx, cancel := context.WithTimeout(context.Background(), rep.opts.FlushTimeout)
+		err = rep.Flush(ctx)
+		cancel()
+	}
+	if closer, ok := rep.sink.(io.Closer); ok {
+		if closeErr := closer.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
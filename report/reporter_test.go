@@ -0,0 +1,200 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package report
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spacemonkeygo/monkit/v3"
+)
+
+type fakeSink struct {
+	mtx     sync.Mutex
+	fail    int // number of sends left to fail
+	batches [][]Point
+	closed  bool
+}
+
+func (s *fakeSink) Send(ctx context.Context, points []Point) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.fail > 0 {
+		s.fail--
+		return errors.New("unavailable")
+	}
+	s.batches = append(s.batches, points)
+	return nil
+}
+
+func (s *fakeSink) Close() error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.closed = true
+	return nil
+}
+
+func (s *fakeSink) count() (points int) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	for _, batch := range s.batches {
+		points += len(batch)
+	}
+	return points
+}
+
+func selfStat(t *testing.T, r *monkit.Registry, measurement, field string) float64 {
+	t.Helper()
+	val, found := 0.0, false
+	r.ScopeNamed(scopeName).Stats(func(key monkit.SeriesKey, f string, v float64) {
+		if key.Measurement == measurement && f == field {
+			val, found = v, true
+		}
+	})
+	if !found {
+		t.Fatalf("no %s %s", measurement, field)
+	}
+	return val
+}
+
+func TestBatchingAndRetry(t *testing.T) {
+	ctx := context.Background()
+	r := monkit.NewRegistry()
+	for _, name := range []string{"a", "b", "c"} {
+		r.ScopeNamed("test").Counter(name).Inc(1)
+	}
+	sink := &fakeSink{fail: 1}
+	rep := NewReporter(r, sink, Options{BatchSize: 2})
+
+	rep.Collect(time.Unix(1000, 0))
+	collected := rep.Buffered()
+	if collected == 0 {
+		t.Fatal("nothing collected")
+	}
+	for _, p := range Collect(r, time.Unix(1000, 0)) {
+		if p.Key.Measurement == "a" && p.Field == "value" && p.Info.Kind != monkit.StatKindGauge {
+			t.Fatalf("got info %+v", p.Info)
+		}
+	}
+
+	if err := rep.Flush(ctx); err == nil {
+		t.Fatal("expected failure")
+	}
+	if rep.Buffered() != collected || selfStat(t, r, "send_failures", "total") != 1 {
+		t.Fatalf("got %d buffered", rep.Buffered())
+	}
+
+	if err := rep.Flush(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if rep.Buffered() != 0 || sink.count() != collected {
+		t.Fatalf("got %d buffered, %d sent", rep.Buffered(), sink.count())
+	}
+	// batches only grow past the batch size to keep series together.
+	batchOf := map[string]int{}
+	for i, batch := range sink.batches {
+		for j, p := range batch {
+			if j >= 2 && !sameSeries(batch[j-1], p) {
+				t.Fatalf("got batch of %d", len(batch))
+			}
+			if prev, ok := batchOf[p.Key.String()]; ok && prev != i {
+				t.Fatalf("series %s split across batches", p.Key)
+			}
+			batchOf[p.Key.String()] = i
+		}
+	}
+	if got := selfStat(t, r, "points_sent", "total"); got != float64(collected) {
+		t.Fatalf("got %v sent", got)
+	}
+	if got := selfStat(t, r, "send_latency", "count"); got != float64(len(sink.batches)+1) {
+		t.Fatalf("got %v latencies", got)
+	}
+}
+
+func TestBoundedBuffer(t *testing.T) {
+	r := monkit.NewRegistry()
+	r.ScopeNamed("test").Counter("a").Inc(1)
+	perCollect := len(Collect(r, time.Now()))
+
+	rep := NewReporter(r, &fakeSink{}, Options{MaxBuffered: perCollect + 1})
+	rep.Collect(time.Unix(1000, 0))
+	rep.Collect(time.Unix(1001, 0))
+
+	if rep.Buffered() != perCollect+1 {
+		t.Fatalf("got %d buffered", rep.Buffered())
+	}
+	if got := selfStat(t, r, "points_dropped", "total"); got < float64(perCollect-1) {
+		t.Fatalf("got %v dropped", got)
+	}
+	// the newest points are kept.
+	rep.mtx.Lock()
+	last := rep.buffer[len(rep.buffer)-1]
+	rep.mtx.Unlock()
+	if !last.Time.Equal(time.Unix(1001, 0)) {
+		t.Fatalf("got %v", last.Time)
+	}
+}
+
+func TestRunAndClose(t *testing.T) {
+	r := monkit.NewRegistry()
+	r.ScopeNamed("test").Counter("a").Inc(1)
+	sink := &fakeSink{fail: 1}
+	rep := NewReporter(r, sink, Options{
+		Interval:   time.Millisecond,
+		Jitter:     time.Millisecond,
+		MinBackoff: time.Millisecond,
+	})
+
+	errs := make(chan error, 1)
+	go func() { errs <- rep.Run(context.Background()) }()
+	for deadline := time.Now().Add(5 * time.Second); sink.count() == 0; {
+		if time.Now().After(deadline) {
+			t.Fatal("nothing sent")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if err := rep.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-errs; err != nil {
+		t.Fatal(err)
+	}
+	if !sink.closed || rep.Buffered() != 0 {
+		t.Fatalf("closed %v with %d buffered", sink.closed, rep.Buffered())
+	}
+	if err := rep.Run(context.Background()); err == nil {
+		t.Fatal("expected error running a closed reporter")
+	}
+}
+
+func TestFinalFlushOnCancel(t *testing.T) {
+	r := monkit.NewRegistry()
+	r.ScopeNamed("test").Counter("a").Inc(1)
+	sink := &fakeSink{}
+	rep := NewReporter(r, sink, Options{Interval: time.Hour})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := rep.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if sink.count() == 0 {
+		t.Fatal("nothing flushed")
+	}
+}
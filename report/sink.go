@@ -0,0 +1,62 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package report
+
+import (
+	"context"
+	"time"
+
+	"github.com/spacemonkeygo/monkit/v3"
+)
+
+// Point is the value of one field of a series at the time it was collected,
+// along with the field's description.
+type Point struct {
+	Key   monkit.SeriesKey
+	Field string
+	Value float64
+	Time  time.Time
+	Info  monkit.StatInfo
+}
+
+// Sink sends Points somewhere. A Reporter calls Send from one goroutine at a
+// time. Sinks that also implement io.Closer are closed when their Reporter
+// is.
+type Sink interface {
+	// Send sends a batch of points. If it returns an error, the Reporter
+	// tries to send the batch again later, so Sinks should not send part of
+	// a batch if they can help it.
+	Send(ctx context.Context, points []Point) error
+}
+
+// SinkFunc is a function that implements Sink.
+type SinkFunc func(ctx context.Context, points []Point) error
+
+// Send implements Sink.
+func (f SinkFunc) Send(ctx context.Context, points []Point) error {
+	return f(ctx, points)
+}
+
+// Collect returns the current values of the fields of r as Points, with the
+// descriptions from r's StatDescriber implementation.
+func Collect(r *monkit.Registry, now time.Time) []Point {
+	catalog := monkit.NewStatCatalog(r)
+	var points []Point
+	r.Stats(func(key monkit.SeriesKey, field string, val float64) {
+		info, _ := catalog.Lookup(key, field)
+		points = append(points, Point{Key: key, Field: field, Value: val, Time: now, Info: info})
+	})
+	return points
+}
@@ -0,0 +1,42 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package statsd sends monkit statistics to a StatsD or DogStatsD agent.
+
+A Sink works in two modes. As a report.Sink, it sends the statistics
+collected by a report.Reporter: fields of kind monkit.StatKindCounter and
+monkit.StatKindHistogram become counters of their increase since the last
+Send, and all other fields become gauges. With Forward, it instead passes on
+the raw events of a Registry's Meters as counters and of its DurationVals as
+timings, or as distributions with Options.Distributions, leaving the
+aggregation to the agent.
+
+	sink, err := statsd.Dial("udp", "localhost:8125", statsd.Options{DogStatsD: true})
+	...
+	defer sink.Close()
+	defer sink.Forward(monkit.Default)()
+
+Metric names are the measurement followed by the field, joined by dots, as
+in "function.total". Forwarded events are named after their measurement
+alone. With Options.DogStatsD, the series' tags are sent as DogStatsD tags;
+otherwise their values are added to the name after the measurement, ordered
+by tag key. Characters StatsD reserves are replaced with underscores.
+
+Lines are coalesced into packets of at most Options.MaxPacketSize bytes.
+Forwarded events are queued without blocking, dropping them while
+Options.EventQueueSize events are waiting, and buffered for up to
+Options.FlushInterval.
+*/
+package statsd // import "github.com/spacemonkeygo/monkit/v3/report/statsd"
@@ -0,0 +1,392 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statsd
+
+import (
+	"context"
+	"math"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spacemonkeygo/monkit/v3"
+	"github.com/spacemonkeygo/monkit/v3/report"
+)
+
+const (
+	// DefaultUDPPacketSize is the default packet size limit over UDP, which
+	// keeps packets from being fragmented on common networks.
+	DefaultUDPPacketSize = 1432
+	// DefaultUnixPacketSize is the default packet size limit over Unix
+	// datagram sockets.
+	DefaultUnixPacketSize = 8192
+	// DefaultFlushInterval is the default of Options.FlushInterval.
+	DefaultFlushInterval = 100 * time.Millisecond
+	// DefaultCounterExpiry is the default of Options.CounterExpiry.
+	DefaultCounterExpiry = 10 * time.Minute
+	// DefaultEventQueueSize is the default of Options.EventQueueSize.
+	DefaultEventQueueSize = 4096
+)
+
+// Options configure a Sink.
+type Options struct {
+	// Prefix is prepended to all metric names, such as "myapp.".
+	Prefix string
+	// DogStatsD sends tags the DogStatsD way rather than in metric names.
+	DogStatsD bool
+	// Distributions sends forwarded durations as DogStatsD distributions
+	// instead of timings.
+	Distributions bool
+	// MaxPacketSize limits the size of packets. It defaults to
+	// DefaultUDPPacketSize, or DefaultUnixPacketSize for Unix sockets.
+	MaxPacketSize int
+	// FlushInterval is the longest forwarded events are buffered before
+	// they are sent. Defaults to DefaultFlushInterval.
+	FlushInterval time.Duration
+	// EventQueueSize is the most forwarded events waiting to be sent. Events
+	// are dropped while the queue is full. Defaults to DefaultEventQueueSize.
+	EventQueueSize int
+	// CounterExpiry is how long a counter field can be missing from Sends
+	// before the Sink forgets its last value, after which its next value is
+	// sent as a whole. Defaults to DefaultCounterExpiry.
+	CounterExpiry time.Duration
+}
+
+// Sink sends statistics or raw events to a StatsD agent. Create one with
+// Dial or NewSink.
+type Sink struct {
+	conn net.Conn
+	opts Options
+
+	mtx      sync.Mutex              // serializes Sends
+	counters map[string]counterState // by metric
+	expired  time.Time               // when expired counters were last removed
+	now      func() time.Time
+
+	// forwarded events are sent by the forward goroutine.
+	events    chan []byte
+	flushes   chan chan error
+	closing   chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// counterState is the last sent value of a counter field.
+type counterState struct {
+	value float64
+	sent  time.Time
+}
+
+// Dial creates a Sink that sends to addr over network, which is "udp",
+// "udp4", "udp6" or "unixgram".
+func Dial(network, addr string, opts Options) (*Sink, error) {
+	conn, err := net.Dial(network, addr)
+	if err != nil {
+		return nil, err
+	}
+	if opts.MaxPacketSize <= 0 && strings.HasPrefix(network, "unix") {
+		opts.MaxPacketSize = DefaultUnixPacketSize
+	}
+	return NewSink(conn, opts), nil
+}
+
+// NewSink creates a Sink that writes packets to conn, and closes it when the
+// Sink is closed.
+func NewSink(conn net.Conn, opts Options) *Sink {
+	if opts.MaxPacketSize <= 0 {
+		opts.MaxPacketSize = DefaultUDPPacketSize
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = DefaultFlushInterval
+	}
+	if opts.CounterExpiry <= 0 {
+		opts.CounterExpiry = DefaultCounterExpiry
+	}
+	if opts.EventQueueSize <= 0 {
+		opts.EventQueueSize = DefaultEventQueueSize
+	}
+	s := &Sink{
+		conn:     conn,
+		opts:     opts,
+		counters: map[string]counterState{},
+		now:      time.Now,
+
+		events:  make(chan []byte, opts.EventQueueSize),
+		flushes: make(chan chan error),
+		closing: make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go s.forward()
+	return s
+}
+
+// Send implements report.Sink. It sends fields of counter kinds as StatsD
+// counters of how much they increased since the previous Send, or of their
+// value when it is the first or the field was reset, and all other fields as
+// gauges. NaN and infinite values are left out. The value of a counter field
+// is only kept once the packet with its line was sent, so a failed Send can
+// be retried with the same points without losing increases.
+func (s *Sink) Send(ctx context.Context, points []report.Point) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	now := s.now()
+	s.expireLocked(now)
+
+	// the values of counters are only kept once their packet was sent.
+	var packet []byte
+	pending := map[string]float64{}
+	var firstErr error
+	send := func() {
+		if len(packet) == 0 {
+			return
+		}
+		_, err := s.conn.Write(packet)
+		packet = packet[:0]
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+		} else {
+			for id, val := range pending {
+				s.counters[id] = counterState{value: val, sent: now}
+			}
+		}
+		pending = map[string]float64{}
+	}
+	add := func(line []byte) {
+		packet = appendLine(packet, line, s.opts.MaxPacketSize, send)
+	}
+
+	for _, p := range points {
+		if math.IsNaN(p.Value) || math.IsInf(p.Value, 0) {
+			continue
+		}
+		name, tags := s.metric(p.Key, p.Field)
+
+		switch p.Info.Kind {
+		case monkit.StatKindCounter, monkit.StatKindHistogram:
+			id := name + "|" + tags
+			delta := p.Value
+			if last, ok := s.counters[id]; ok && p.Value >= last.value {
+				delta = p.Value - last.value
+			}
+			if delta == 0 {
+				s.counters[id] = counterState{value: p.Value, sent: now}
+				continue
+			}
+			add(s.line(name, formatFloat(delta), "c", tags))
+			pending[id] = p.Value
+		default:
+			if p.Value < 0 && !s.opts.DogStatsD {
+				// a leading sign makes a StatsD gauge change by the value,
+				// so it has to be set to zero first.
+				add(s.line(name, "0", "g", tags))
+			}
+			add(s.line(name, formatFloat(p.Value), "g", tags))
+		}
+	}
+	send()
+	return firstErr
+}
+
+// expireLocked forgets the counters that were not sent for CounterExpiry.
+// It only looks for them once per CounterExpiry. s.mtx must be held.
+func (s *Sink) expireLocked(now time.Time) {
+	if now.Sub(s.expired) < s.opts.CounterExpiry {
+		return
+	}
+	s.expired = now
+	for id, state := range s.counters {
+		if now.Sub(state.sent) >= s.opts.CounterExpiry {
+			delete(s.counters, id)
+		}
+	}
+}
+
+// Forward makes the Sink pass on the raw events of the Meters and
+// DurationVals of r, until the returned cancel method is called. See
+// Registry.ObserveEvents. The events are queued without blocking, and sent
+// by a goroutine of the Sink. Errors sending forwarded events are ignored.
+func (s *Sink) Forward(r *monkit.Registry) (cancel func()) {
+	return r.ObserveEvents(s)
+}
+
+// ObserveMark implements monkit.EventObserver.
+func (s *Sink) ObserveMark(key monkit.SeriesKey, amount int64) {
+	name, tags := s.metric(key, "")
+	s.event(s.line(name, strconv.FormatInt(amount, 10), "c", tags))
+}
+
+// ObserveDuration implements monkit.EventObserver.
+func (s *Sink) ObserveDuration(key monkit.SeriesKey, val time.Duration) {
+	name, tags := s.metric(key, "")
+	typ := "ms"
+	if s.opts.Distributions {
+		typ = "d"
+	}
+	s.event(s.line(name, formatFloat(float64(val)/float64(time.Millisecond)), typ, tags))
+}
+
+// event queues the line of a forwarded event, or drops it if the queue is
+// full.
+func (s *Sink) event(line []byte) {
+	select {
+	case s.events <- line:
+	default:
+	}
+}
+
+// forward sends the queued events, in packets that are sent once they are
+// full, FlushInterval after their first event was queued, or when the Sink
+// is flushed or closed.
+func (s *Sink) forward() {
+	defer close(s.done)
+
+	var packet []byte
+	var timer <-chan time.Time
+	var err error
+	send := func() {
+		if len(packet) > 0 {
+			if _, writeErr := s.conn.Write(packet); writeErr != nil && err == nil {
+				err = writeErr
+			}
+			packet = packet[:0]
+		}
+		timer = nil
+	}
+	add := func(line []byte) {
+		packet = appendLine(packet, line, s.opts.MaxPacketSize, send)
+		if timer == nil {
+			timer = time.After(s.opts.FlushInterval)
+		}
+	}
+	// drain adds the events queued so far, sends them, and returns the first
+	// error since the previous drain.
+	drain := func() error {
+		for n := len(s.events); n > 0; n-- {
+			add(<-s.events)
+		}
+		send()
+		drained := err
+		err = nil
+		return drained
+	}
+
+	for {
+		select {
+		case line := <-s.events:
+			add(line)
+		case <-timer:
+			send()
+		case result := <-s.flushes:
+			result <- drain()
+		case <-s.closing:
+			_ = drain()
+			return
+		}
+	}
+}
+
+// Flush sends the queued forwarded events, and returns the first error
+// sending forwarded events since the previous Flush.
+func (s *Sink) Flush() error {
+	result := make(chan error, 1)
+	select {
+	case s.flushes <- result:
+		return <-result
+	case <-s.done:
+		return nil
+	}
+}
+
+// Close sends the queued forwarded events and closes the connection.
+func (s *Sink) Close() error {
+	err := s.Flush()
+	s.closeOnce.Do(func() { close(s.closing) })
+	<-s.done
+	if closeErr := s.conn.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// appendLine adds line to packet, calling send first if the line doesn't fit
+// into maxSize. send must empty the packet.
+func appendLine(packet, line []byte, maxSize int, send func()) []byte {
+	if len(packet) > 0 && len(packet)+1+len(line) > maxSize {
+		send()
+		packet = packet[:0]
+	}
+	if len(packet) > 0 {
+		packet = append(packet, '\n')
+	}
+	return append(packet, line...)
+}
+
+var (
+	nameReplacer = strings.NewReplacer(":", "_", "|", "_", "@", "_", "#", "_", ",", "_",
+		" ", "_", "\t", "_", "\n", "_")
+	tagReplacer = strings.NewReplacer("|", "_", ",", "_", " ", "_", "\t", "_", "\n", "_")
+)
+
+// metric returns the name of field of the series key, and its DogStatsD tags
+// if enabled. An empty field is left out of the name.
+func (s *Sink) metric(key monkit.SeriesKey, field string) (name, tags string) {
+	all := key.Tags.All()
+	keys := make([]string, 0, len(all))
+	for k := range all {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := []string{s.opts.Prefix + key.Measurement}
+	if s.opts.DogStatsD {
+		pairs := make([]string, 0, len(keys))
+		for _, k := range keys {
+			pairs = append(pairs, tagReplacer.Replace(k)+":"+tagReplacer.Replace(all[k]))
+		}
+		tags = strings.Join(pairs, ",")
+	} else {
+		for _, k := range keys {
+			parts = append(parts, all[k])
+		}
+	}
+	if field != "" {
+		parts = append(parts, field)
+	}
+	return nameReplacer.Replace(strings.Join(parts, ".")), tags
+}
+
+func (s *Sink) line(name, value, typ, tags string) []byte {
+	line := make([]byte, 0, len(name)+len(value)+len(typ)+len(tags)+4)
+	line = append(line, name...)
+	line = append(line, ':')
+	line = append(line, value...)
+	line = append(line, '|')
+	line = append(line, typ...)
+	if tags != "" {
+		line = append(line, "|#"...)
+		line = append(line, tags...)
+	}
+	return line
+}
+
+func formatFloat(val float64) string {
+	return strconv.FormatFloat(val, 'f', -1, 64)
+}
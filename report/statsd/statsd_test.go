@@ -0,0 +1,250 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statsd
+
+import (
+	"context"
+	"errors"
+	"net"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spacemonkeygo/monkit/v3"
+	"github.com/spacemonkeygo/monkit/v3/report"
+)
+
+func listen(t *testing.T, network, addr string) net.PacketConn {
+	t.Helper()
+	conn, err := net.ListenPacket(network, addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+// receive reads packets until it has n lines.
+func receive(t *testing.T, conn net.PacketConn, n int) (packets []string, lines []string) {
+	t.Helper()
+	buf := make([]byte, 1<<16)
+	for len(lines) < n {
+		_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		size, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		packets = append(packets, string(buf[:size]))
+		lines = append(lines, strings.Split(string(buf[:size]), "\n")...)
+	}
+	return packets, lines
+}
+
+func points(total, gauge float64) []report.Point {
+	key := monkit.NewSeriesKey("function").WithTag("scope", "pkg").WithTag("name", "work")
+	return []report.Point{
+		{Key: key, Field: "total", Value: total, Info: monkit.StatInfo{Kind: monkit.StatKindCounter}},
+		{Key: key, Field: "times_recent", Value: gauge, Info: monkit.StatInfo{Kind: monkit.StatKindGauge}},
+	}
+}
+
+func TestSend(t *testing.T) {
+	ctx := context.Background()
+	server := listen(t, "udp", "127.0.0.1:0")
+	sink, err := Dial("udp", server.LocalAddr().String(), Options{Prefix: "app."})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = sink.Close() }()
+
+	if err := sink.Send(ctx, points(5, 1.5)); err != nil {
+		t.Fatal(err)
+	}
+	if err := sink.Send(ctx, points(7, -2)); err != nil {
+		t.Fatal(err)
+	}
+	_, lines := receive(t, server, 5)
+	expected := []string{
+		"app.function.work.pkg.total:5|c",
+		"app.function.work.pkg.times_recent:1.5|g",
+		"app.function.work.pkg.total:2|c",
+		"app.function.work.pkg.times_recent:0|g",
+		"app.function.work.pkg.times_recent:-2|g",
+	}
+	if strings.Join(lines, "\n") != strings.Join(expected, "\n") {
+		t.Fatalf("got %q", lines)
+	}
+}
+
+// fakeConn records the packets written to it, or fails to write them.
+type fakeConn struct {
+	net.Conn
+	fail    bool
+	packets []string
+}
+
+func (c *fakeConn) Write(p []byte) (int, error) {
+	if c.fail {
+		return 0, errors.New("unreachable")
+	}
+	c.packets = append(c.packets, string(p))
+	return len(p), nil
+}
+
+func (c *fakeConn) Close() error { return nil }
+
+func TestSendRetry(t *testing.T) {
+	ctx := context.Background()
+	conn := &fakeConn{}
+	sink := NewSink(conn, Options{})
+	defer func() { _ = sink.Close() }()
+
+	if err := sink.Send(ctx, points(5, 1)); err != nil {
+		t.Fatal(err)
+	}
+	conn.fail = true
+	if err := sink.Send(ctx, points(7, 1)); err == nil {
+		t.Fatal("expected an error")
+	}
+	// the retry sends the increase again.
+	conn.fail = false
+	if err := sink.Send(ctx, points(7, 1)); err != nil {
+		t.Fatal(err)
+	}
+	if len(conn.packets) != 2 || !strings.HasPrefix(conn.packets[1], "function.work.pkg.total:2|c\n") {
+		t.Fatalf("got %q", conn.packets)
+	}
+}
+
+func TestCounterExpiry(t *testing.T) {
+	ctx := context.Background()
+	conn := &fakeConn{}
+	sink := NewSink(conn, Options{CounterExpiry: time.Minute})
+	defer func() { _ = sink.Close() }()
+	now := time.Now()
+	sink.now = func() time.Time { return now }
+
+	if err := sink.Send(ctx, points(5, 1)); err != nil {
+		t.Fatal(err)
+	}
+	if len(sink.counters) != 1 {
+		t.Fatalf("got %d counters", len(sink.counters))
+	}
+
+	now = now.Add(time.Minute)
+	if err := sink.Send(ctx, nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(sink.counters) != 0 {
+		t.Fatalf("got %d counters after they expired", len(sink.counters))
+	}
+}
+
+func TestDogStatsDCoalescing(t *testing.T) {
+	ctx := context.Background()
+	server := listen(t, "udp", "127.0.0.1:0")
+	sink, err := Dial("udp", server.LocalAddr().String(), Options{DogStatsD: true, MaxPacketSize: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = sink.Close() }()
+
+	if err := sink.Send(ctx, append(points(1, 2), points(3, 4)...)); err != nil {
+		t.Fatal(err)
+	}
+	packets, lines := receive(t, server, 4)
+	for _, packet := range packets {
+		if len(packet) > 100 {
+			t.Fatalf("got packet of %d bytes", len(packet))
+		}
+	}
+	if len(packets) != 2 || lines[0] != "function.total:1|c|#name:work,scope:pkg" ||
+		lines[3] != "function.times_recent:4|g|#name:work,scope:pkg" {
+		t.Fatalf("got %q", packets)
+	}
+}
+
+func TestForward(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("no unix datagram sockets")
+	}
+	path := filepath.Join(t.TempDir(), "statsd.sock")
+	server := listen(t, "unixgram", path)
+	sink, err := Dial("unixgram", path, Options{DogStatsD: true, Distributions: true,
+		FlushInterval: time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = sink.Close() }()
+
+	r := monkit.NewRegistry()
+	mon := r.ScopeNamed("test")
+	cancel := sink.Forward(r)
+	mon.Meter("requests", monkit.NewSeriesTag("kind", "get")).Mark(3)
+	mon.DurationVal("latency").Observe(1500 * time.Microsecond)
+	cancel()
+	mon.Meter("requests").Mark(1)
+
+	_, lines := receive(t, server, 2)
+	if len(lines) != 2 || lines[0] != "requests:3|c|#kind:get" || lines[1] != "latency:1.5|d" {
+		t.Fatalf("got %q", lines)
+	}
+}
+
+// blockingConn blocks writes until it is released.
+type blockingConn struct {
+	net.Conn
+	release chan struct{}
+
+	mtx   sync.Mutex
+	lines int
+}
+
+func (c *blockingConn) Write(p []byte) (int, error) {
+	<-c.release
+	c.mtx.Lock()
+	c.lines += strings.Count(string(p), "\n") + 1
+	c.mtx.Unlock()
+	return len(p), nil
+}
+
+func (c *blockingConn) Close() error { return nil }
+
+func TestForwardQueueFull(t *testing.T) {
+	conn := &blockingConn{release: make(chan struct{})}
+	sink := NewSink(conn, Options{EventQueueSize: 2, MaxPacketSize: 1})
+
+	r := monkit.NewRegistry()
+	cancel := sink.Forward(r)
+	defer cancel()
+	meter := r.ScopeNamed("test").Meter("requests")
+	// marking does not wait for the blocked connection.
+	for i := 0; i < 100; i++ {
+		meter.Mark(1)
+	}
+
+	close(conn.release)
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+	conn.mtx.Lock()
+	defer conn.mtx.Unlock()
+	if conn.lines == 0 || conn.lines >= 100 {
+		t.Fatalf("got %d lines, expected the full queue to drop some", conn.lines)
+	}
+}
@@ -16,25 +16,53 @@ package monkit
 
 import (
 	"fmt"
+	"reflect"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/spacemonkeygo/monkit/v3/monotime"
 )
 
 // Scope represents a named collection of StatSources. Scopes are constructed
 // through Registries.
 type Scope struct {
+	// sync/atomic things. the 64-bit fields come first so they are aligned
+	// on 32-bit platforms.
+	ttl   int64 // time.Duration, see SetTTL
+	limit int64 // see SetCardinalityLimit
+
 	r       *Registry
 	name    string
 	mtx     sync.RWMutex
 	sources map[string]StatSource
 	chains  []StatSource
+
+	// cardinality bookkeeping, protected by mtx. series are the namespaced
+	// measurement names of the sources. overflowed maps the names of the
+	// sources that were collapsed into an overflow series to the name of its
+	// source, by series, so they are found without taking the write lock.
+	seriesOf    map[string]sourceSeries // source name to series
+	cardinality map[string]int
+	overflows   map[string]int64
+	overflowed  map[string]map[string]string
+
+	constTags  atomic.Pointer[[]SeriesTag] // see SetTags
+	expireMtx  sync.Mutex
+	lastExpire time.Time
+	activity   map[string]sourceActivity
 }
 
 func newScope(r *Registry, name string) *Scope {
 	return &Scope{
-		r:       r,
-		name:    name,
-		sources: map[string]StatSource{}}
+		r:           r,
+		name:        name,
+		sources:     map[string]StatSource{},
+		seriesOf:    map[string]sourceSeries{},
+		cardinality: map[string]int{},
+		overflows:   map[string]int64{},
+		overflowed:  map[string]map[string]string{}}
 }
 
 // Func retrieves or creates a Func named after the currently executing
@@ -43,11 +71,13 @@ func (s *Scope) Func() *Func {
 	return s.FuncNamed(callerFunc(0))
 }
 
-func (s *Scope) newSource(name string, constructor func() StatSource) (
+func (s *Scope) newSource(name, series string, tagged bool,
+	constructor func() StatSource,
+	overflow func() (name string, constructor func() StatSource)) (
 	rv StatSource) {
 
 	s.mtx.RLock()
-	source, exists := s.sources[name]
+	source, exists := s.lookupSource(name, series)
 	s.mtx.RUnlock()
 
 	if exists {
@@ -55,18 +85,70 @@ func (s *Scope) newSource(name string, constructor func() StatSource) (
 	}
 
 	s.mtx.Lock()
-	if source, exists := s.sources[name]; exists {
+	if source, exists := s.lookupSource(name, series); exists {
 		s.mtx.Unlock()
 		return source
 	}
 
+	if tagged {
+		if limit := s.cardinalityLimit(); limit > 0 && s.cardinality[series] >= limit {
+			overflowName, overflowConstructor := overflow()
+			source, exists := s.sources[overflowName]
+			if !exists {
+				source = overflowConstructor()
+				s.sources[overflowName] = source
+				s.seriesOf[overflowName] = sourceSeries{series: series, overflow: true}
+			}
+			if s.overflowed[series] == nil {
+				s.overflowed[series] = map[string]string{}
+			}
+			s.overflowed[series][name] = overflowName
+			s.overflows[series]++
+			s.mtx.Unlock()
+
+			if !exists {
+				s.expire(monotime.Now())
+			}
+			return source
+		}
+	}
+
 	ss := constructor()
 	s.sources[name] = ss
+	s.seriesOf[name] = sourceSeries{series: series}
+	s.cardinality[series]++
 	s.mtx.Unlock()
 
+	s.expire(monotime.Now())
 	return ss
 }
 
+// lookupSource returns the source named name, or the overflow source it was
+// collapsed into. s.mtx must be held.
+func (s *Scope) lookupSource(name, series string) (StatSource, bool) {
+	if source, exists := s.sources[name]; exists {
+		return source, true
+	}
+	if overflowName, exists := s.overflowed[series][name]; exists {
+		source, exists := s.sources[overflowName]
+		return source, exists
+	}
+	return nil, false
+}
+
+// newTaggedSource calls newSource for a source named after the measurement
+// name and tags, where constructor gets the tags to use, which are changed to
+// OverflowTagValue when the series is over its cardinality limit.
+func (s *Scope) newTaggedSource(namespace, name string, tags []SeriesTag,
+	constructor func(tags []SeriesTag) StatSource) StatSource {
+	return s.newSource(sourceName(namespace, name, tags), namespace+name, len(tags) > 0,
+		func() StatSource { return constructor(tags) },
+		func() (string, func() StatSource) {
+			tags := overflowTags(tags)
+			return sourceName(namespace, name, tags), func() StatSource { return constructor(tags) }
+		})
+}
+
 func sourceName(namespace, name string, tags []SeriesTag) string {
 	var sourceNameSize int
 	sourceNameSize += len(namespace) + len(name) + len(tags)*2
@@ -93,8 +175,12 @@ func sourceName(namespace, name string, tags []SeriesTag) string {
 // unique Func. SeriesTags are not sorted, so keep the order consistent to avoid
 // unintentionally creating new unique Funcs.
 func (s *Scope) FuncNamed(name string, tags ...SeriesTag) *Func {
-	source := s.newSource(sourceName("func:", name, tags), func() StatSource {
-		return newFunc(s, NewSeriesKey("function").WithTag("name", name).WithTags(tags...))
+	source := s.newTaggedSource("func:", name, tags, func(tags []SeriesTag) StatSource {
+		f := newFunc(s, NewSeriesKey("function").WithTag("name", name).WithTags(tags...))
+		if accuracy := s.r.sketchesAccuracy(); accuracy > 0 {
+			f.UseSketch(accuracy)
+		}
+		return f
 	})
 	f, ok := source.(*Func)
 	if !ok {
@@ -120,9 +206,19 @@ func (s *Scope) Funcs(cb func(f *Func)) {
 }
 
 // Meter retrieves or creates a Meter named after the given name. See Event.
+// New Meters use the window configured with Registry.SetMeterWindow.
 func (s *Scope) Meter(name string, tags ...SeriesTag) *Meter {
-	source := s.newSource(sourceName("", name, tags), func() StatSource {
-		return NewMeter(NewSeriesKey(name).WithTags(tags...))
+	return s.MeterWithWindow(name, s.r.defaultMeterWindow(), tags...)
+}
+
+// MeterWithWindow is like Meter, but a new Meter uses the given window. The
+// window of an existing Meter is not changed.
+func (s *Scope) MeterWithWindow(name string, window MeterWindow, tags ...SeriesTag) *Meter {
+	window.validate()
+	source := s.newTaggedSource("", name, tags, func(tags []SeriesTag) StatSource {
+		m := NewMeterWithWindow(NewSeriesKey(name).WithTags(tags...), window)
+		m.events = &s.r.events
+		return m
 	})
 	m, ok := source.(*Meter)
 	if !ok {
@@ -141,7 +237,7 @@ func (s *Scope) Event(name string, tags ...SeriesTag) {
 // DiffMeter retrieves or creates a DiffMeter after the given name and two
 // submeters.
 func (s *Scope) DiffMeter(name string, m1, m2 *Meter, tags ...SeriesTag) {
-	source := s.newSource(sourceName("", name, tags), func() StatSource {
+	source := s.newTaggedSource("", name, tags, func(tags []SeriesTag) StatSource {
 		return NewDiffMeter(NewSeriesKey(name).WithTags(tags...), m1, m2)
 	})
 	if _, ok := source.(*DiffMeter); !ok {
@@ -152,8 +248,12 @@ func (s *Scope) DiffMeter(name string, m1, m2 *Meter, tags ...SeriesTag) {
 
 // IntVal retrieves or creates an IntVal after the given name.
 func (s *Scope) IntVal(name string, tags ...SeriesTag) *IntVal {
-	source := s.newSource(sourceName("", name, tags), func() StatSource {
-		return NewIntVal(NewSeriesKey(name).WithTags(tags...))
+	source := s.newTaggedSource("", name, tags, func(tags []SeriesTag) StatSource {
+		v := NewIntVal(NewSeriesKey(name).WithTags(tags...))
+		if accuracy := s.r.sketchesAccuracy(); accuracy > 0 {
+			v.UseSketch(accuracy)
+		}
+		return v
 	})
 	m, ok := source.(*IntVal)
 	if !ok {
@@ -171,8 +271,12 @@ func (s *Scope) IntValf(template string, args ...interface{}) *IntVal {
 
 // FloatVal retrieves or creates a FloatVal after the given name.
 func (s *Scope) FloatVal(name string, tags ...SeriesTag) *FloatVal {
-	source := s.newSource(sourceName("", name, tags), func() StatSource {
-		return NewFloatVal(NewSeriesKey(name).WithTags(tags...))
+	source := s.newTaggedSource("", name, tags, func(tags []SeriesTag) StatSource {
+		v := NewFloatVal(NewSeriesKey(name).WithTags(tags...))
+		if accuracy := s.r.sketchesAccuracy(); accuracy > 0 {
+			v.UseSketch(accuracy)
+		}
+		return v
 	})
 	m, ok := source.(*FloatVal)
 	if !ok {
@@ -190,7 +294,7 @@ func (s *Scope) FloatValf(template string, args ...interface{}) *FloatVal {
 
 // BoolVal retrieves or creates a BoolVal after the given name.
 func (s *Scope) BoolVal(name string, tags ...SeriesTag) *BoolVal {
-	source := s.newSource(sourceName("", name, tags), func() StatSource {
+	source := s.newTaggedSource("", name, tags, func(tags []SeriesTag) StatSource {
 		return NewBoolVal(NewSeriesKey(name).WithTags(tags...))
 	})
 	m, ok := source.(*BoolVal)
@@ -209,7 +313,7 @@ func (s *Scope) BoolValf(template string, args ...interface{}) *BoolVal {
 
 // StructVal retrieves or creates a StructVal after the given name.
 func (s *Scope) StructVal(name string, tags ...SeriesTag) *StructVal {
-	source := s.newSource(sourceName("", name, tags), func() StatSource {
+	source := s.newTaggedSource("", name, tags, func(tags []SeriesTag) StatSource {
 		return NewStructVal(NewSeriesKey(name).WithTags(tags...))
 	})
 	m, ok := source.(*StructVal)
@@ -222,8 +326,13 @@ func (s *Scope) StructVal(name string, tags ...SeriesTag) *StructVal {
 
 // DurationVal retrieves or creates a DurationVal after the given name.
 func (s *Scope) DurationVal(name string, tags ...SeriesTag) *DurationVal {
-	source := s.newSource(sourceName("", name, tags), func() StatSource {
-		return NewDurationVal(NewSeriesKey(name).WithTags(tags...))
+	source := s.newTaggedSource("", name, tags, func(tags []SeriesTag) StatSource {
+		v := NewDurationVal(NewSeriesKey(name).WithTags(tags...))
+		v.events = &s.r.events
+		if accuracy := s.r.sketchesAccuracy(); accuracy > 0 {
+			v.UseSketch(accuracy)
+		}
+		return v
 	})
 	m, ok := source.(*DurationVal)
 	if !ok {
@@ -233,11 +342,46 @@ func (s *Scope) DurationVal(name string, tags ...SeriesTag) *DurationVal {
 	return m
 }
 
+// DurationValSketch is like DurationVal, but the DurationVal estimates
+// quantiles with a Sketch rather than a sample reservoir. The Sketch uses the
+// accuracy configured with Registry.UseSketches, or DefaultSketchAccuracy.
+func (s *Scope) DurationValSketch(name string, tags ...SeriesTag) *DurationVal {
+	v := s.DurationVal(name, tags...)
+	v.ensureSketch(s.sketchAccuracy())
+	return v
+}
+
+// IntValSketch is like IntVal, but the IntVal estimates quantiles with a
+// Sketch. See DurationValSketch.
+func (s *Scope) IntValSketch(name string, tags ...SeriesTag) *IntVal {
+	v := s.IntVal(name, tags...)
+	v.ensureSketch(s.sketchAccuracy())
+	return v
+}
+
+// FloatValSketch is like FloatVal, but the FloatVal estimates quantiles with a
+// Sketch. See DurationValSketch.
+func (s *Scope) FloatValSketch(name string, tags ...SeriesTag) *FloatVal {
+	v := s.FloatVal(name, tags...)
+	v.ensureSketch(s.sketchAccuracy())
+	return v
+}
+
+func (s *Scope) sketchAccuracy() float64 {
+	if accuracy := s.r.sketchesAccuracy(); accuracy > 0 {
+		return accuracy
+	}
+	return DefaultSketchAccuracy
+}
+
 // RawValk retrieves or creates a RawVal with a given key and aggregations.
 func (s *Scope) RawValk(key SeriesKey, aggregations ...Aggregate) *RawVal {
-	source := s.newSource(key.String(), func() StatSource {
-		return NewRawVal(key, aggregations...)
-	})
+	source := s.newSource(key.String(), key.Measurement, key.Tags.Len() > 0,
+		func() StatSource { return NewRawVal(key, aggregations...) },
+		func() (string, func() StatSource) {
+			key := overflowKey(key)
+			return key.String(), func() StatSource { return NewRawVal(key, aggregations...) }
+		})
 	m, ok := source.(*RawVal)
 	if !ok {
 		panic(fmt.Sprintf("%s already used for another stats source: %#v", key, source))
@@ -258,8 +402,12 @@ func (s *Scope) RawValf(template string, args ...interface{}) *RawVal {
 
 // Timer retrieves or creates a Timer after the given name.
 func (s *Scope) Timer(name string, tags ...SeriesTag) *Timer {
-	source := s.newSource(sourceName("", name, tags), func() StatSource {
-		return NewTimer(NewSeriesKey(name).WithTags(tags...))
+	source := s.newTaggedSource("", name, tags, func(tags []SeriesTag) StatSource {
+		v := NewTimer(NewSeriesKey(name).WithTags(tags...))
+		if accuracy := s.r.sketchesAccuracy(); accuracy > 0 {
+			v.UseSketch(accuracy)
+		}
+		return v
 	})
 	m, ok := source.(*Timer)
 	if !ok {
@@ -271,7 +419,7 @@ func (s *Scope) Timer(name string, tags ...SeriesTag) *Timer {
 
 // Counter retrieves or creates a Counter after the given name.
 func (s *Scope) Counter(name string, tags ...SeriesTag) *Counter {
-	source := s.newSource(sourceName("", name, tags), func() StatSource {
+	source := s.newTaggedSource("", name, tags, func(tags []SeriesTag) StatSource {
 		return NewCounter(NewSeriesKey(name).WithTags(tags...))
 	})
 	m, ok := source.(*Counter)
@@ -282,29 +430,140 @@ func (s *Scope) Counter(name string, tags ...SeriesTag) *Counter {
 	return m
 }
 
+// Histogram retrieves or creates a Histogram after the given name. The
+// buckets are only used when the Histogram is created. See NewHistogram.
+func (s *Scope) Histogram(name string, buckets []float64, tags ...SeriesTag) *Histogram {
+	checkBuckets(buckets)
+	source := s.newTaggedSource("", name, tags, func(tags []SeriesTag) StatSource {
+		return NewHistogram(NewSeriesKey(name).WithTags(tags...), buckets)
+	})
+	m, ok := source.(*Histogram)
+	if !ok {
+		panic(fmt.Sprintf("%s already used for another stats source: %#v",
+			name, source))
+	}
+	return m
+}
+
 // Gauge registers a callback that returns a float as the given name in the
 // Scope's StatSource table.
 func (s *Scope) Gauge(name string, cb func() float64) {
-	type gauge struct{ StatSource }
+	s.GaugeTagged(name, cb)
+}
+
+// GaugeTagged is like Gauge, but the value is reported with the given tags.
+// Each unique combination of name and tags is a separate gauge.
+// Like other tagged sources, tagged gauges are subject to the cardinality
+// limit. Gauges over the limit are not reported, and the overflow series'
+// gauge reports how many of them there are instead.
+func (s *Scope) GaugeTagged(name string, cb func() float64, tags ...SeriesTag) {
+	s.setGauge(sourceName("", name, tags), name, len(tags) > 0,
+		gauge{key: NewSeriesKey(name).WithTags(tags...), cb: cb},
+		func() (string, StatSource) {
+			tags := overflowTags(tags)
+			overflowName := sourceName("", name, tags)
+			return overflowName, gauge{key: NewSeriesKey(name).WithTags(tags...), cb: func() float64 {
+				return float64(s.overflowedInto(name, overflowName))
+			}}
+		})
+}
 
-	// gauges allow overwriting
+// GaugeMulti registers a callback that can report any number of fields and
+// tag sets under the given name. On every Stats call, cb is called with an
+// emit function, which reports val as the given field with the given tags.
+//
+//	mon.GaugeMulti("queue", func(emit func(string, float64, ...monkit.SeriesTag)) {
+//	  for name, q := range queues {
+//	    tag := monkit.NewSeriesTag("queue", name)
+//	    emit("length", float64(q.Len()), tag)
+//	    emit("capacity", float64(q.Cap()), tag)
+//	  }
+//	})
+func (s *Scope) GaugeMulti(name string,
+	cb func(emit func(field string, val float64, tags ...SeriesTag))) {
+	s.setGauge(name, name, false, &multiGauge{name: name, cb: cb}, nil)
+}
+
+// setGauge registers a callback gauge, which, unlike other sources, can be
+// overwritten by a gauge of the same type. Tagged gauges over the cardinality
+// limit of series are collapsed into the gauge returned by overflow instead,
+// like newSource does, which is only registered once.
+func (s *Scope) setGauge(name, series string, tagged bool, source StatSource,
+	overflow func() (name string, source StatSource)) {
 	s.mtx.Lock()
 	defer s.mtx.Unlock()
 
-	if source, exists := s.sources[name]; exists {
-		if _, ok := source.(gauge); !ok {
-			panic(fmt.Sprintf("%s already used for another stats source: %#v",
-				name, source))
+	if _, overflowed := s.overflowed[series][name]; overflowed {
+		return
+	}
+	if _, exists := s.sources[name]; !exists && tagged {
+		if limit := s.cardinalityLimit(); limit > 0 && s.cardinality[series] >= limit {
+			overflowName, overflowSource := overflow()
+			if _, exists := s.sources[overflowName]; !exists {
+				s.sources[overflowName] = overflowSource
+				s.seriesOf[overflowName] = sourceSeries{series: series, overflow: true}
+			}
+			if s.overflowed[series] == nil {
+				s.overflowed[series] = map[string]string{}
+			}
+			s.overflowed[series][name] = overflowName
+			s.overflows[series]++
+			return
 		}
 	}
 
-	s.sources[name] = gauge{StatSource: StatSourceFunc(
-		func(scb func(key SeriesKey, field string, value float64)) {
-			scb(NewSeriesKey(name), "value", cb())
-		}),
+	existing, exists := s.sources[name]
+	if exists && reflect.TypeOf(existing) != reflect.TypeOf(source) {
+		panic(fmt.Sprintf("%s already used for another stats source: %#v",
+			name, existing))
+	}
+
+	s.sources[name] = source
+	if !exists {
+		s.seriesOf[name] = sourceSeries{series: series}
+		s.cardinality[series]++
 	}
 }
 
+// IntGauge retrieves or creates an IntGauge after the given name.
+func (s *Scope) IntGauge(name string, tags ...SeriesTag) *IntGauge {
+	source := s.newTaggedSource("", name, tags, func(tags []SeriesTag) StatSource {
+		return NewIntGauge(NewSeriesKey(name).WithTags(tags...))
+	})
+	m, ok := source.(*IntGauge)
+	if !ok {
+		panic(fmt.Sprintf("%s already used for another stats source: %#v",
+			name, source))
+	}
+	return m
+}
+
+// FloatGauge retrieves or creates a FloatGauge after the given name.
+func (s *Scope) FloatGauge(name string, tags ...SeriesTag) *FloatGauge {
+	source := s.newTaggedSource("", name, tags, func(tags []SeriesTag) StatSource {
+		return NewFloatGauge(NewSeriesKey(name).WithTags(tags...))
+	})
+	m, ok := source.(*FloatGauge)
+	if !ok {
+		panic(fmt.Sprintf("%s already used for another stats source: %#v",
+			name, source))
+	}
+	return m
+}
+
+type gauge struct {
+	key SeriesKey
+	cb  func() float64
+}
+
+func (g gauge) Stats(cb func(key SeriesKey, field string, val float64)) {
+	cb(g.key, "value", g.cb())
+}
+
+func (g gauge) DescribeStats(cb func(key SeriesKey, field string, info StatInfo)) {
+	cb(g.key, "value", describeField(StatKindGauge, "", "", "value returned by the gauge callback"))
+}
+
 // Chain registers a full StatSource as the given name in the Scope's
 // StatSource table.
 func (s *Scope) Chain(source StatSource) {
@@ -315,6 +574,67 @@ func (s *Scope) Chain(source StatSource) {
 	s.chains = append(s.chains, source)
 }
 
+// Remove removes the StatSource created with the given name and tags, such as
+// a Meter, an IntVal or a Func from FuncNamed, or the Gauge with the given
+// name. It returns false if there was no such source. Anyone still holding on
+// to the removed source can keep using it, but it is no longer reported, and
+// retrieving a source with the same name and tags creates a new one.
+func (s *Scope) Remove(name string, tags ...SeriesTag) bool {
+	s.mtx.Lock()
+	var removed []StatSource
+	for _, namespace := range []string{"", "func:"} {
+		key := sourceName(namespace, name, tags)
+		delete(s.overflowed[namespace+name], key)
+		if source, exists := s.sources[key]; exists {
+			s.deleteSource(key)
+			removed = append(removed, source)
+		}
+	}
+	s.mtx.Unlock()
+
+	for _, source := range removed {
+		releaseSource(source)
+	}
+	return len(removed) > 0
+}
+
+// RemoveSource removes the given StatSource from the Scope, whether it was
+// created through the Scope or registered with Chain. It returns false if the
+// source was not found. See Remove.
+func (s *Scope) RemoveSource(source StatSource) bool {
+	if source == nil || !reflect.TypeOf(source).Comparable() {
+		// gauges are not comparable, and can only be removed by name.
+		return false
+	}
+
+	s.mtx.Lock()
+	found := false
+	for name, existing := range s.sources {
+		if existing == source {
+			s.deleteSource(name)
+			found = true
+		}
+	}
+	chains := s.chains[:0]
+	for _, existing := range s.chains {
+		if existing == source {
+			found = true
+			continue
+		}
+		chains = append(chains, existing)
+	}
+	for i := len(chains); i < len(s.chains); i++ {
+		s.chains[i] = nil
+	}
+	s.chains = chains
+	s.mtx.Unlock()
+
+	if found {
+		releaseSource(source)
+	}
+	return found
+}
+
 func (s *Scope) allNamedSources() (sources []namedSource) {
 	s.mtx.Lock()
 	sources = make([]namedSource, 0, len(s.sources))
@@ -327,8 +647,11 @@ func (s *Scope) allNamedSources() (sources []namedSource) {
 
 // Stats implements the StatSource interface.
 func (s *Scope) Stats(cb func(key SeriesKey, field string, val float64)) {
+	s.expire(monotime.Now())
+
+	tagger := s.tagger()
 	cbWithScope := func(key SeriesKey, field string, val float64) {
-		cb(key.WithTag("scope", s.name), field, val)
+		cb(tagger.tag(key), field, val)
 	}
 
 	for _, namedSource := range s.allNamedSources() {
@@ -342,12 +665,37 @@ func (s *Scope) Stats(cb func(key SeriesKey, field string, val float64)) {
 	for _, source := range chains {
 		source.Stats(cbWithScope)
 	}
+
+	s.overflowStats(cbWithScope)
+}
+
+// DescribeStats implements the StatDescriber interface.
+func (s *Scope) DescribeStats(cb func(key SeriesKey, field string, info StatInfo)) {
+	tagger := s.tagger()
+	cbWithScope := func(key SeriesKey, field string, info StatInfo) {
+		cb(tagger.tag(key), field, info)
+	}
+
+	for _, namedSource := range s.allNamedSources() {
+		DescribeStatSource(namedSource.source, cbWithScope)
+	}
+
+	s.mtx.Lock()
+	chains := append([]StatSource(nil), s.chains...)
+	s.mtx.Unlock()
+
+	for _, source := range chains {
+		DescribeStatSource(source, cbWithScope)
+	}
+
+	describeOverflow(cbWithScope)
 }
 
 // Name returns the name of the Scope, often the Package name.
 func (s *Scope) Name() string { return s.name }
 
 var _ StatSource = (*Scope)(nil)
+var _ StatDescriber = (*Scope)(nil)
 
 type namedSource struct {
 	name   string
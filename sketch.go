@@ -0,0 +1,398 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monkit
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+const (
+	// DefaultSketchAccuracy is the relative accuracy used for Sketches when
+	// no other accuracy is configured.
+	DefaultSketchAccuracy = 0.01
+
+	// sketchMaxBins bounds the number of bins kept per sign. When exceeded,
+	// the bins closest to zero are collapsed together, trading accuracy on
+	// the smallest values for bounded memory.
+	sketchMaxBins = 2048
+
+	// sketchMinValue is the smallest magnitude a Sketch can tell apart from
+	// zero.
+	sketchMinValue = 1e-12
+
+	sketchVersion = 1
+)
+
+// Sketch is a mergeable quantile sketch (a DDSketch). Values returned by
+// Query are within the configured relative accuracy of the true quantile
+// value: for an accuracy of 0.01, the estimate of a 100ms quantile is between
+// 99ms and 101ms. Unlike the sample reservoir, Sketches of the same accuracy
+// can be merged exactly, such as across processes or time windows.
+//
+// Sketch is not threadsafe.
+type Sketch struct {
+	alpha    float64
+	gamma    float64
+	logGamma float64
+
+	count    uint64
+	zeros    uint64
+	min, max float64
+	pos, neg sketchBins
+}
+
+// sketchBins is a dense range of bin counts, starting at bin index offset.
+type sketchBins struct {
+	offset int
+	counts []uint64
+}
+
+// NewSketch creates a Sketch with the given relative accuracy, where
+// 0 < relativeAccuracy < 1.
+func NewSketch(relativeAccuracy float64) *Sketch {
+	if !(relativeAccuracy > 0 && relativeAccuracy < 1) {
+		panic(fmt.Sprintf("invalid sketch accuracy %v", relativeAccuracy))
+	}
+	gamma := (1 + relativeAccuracy) / (1 - relativeAccuracy)
+	return &Sketch{
+		alpha:    relativeAccuracy,
+		gamma:    gamma,
+		logGamma: math.Log(gamma),
+	}
+}
+
+// RelativeAccuracy returns the relative accuracy the Sketch was created with.
+func (s *Sketch) RelativeAccuracy() float64 { return s.alpha }
+
+// Count returns the number of inserted values.
+func (s *Sketch) Count() int64 { return int64(s.count) }
+
+// Min returns the lowest inserted value, or 0 if the Sketch is empty.
+func (s *Sketch) Min() float64 { return s.min }
+
+// Max returns the highest inserted value, or 0 if the Sketch is empty.
+func (s *Sketch) Max() float64 { return s.max }
+
+// Insert adds a value to the Sketch. NaN values are ignored.
+func (s *Sketch) Insert(val float64) {
+	s.insertN(val, 1)
+}
+
+func (s *Sketch) insertN(val float64, n uint64) {
+	if math.IsNaN(val) || n == 0 {
+		return
+	}
+	if s.count == 0 || val < s.min {
+		s.min = val
+	}
+	if s.count == 0 || val > s.max {
+		s.max = val
+	}
+	s.count += n
+	switch {
+	case val > sketchMinValue:
+		s.pos.add(s.index(val), n)
+	case val < -sketchMinValue:
+		s.neg.add(s.index(-val), n)
+	default:
+		s.zeros += n
+	}
+}
+
+func (s *Sketch) index(val float64) int {
+	if math.IsInf(val, 0) {
+		val = math.MaxFloat64
+	}
+	return int(math.Ceil(math.Log(val) / s.logGamma))
+}
+
+func (s *Sketch) value(index int) float64 {
+	return 2 * math.Exp(float64(index)*s.logGamma) / (1 + s.gamma)
+}
+
+// Query returns an estimate of the value at the given quantile, where
+// 0 <= quantile <= 1. An empty Sketch returns 0.
+func (s *Sketch) Query(quantile float64) float64 {
+	if s.count == 0 {
+		return 0
+	}
+	if quantile <= 0 {
+		return s.min
+	}
+	if quantile >= 1 {
+		return s.max
+	}
+
+	rank := uint64(quantile * float64(s.count-1))
+	var seen uint64
+
+	// negative values are visited from the largest magnitude down.
+	for i := len(s.neg.counts) - 1; i >= 0; i-- {
+		seen += s.neg.counts[i]
+		if seen > rank {
+			return s.clamp(-s.value(s.neg.offset + i))
+		}
+	}
+	seen += s.zeros
+	if seen > rank {
+		return 0
+	}
+	for i, count := range s.pos.counts {
+		seen += count
+		if seen > rank {
+			return s.clamp(s.value(s.pos.offset + i))
+		}
+	}
+	return s.max
+}
+
+// clamp keeps estimates within the observed range, as bin centers can lie
+// slightly outside of it.
+func (s *Sketch) clamp(val float64) float64 {
+	if val < s.min {
+		return s.min
+	}
+	if val > s.max {
+		return s.max
+	}
+	return val
+}
+
+// Merge adds all of the values in other to s. Both Sketches must have the
+// same relative accuracy.
+func (s *Sketch) Merge(other *Sketch) error {
+	if s.alpha != other.alpha {
+		return fmt.Errorf("monkit: cannot merge sketches of accuracy %v and %v",
+			s.alpha, other.alpha)
+	}
+	if other.count == 0 {
+		return nil
+	}
+	if s.count == 0 || other.min < s.min {
+		s.min = other.min
+	}
+	if s.count == 0 || other.max > s.max {
+		s.max = other.max
+	}
+	s.count += other.count
+	s.zeros += other.zeros
+	for i, count := range other.pos.counts {
+		s.pos.add(other.pos.offset+i, count)
+	}
+	for i, count := range other.neg.counts {
+		s.neg.add(other.neg.offset+i, count)
+	}
+	return nil
+}
+
+// Copy returns a full copy of the Sketch.
+func (s *Sketch) Copy() *Sketch {
+	if s == nil {
+		return nil
+	}
+	cp := *s
+	cp.pos.counts = append([]uint64(nil), s.pos.counts...)
+	cp.neg.counts = append([]uint64(nil), s.neg.counts...)
+	return &cp
+}
+
+// Reset removes all values from the Sketch.
+func (s *Sketch) Reset() {
+	s.count, s.zeros, s.min, s.max = 0, 0, 0, 0
+	s.pos = sketchBins{}
+	s.neg = sketchBins{}
+}
+
+// Buckets calls cb for every non-empty bin of the Sketch, in increasing value
+// order. lower and upper are the bounds of the values counted in the bin. All
+// values within the Sketch's accuracy of zero share a single bin.
+func (s *Sketch) Buckets(cb func(lower, upper float64, count int64)) {
+	for i := len(s.neg.counts) - 1; i >= 0; i-- {
+		if count := s.neg.counts[i]; count > 0 {
+			index := s.neg.offset + i
+			cb(-math.Pow(s.gamma, float64(index)),
+				-math.Pow(s.gamma, float64(index-1)), int64(count))
+		}
+	}
+	if s.zeros > 0 {
+		cb(-sketchMinValue, sketchMinValue, int64(s.zeros))
+	}
+	for i, count := range s.pos.counts {
+		if count > 0 {
+			index := s.pos.offset + i
+			cb(math.Pow(s.gamma, float64(index-1)),
+				math.Pow(s.gamma, float64(index)), int64(count))
+		}
+	}
+}
+
+func (b *sketchBins) add(index int, n uint64) {
+	if len(b.counts) == 0 {
+		b.offset = index
+		b.counts = append(b.counts, n)
+		return
+	}
+	if index < b.offset {
+		if b.offset+len(b.counts)-index > sketchMaxBins {
+			// collapse the lowest bins rather than growing further.
+			b.counts[0] += n
+			return
+		}
+		grown := make([]uint64, b.offset-index+len(b.counts))
+		copy(grown[b.offset-index:], b.counts)
+		b.counts = grown
+		b.offset = index
+	}
+	if pos := index - b.offset; pos >= len(b.counts) {
+		b.counts = append(b.counts, make([]uint64, pos-len(b.counts)+1)...)
+		if extra := len(b.counts) - sketchMaxBins; extra > 0 {
+			b.collapse(extra)
+		}
+	}
+	b.counts[index-b.offset] += n
+}
+
+// collapse merges the lowest n+1 bins into one.
+func (b *sketchBins) collapse(n int) {
+	var sum uint64
+	for _, count := range b.counts[:n+1] {
+		sum += count
+	}
+	b.counts = append(b.counts[:0], b.counts[n:]...)
+	b.counts[0] = sum
+	b.offset += n
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (s *Sketch) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, 0, 64+8*(len(s.pos.counts)+len(s.neg.counts)))
+	buf = append(buf, sketchVersion)
+	buf = binary.BigEndian.AppendUint64(buf, math.Float64bits(s.alpha))
+	buf = binary.BigEndian.AppendUint64(buf, math.Float64bits(s.min))
+	buf = binary.BigEndian.AppendUint64(buf, math.Float64bits(s.max))
+	buf = binary.AppendUvarint(buf, s.zeros)
+	for _, bins := range []*sketchBins{&s.pos, &s.neg} {
+		buf = binary.AppendVarint(buf, int64(bins.offset))
+		buf = binary.AppendUvarint(buf, uint64(len(bins.counts)))
+		for _, count := range bins.counts {
+			buf = binary.AppendUvarint(buf, count)
+		}
+	}
+	return buf, nil
+}
+
+var errBadSketch = errors.New("monkit: invalid sketch encoding")
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (s *Sketch) UnmarshalBinary(data []byte) error {
+	if len(data) < 25 || data[0] != sketchVersion {
+		return errBadSketch
+	}
+	alpha := math.Float64frombits(binary.BigEndian.Uint64(data[1:]))
+	if !(alpha > 0 && alpha < 1) {
+		return errBadSketch
+	}
+	decoded := NewSketch(alpha)
+	decoded.min = math.Float64frombits(binary.BigEndian.Uint64(data[9:]))
+	decoded.max = math.Float64frombits(binary.BigEndian.Uint64(data[17:]))
+	data = data[25:]
+
+	readUvarint := func() (uint64, bool) {
+		v, n := binary.Uvarint(data)
+		if n <= 0 {
+			return 0, false
+		}
+		data = data[n:]
+		return v, true
+	}
+
+	var ok bool
+	if decoded.zeros, ok = readUvarint(); !ok {
+		return errBadSketch
+	}
+	decoded.count = decoded.zeros
+	for _, bins := range []*sketchBins{&decoded.pos, &decoded.neg} {
+		offset, n := binary.Varint(data)
+		if n <= 0 {
+			return errBadSketch
+		}
+		data = data[n:]
+		bins.offset = int(offset)
+		size, ok := readUvarint()
+		if !ok || size > sketchMaxBins {
+			return errBadSketch
+		}
+		bins.counts = make([]uint64, size)
+		for i := range bins.counts {
+			if bins.counts[i], ok = readUvarint(); !ok {
+				return errBadSketch
+			}
+			decoded.count += bins.counts[i]
+		}
+	}
+	if len(data) != 0 {
+		return errBadSketch
+	}
+	*s = *decoded
+	return nil
+}
+
+// SketchSource is implemented by StatSources whose distributions keep
+// Sketches, so that the full distributions can be exported rather than just
+// the quantiles reported by Stats.
+type SketchSource interface {
+	// Sketches calls cb with a copy of the Sketch of every distribution that
+	// has one. The keys match the keys of the distributions' fields in
+	// Stats, and the values are in the same units, so seconds for
+	// durations.
+	Sketches(cb func(key SeriesKey, sketch *Sketch))
+}
+
+// Sketches calls cb with the Sketches of all sources in the Scope that
+// implement SketchSource. See SketchSource.
+func (s *Scope) Sketches(cb func(key SeriesKey, sketch *Sketch)) {
+	tagger := s.tagger()
+	cbWithScope := func(key SeriesKey, sketch *Sketch) {
+		cb(tagger.tag(key), sketch)
+	}
+
+	for _, namedSource := range s.allNamedSources() {
+		if source, ok := namedSource.source.(SketchSource); ok {
+			source.Sketches(cbWithScope)
+		}
+	}
+
+	s.mtx.Lock()
+	chains := append([]StatSource(nil), s.chains...)
+	s.mtx.Unlock()
+
+	for _, chain := range chains {
+		if source, ok := chain.(SketchSource); ok {
+			source.Sketches(cbWithScope)
+		}
+	}
+}
+
+// Sketches calls cb with the Sketches of all Scopes in the Registry. The
+// Registry's CallbackTransformers are not applied to the keys.
+func (r *Registry) Sketches(cb func(key SeriesKey, sketch *Sketch)) {
+	r.Scopes(func(s *Scope) { s.Sketches(cb) })
+}
+
+var _ SketchSource = (*Scope)(nil)
+var _ SketchSource = (*Registry)(nil)
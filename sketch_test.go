@@ -0,0 +1,129 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monkit
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+	"time"
+)
+
+func checkSketchQuantiles(t *testing.T, s *Sketch, sorted []float64) {
+	t.Helper()
+	for _, q := range []float64{0, .1, .5, .9, .99, .999, 1} {
+		expected := sorted[int(q*float64(len(sorted)-1))]
+		got := s.Query(q)
+		if math.Abs(got-expected) > s.RelativeAccuracy()*math.Abs(expected) {
+			t.Errorf("q%v: got %v, expected %v", q, got, expected)
+		}
+	}
+}
+
+func TestSketch(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	a, b := NewSketch(.01), NewSketch(.01)
+	var values []float64
+	for i := 0; i < 100000; i++ {
+		v := rng.ExpFloat64() * 1000
+		if i%100 == 0 {
+			v = -v
+		}
+		if i%2 == 0 {
+			a.Insert(v)
+		} else {
+			b.Insert(v)
+		}
+		values = append(values, v)
+	}
+	sort.Float64s(values)
+
+	if err := a.Merge(b); err != nil {
+		t.Fatal(err)
+	}
+	if a.Count() != int64(len(values)) {
+		t.Fatalf("got count %d, expected %d", a.Count(), len(values))
+	}
+	checkSketchQuantiles(t, a, values)
+
+	data, err := a.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Sketch
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Count() != a.Count() {
+		t.Fatalf("got count %d after decoding, expected %d", decoded.Count(), a.Count())
+	}
+	checkSketchQuantiles(t, &decoded, values)
+
+	if err := decoded.UnmarshalBinary(data[:len(data)-1]); err == nil {
+		t.Fatal("expected an error decoding a truncated sketch")
+	}
+	if err := a.Merge(NewSketch(.02)); err == nil {
+		t.Fatal("expected an error merging sketches of different accuracy")
+	}
+}
+
+func TestDurationValSketch(t *testing.T) {
+	r := NewRegistry()
+	mon := r.ScopeNamed("sketch")
+	v := mon.DurationValSketch("latency")
+	for i := 1; i <= 1000; i++ {
+		v.Observe(time.Duration(i) * time.Millisecond)
+	}
+	if v != mon.DurationVal("latency") {
+		t.Fatal("expected the same DurationVal")
+	}
+	if got := v.Quantile(.99); got < 980*time.Millisecond || got > 1000*time.Millisecond {
+		t.Fatalf("unexpected p99 %v", got)
+	}
+	if sketch := v.Sketch(); sketch == nil || sketch.Count() != 1000 {
+		t.Fatalf("unexpected sketch %+v", sketch)
+	}
+
+	r.UseSketches(.001)
+	f := mon.FuncNamed("task")
+	func() {
+		defer f.Observe()(nil)
+	}()
+	if sketch := f.SuccessTimes().Sketch(); sketch == nil || sketch.RelativeAccuracy() != .001 {
+		t.Fatalf("expected registry sketch accuracy, got %+v", sketch)
+	}
+}
+
+func TestSketchSource(t *testing.T) {
+	r := NewRegistry()
+	mon := r.ScopeNamed("test")
+	mon.DurationValSketch("latency").Observe(time.Second)
+	mon.DurationVal("unsketched").Observe(time.Second)
+	mon.IntValSketch("size").Observe(10)
+
+	got := map[string]int64{}
+	r.Sketches(func(key SeriesKey, sketch *Sketch) {
+		got[key.String()] = sketch.Count()
+	})
+	if len(got) != 2 || got["latency,scope=test"] != 1 || got["size,scope=test"] != 1 {
+		t.Fatalf("got %v", got)
+	}
+	r.Sketches(func(key SeriesKey, sketch *Sketch) {
+		if key.Measurement == "latency" && math.Abs(sketch.Max()-1) > 1e-9 {
+			t.Fatalf("got max %v, expected seconds", sketch.Max())
+		}
+	})
+}
@@ -0,0 +1,293 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monkit
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/spacemonkeygo/monkit/v3/monotime"
+)
+
+// DefaultSLOPeriod is the period of the error budget of SLOs that don't set
+// one.
+const DefaultSLOPeriod = 30 * 24 * time.Hour
+
+// SLO is a service level objective for the calls of a Func: the fraction of
+// calls that have to be good.
+type SLO struct {
+	// Target is the fraction of calls that have to be good, such as 0.999.
+	Target float64
+	// Latency makes only calls that succeed within Latency good. If it is 0,
+	// all successful calls are good, so the SLO is an availability target.
+	Latency time.Duration
+	// Period is the period the error budget is computed over. If it is 0,
+	// DefaultSLOPeriod is used.
+	Period time.Duration
+}
+
+func (slo SLO) period() time.Duration {
+	if slo.Period <= 0 {
+		return DefaultSLOPeriod
+	}
+	return slo.Period
+}
+
+// good returns whether a call meets the SLO.
+func (slo SLO) good(err error, panicked bool, duration time.Duration) bool {
+	return err == nil && !panicked && (slo.Latency <= 0 || duration <= slo.Latency)
+}
+
+// SLOWindow is a window SLO burn rates are computed over.
+type SLOWindow struct {
+	// Name is the suffix of the window's field names, like "5m".
+	Name   string
+	Window time.Duration
+}
+
+// sloWindows are the windows of SLOWindows, from the shortest to the longest.
+var sloWindows = [...]SLOWindow{
+	{"5m", 5 * time.Minute},
+	{"1h", time.Hour},
+	{"6h", 6 * time.Hour},
+	{"3d", 72 * time.Hour},
+}
+
+// sloLongestWindow is the longest of sloWindows, which the per-minute counts
+// of SLO trackers cover.
+var sloLongestWindow = sloWindows[len(sloWindows)-1].Window
+
+// SLOWindows returns the windows SLO burn rates are computed over, in the
+// order of SLOStatus.BurnRates.
+func SLOWindows() []SLOWindow {
+	return append([]SLOWindow(nil), sloWindows[:]...)
+}
+
+// SLOStatus is how a Func is doing against its SLO.
+type SLOStatus struct {
+	SLO SLO
+	// BurnRates are the rates the error budget is spent at over every
+	// window of SLOWindows, in the same order. A burn rate of 1 spends
+	// exactly the budget over the SLO's period.
+	BurnRates []float64
+	// Good and Total are the number of good calls and of all calls over the
+	// SLO's period.
+	Good, Total int64
+	// ErrorBudgetRemaining is the fraction of the error budget of the period
+	// that is left. It is negative once the budget is exceeded.
+	ErrorBudgetRemaining float64
+}
+
+// UseSLO makes the FuncStats track how its calls do against slo. It keeps
+// counts of good and all calls per minute over the longest of SLOWindows and
+// per hour over the SLO's period, and emits the burn rates and the remaining
+// error budget as fields of the "function_slo" measurement, with the same
+// tags as the FuncStats. See SLOStatus. Calling UseSLO again with the same
+// SLO has no effect, while a different SLO starts over.
+func (f *FuncStats) UseSLO(slo SLO) {
+	if t := f.slo.Load(); t != nil && t.slo == slo {
+		return
+	}
+	f.slo.Store(newSLOTracker(slo, monotime.Now))
+}
+
+// UseSLO makes the Task's Func track how its calls do against slo. See
+// FuncStats.UseSLO. It returns the Task, so it can be used like:
+//
+//	var mon = monkit.Package()
+//	var slo = monkit.SLO{Target: .999, Latency: 500 * time.Millisecond}
+//
+//	func MyFunc(ctx context.Context) (err error) {
+//	  defer mon.Task().UseSLO(slo)(&ctx)(&err)
+//	  ...
+//	}
+func (f Task) UseSLO(slo SLO) Task {
+	f.Func().UseSLO(slo)
+	return f
+}
+
+// SLOStatus returns how the FuncStats is doing against its SLO, and false if
+// it has none.
+func (f *FuncStats) SLOStatus() (SLOStatus, bool) {
+	t := f.slo.Load()
+	if t == nil {
+		return SLOStatus{}, false
+	}
+	return t.status(), true
+}
+
+// sloTracker keeps the counts an SLOStatus is computed from. Calls are
+// counted with atomics, so recording them only takes the mutex when a bucket
+// has to be reused for a new minute or hour.
+type sloTracker struct {
+	slo SLO
+	now func() time.Time
+
+	mtx     sync.Mutex // protects reusing buckets
+	minutes sloRing
+	hours   sloRing
+}
+
+func newSLOTracker(slo SLO, now func() time.Time) *sloTracker {
+	t := &sloTracker{slo: slo, now: now}
+	t.minutes.init(time.Minute, sloLongestWindow)
+	t.hours.init(time.Hour, slo.period())
+	return t
+}
+
+func (t *sloTracker) record(err error, panicked bool, duration time.Duration) {
+	good := t.slo.good(err, panicked, duration)
+	now := t.now()
+	t.minutes.add(&t.mtx, now, good)
+	t.hours.add(&t.mtx, now, good)
+}
+
+func (t *sloTracker) reset() {
+	t.mtx.Lock()
+	t.minutes.clear()
+	t.hours.clear()
+	t.mtx.Unlock()
+}
+
+func (t *sloTracker) status() SLOStatus {
+	now := t.now()
+	budget := 1 - t.slo.Target
+
+	status := SLOStatus{SLO: t.slo, BurnRates: make([]float64, len(sloWindows))}
+	for i, window := range sloWindows {
+		good, total := t.minutes.sum(now, window.Window)
+		if total > 0 && budget > 0 {
+			status.BurnRates[i] = float64(total-good) / float64(total) / budget
+		}
+	}
+	status.Good, status.Total = t.hours.sum(now, t.slo.period())
+	status.ErrorBudgetRemaining = 1
+	if status.Total > 0 && budget > 0 {
+		allowed := budget * float64(status.Total)
+		status.ErrorBudgetRemaining = 1 - float64(status.Total-status.Good)/allowed
+	}
+	return status
+}
+
+func (t *sloTracker) stats(key SeriesKey, cb func(key SeriesKey, field string, val float64)) {
+	status := t.status()
+	cb(key, "target", t.slo.Target)
+	for i, window := range sloWindows {
+		cb(key, "burn_rate_"+window.Name, status.BurnRates[i])
+	}
+	cb(key, "period_good", float64(status.Good))
+	cb(key, "period_total", float64(status.Total))
+	cb(key, "error_budget_remaining", status.ErrorBudgetRemaining)
+}
+
+func describeSLO(key SeriesKey, cb func(key SeriesKey, field string, info StatInfo)) {
+	cb(key, "target", describeField(StatKindGauge, "", "",
+		"fraction of calls that have to be good"))
+	for _, window := range sloWindows {
+		cb(key, "burn_rate_"+window.Name, describeField(StatKindGauge, "", "",
+			"rate the error budget is spent at over the last "+window.Name))
+	}
+	cb(key, "period_good", describeField(StatKindGauge, "", "",
+		"number of good calls within the SLO period"))
+	cb(key, "period_total", describeField(StatKindGauge, "", "",
+		"number of calls within the SLO period"))
+	cb(key, "error_budget_remaining", describeField(StatKindGauge, "", "",
+		"fraction of the error budget of the SLO period that is left"))
+}
+
+// sloRing counts good and all calls in buckets of a fixed width, covering at
+// least a given span. Buckets are numbered by the widths since the Unix epoch,
+// and every bucket knows the number it counts for, so stale buckets are
+// skipped and reused without a sweep.
+type sloRing struct {
+	width   time.Duration
+	buckets []sloBucket
+}
+
+// sloBucket counts calls within a bucket width. All fields are accessed
+// atomically.
+type sloBucket struct {
+	number      int64 // number of the counted bucket since the Unix epoch
+	good, total int64
+}
+
+func (r *sloRing) init(width, span time.Duration) {
+	r.width = width
+	// one more bucket than needed, as the current one is only partial.
+	r.buckets = make([]sloBucket, int((span+width-1)/width)+1)
+	r.clear()
+}
+
+// clear empties all buckets. The mutex of the tracker must be held.
+func (r *sloRing) clear() {
+	for i := range r.buckets {
+		bucket := &r.buckets[i]
+		atomic.StoreInt64(&bucket.number, -1)
+		atomic.StoreInt64(&bucket.good, 0)
+		atomic.StoreInt64(&bucket.total, 0)
+	}
+}
+
+// add counts a call at now. If its bucket still counts an older number, it
+// is emptied and reused while holding mtx. Calls older than the bucket's
+// number are dropped.
+func (r *sloRing) add(mtx *sync.Mutex, now time.Time, good bool) {
+	number := now.UnixNano() / int64(r.width)
+	bucket := &r.buckets[number%int64(len(r.buckets))]
+	if current := atomic.LoadInt64(&bucket.number); current != number {
+		if current > number {
+			return // too old
+		}
+		mtx.Lock()
+		if current := atomic.LoadInt64(&bucket.number); current < number {
+			// the counts are emptied before the number is changed, so that
+			// calls counted for the new number are kept.
+			atomic.StoreInt64(&bucket.good, 0)
+			atomic.StoreInt64(&bucket.total, 0)
+			atomic.StoreInt64(&bucket.number, number)
+		} else if current > number {
+			mtx.Unlock()
+			return // too old
+		}
+		mtx.Unlock()
+	}
+	atomic.AddInt64(&bucket.total, 1)
+	if good {
+		atomic.AddInt64(&bucket.good, 1)
+	}
+}
+
+// sum returns the counts of the buckets covering window up to now.
+func (r *sloRing) sum(now time.Time, window time.Duration) (good, total int64) {
+	current := now.UnixNano() / int64(r.width)
+	n := int64(len(r.buckets))
+	count := int64((window + r.width - 1) / r.width)
+	if count >= n {
+		count = n - 1
+	}
+	for i := current - count; i <= current; i++ {
+		if i < 0 {
+			continue
+		}
+		bucket := &r.buckets[i%n]
+		if atomic.LoadInt64(&bucket.number) != i {
+			continue
+		}
+		good += atomic.LoadInt64(&bucket.good)
+		total += atomic.LoadInt64(&bucket.total)
+	}
+	return good, total
+}
@@ -0,0 +1,128 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monkit
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSLOTracker(t *testing.T) {
+	now := time.Unix(1_000_000_020, 0)
+	tracker := newSLOTracker(SLO{Target: 0.9, Latency: time.Second, Period: 24 * time.Hour},
+		func() time.Time { return now })
+
+	errBad := errors.New("bad")
+	// 4 hours ago: 10 bad calls out of 20.
+	now = now.Add(-4 * time.Hour)
+	for i := 0; i < 10; i++ {
+		tracker.record(nil, false, time.Millisecond)
+		tracker.record(errBad, false, time.Millisecond)
+	}
+	// now: 1 slow, 1 panicked and 8 good calls.
+	now = now.Add(4 * time.Hour)
+	tracker.record(nil, false, 2*time.Second)
+	tracker.record(nil, true, time.Millisecond)
+	for i := 0; i < 8; i++ {
+		tracker.record(nil, false, time.Second)
+	}
+
+	status := tracker.status()
+	expected := []float64{2, 2, 12.0 / 30 / 0.1, 12.0 / 30 / 0.1}
+	for i, rate := range status.BurnRates {
+		if math.Abs(rate-expected[i]) > 1e-9 {
+			t.Errorf("burn rate %s: got %v, expected %v", sloWindows[i].Name, rate, expected[i])
+		}
+	}
+	if status.Good != 18 || status.Total != 30 {
+		t.Errorf("got %d good of %d calls", status.Good, status.Total)
+	}
+	if math.Abs(status.ErrorBudgetRemaining-(1-12/3.0)) > 1e-9 {
+		t.Errorf("got remaining budget %v", status.ErrorBudgetRemaining)
+	}
+
+	// the old calls leave the windows and the period.
+	now = now.Add(25 * time.Hour)
+	status = tracker.status()
+	if status.BurnRates[1] != 0 || math.Abs(status.BurnRates[3]-12.0/30/0.1) > 1e-9 || status.Total != 0 ||
+		status.ErrorBudgetRemaining != 1 {
+		t.Errorf("unexpected status %+v", status)
+	}
+}
+
+func TestSLOTrackerConcurrent(t *testing.T) {
+	now := time.Unix(1_000_000_020, 0)
+	tracker := newSLOTracker(SLO{Target: 0.9}, func() time.Time { return now })
+
+	errBad := errors.New("bad")
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				tracker.record(nil, false, time.Millisecond)
+				tracker.record(errBad, false, time.Millisecond)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if status := tracker.status(); status.Good != 8000 || status.Total != 16000 {
+		t.Fatalf("got %d good of %d calls", status.Good, status.Total)
+	}
+}
+
+func TestFuncSLO(t *testing.T) {
+	r := NewRegistry()
+	mon := r.ScopeNamed("test")
+	slo := SLO{Target: 0.5}
+
+	work := func(fail bool) (err error) {
+		ctx := context.Background()
+		defer mon.TaskNamed("work").UseSLO(slo)(&ctx)(&err)
+		if fail {
+			return errors.New("fail")
+		}
+		return nil
+	}
+	for i := 0; i < 3; i++ {
+		_ = work(false)
+	}
+	_ = work(true)
+
+	status, ok := mon.FuncNamed("work").SLOStatus()
+	if !ok || status.Good != 3 || status.Total != 4 || status.ErrorBudgetRemaining != 0.5 {
+		t.Fatalf("unexpected status %+v", status)
+	}
+
+	key := NewSeriesKey("function_slo").WithTags(NewSeriesTag("name", "work"), NewSeriesTag("scope", "test"))
+	if got := lookupValue(t, r.Snapshot(), key, "burn_rate_5m"); got != 0.5 {
+		t.Fatalf("got burn rate %v", got)
+	}
+	catalog := NewStatCatalog(r)
+	if info, ok := catalog.Lookup(NewSeriesKey("function_slo").WithTag("name", "work"),
+		"error_budget_remaining"); !ok || info.Kind != StatKindGauge {
+		t.Fatalf("got %+v", info)
+	}
+
+	if _, ok := mon.FuncNamed("other").SLOStatus(); ok {
+		t.Fatal("unexpected SLO")
+	}
+}
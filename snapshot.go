@@ -0,0 +1,369 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monkit
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Snapshot is a copy of all statistics of a Registry. Snapshots are created
+// with Registry.Snapshot or View.Snapshot and must not be modified, so that
+// all readers of a Snapshot see the same values. A Snapshot is a StatSource
+// and StatDescriber itself, so it can be re-emitted to anything that consumes
+// those.
+//
+// The fields of every source, such as the counts, sums and quantiles of a
+// Func, are copied at once and are consistent with each other, but the
+// sources are copied one after the other, so different sources can be apart
+// by the time it takes to collect the Snapshot. Values that are updated
+// together in different sources can thus disagree slightly.
+type Snapshot struct {
+	// Time is when collecting the Snapshot started.
+	Time time.Time
+	// Since is the start of the interval the values of a delta Snapshot
+	// cover, see Diff. It is zero for Snapshots of cumulative values.
+	Since time.Time
+	// Series contains the series sorted by their keys' string form.
+	Series []SeriesSnapshot
+}
+
+// SeriesSnapshot contains the fields of one series in a Snapshot.
+type SeriesSnapshot struct {
+	Key SeriesKey
+	// Fields are sorted by name.
+	Fields []FieldSnapshot
+
+	name string // Key.String(), if built by this package
+}
+
+func newSeriesSnapshot(key SeriesKey, fields []FieldSnapshot) SeriesSnapshot {
+	return SeriesSnapshot{Key: key, Fields: fields, name: key.String()}
+}
+
+// keyString returns the string form of the series' key, which Snapshots are
+// sorted by.
+func (s *SeriesSnapshot) keyString() string {
+	if s.name == "" {
+		return s.Key.String()
+	}
+	return s.name
+}
+
+// FieldSnapshot is the value of one field of a series in a Snapshot, along
+// with its description.
+type FieldSnapshot struct {
+	Field string
+	Value float64
+	Info  StatInfo
+}
+
+// Snapshot collects the current values of all statistics in the Registry,
+// with the Registry's CallbackTransformers applied, along with their
+// descriptions. Every source copies its own state when it is reached, so the
+// Snapshot is only consistent per source, as described on Snapshot. Once
+// taken, it can be shared by any number of readers.
+func (r *Registry) Snapshot() *Snapshot {
+	b := newSnapshotBuilder(r)
+	r.Stats(b.add)
+	return b.done()
+}
+
+// snapshotBuilder collects values into a Snapshot.
+type snapshotBuilder struct {
+	now     time.Time
+	catalog *StatCatalog
+	series  map[string]*SeriesSnapshot
+}
+
+func newSnapshotBuilder(r *Registry) *snapshotBuilder {
+	return &snapshotBuilder{
+		now:     time.Now(),
+		catalog: NewStatCatalog(r),
+		series:  map[string]*SeriesSnapshot{},
+	}
+}
+
+func (b *snapshotBuilder) add(key SeriesKey, field string, val float64) {
+	info, _ := b.catalog.Lookup(key, field)
+	b.addField(key, FieldSnapshot{Field: field, Value: val, Info: info})
+}
+
+func (b *snapshotBuilder) addField(key SeriesKey, field FieldSnapshot) {
+	name := key.String()
+	series, ok := b.series[name]
+	if !ok {
+		series = &SeriesSnapshot{Key: key, name: name}
+		b.series[name] = series
+	}
+	series.Fields = append(series.Fields, field)
+}
+
+func (b *snapshotBuilder) done() *Snapshot {
+	names := make([]string, 0, len(b.series))
+	for name := range b.series {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	s := &Snapshot{Time: b.now, Series: make([]SeriesSnapshot, 0, len(names))}
+	for _, name := range names {
+		series := b.series[name]
+		// if a field was emitted more than once, the last value wins.
+		sort.SliceStable(series.Fields, func(i, j int) bool {
+			return series.Fields[i].Field < series.Fields[j].Field
+		})
+		fields := series.Fields[:0]
+		for i, field := range series.Fields {
+			if i+1 < len(series.Fields) && series.Fields[i+1].Field == field.Field {
+				continue
+			}
+			fields = append(fields, field)
+		}
+		series.Fields = fields
+		s.Series = append(s.Series, *series)
+	}
+	return s
+}
+
+// Stats implements the StatSource interface.
+func (s *Snapshot) Stats(cb func(key SeriesKey, field string, val float64)) {
+	for _, series := range s.Series {
+		for _, field := range series.Fields {
+			cb(series.Key, field.Field, field.Value)
+		}
+	}
+}
+
+// DescribeStats implements the StatDescriber interface.
+func (s *Snapshot) DescribeStats(cb func(key SeriesKey, field string, info StatInfo)) {
+	for _, series := range s.Series {
+		for _, field := range series.Fields {
+			cb(series.Key, field.Field, field.Info)
+		}
+	}
+}
+
+var _ StatSource = (*Snapshot)(nil)
+var _ StatDescriber = (*Snapshot)(nil)
+
+// Lookup returns the given field of the given series.
+func (s *Snapshot) Lookup(key SeriesKey, field string) (FieldSnapshot, bool) {
+	series := s.lookupSeries(key.String())
+	if series == nil {
+		return FieldSnapshot{}, false
+	}
+	return series.lookupField(field)
+}
+
+func (s *SeriesSnapshot) lookupField(field string) (FieldSnapshot, bool) {
+	i := sort.Search(len(s.Fields), func(i int) bool {
+		return s.Fields[i].Field >= field
+	})
+	if i < len(s.Fields) && s.Fields[i].Field == field {
+		return s.Fields[i], true
+	}
+	return FieldSnapshot{}, false
+}
+
+func (s *Snapshot) lookupSeries(name string) *SeriesSnapshot {
+	i := sort.Search(len(s.Series), func(i int) bool {
+		return s.Series[i].keyString() >= name
+	})
+	if i < len(s.Series) && s.Series[i].keyString() == name {
+		return &s.Series[i]
+	}
+	return nil
+}
+
+// Diff returns a Snapshot of what changed since prev, which should be an
+// earlier Snapshot of the same Registry. Counter and histogram fields become
+// the difference to their value in prev, or stay as they are if they are not
+// in prev or were reset since. All other fields keep their current values.
+func (s *Snapshot) Diff(prev *Snapshot) *Snapshot {
+	return s.diff(prev, nil)
+}
+
+// diff is like Diff, but replaces the series in windows entirely.
+func (s *Snapshot) diff(prev *Snapshot, windows map[string]*SeriesSnapshot) *Snapshot {
+	rv := &Snapshot{Time: s.Time, Since: prev.Time,
+		Series: make([]SeriesSnapshot, 0, len(s.Series))}
+	for _, series := range s.Series {
+		name := series.keyString()
+		if window, ok := windows[name]; ok {
+			rv.Series = append(rv.Series, *window)
+			delete(windows, name)
+			continue
+		}
+
+		prevSeries := prev.lookupSeries(name)
+		fields := make([]FieldSnapshot, len(series.Fields))
+		for i, field := range series.Fields {
+			fields[i] = field
+			if prevSeries == nil {
+				continue
+			}
+			switch field.Info.Kind {
+			case StatKindCounter, StatKindHistogram:
+				prevField, ok := prevSeries.lookupField(field.Field)
+				if ok && field.Value >= prevField.Value {
+					fields[i].Value -= prevField.Value
+				}
+			}
+		}
+		rv.Series = append(rv.Series, SeriesSnapshot{Key: series.Key, Fields: fields, name: name})
+	}
+
+	// windows of series that were not in s, for example because they expired.
+	if len(windows) > 0 {
+		for _, window := range windows {
+			rv.Series = append(rv.Series, *window)
+		}
+		sort.Slice(rv.Series, func(i, j int) bool {
+			return rv.Series[i].keyString() < rv.Series[j].keyString()
+		})
+	}
+	return rv
+}
+
+type snapshotJSON struct {
+	Time   time.Time            `json:"time"`
+	Since  *time.Time           `json:"since,omitempty"`
+	Series []seriesSnapshotJSON `json:"series"`
+}
+
+type seriesSnapshotJSON struct {
+	Measurement string              `json:"measurement"`
+	Tags        map[string]string   `json:"tags,omitempty"`
+	Fields      []fieldSnapshotJSON `json:"fields"`
+}
+
+type fieldSnapshotJSON struct {
+	Field    string        `json:"field"`
+	Value    snapshotValue `json:"value"`
+	Kind     string        `json:"kind"`
+	Unit     string        `json:"unit,omitempty"`
+	Help     string        `json:"help,omitempty"`
+	Quantile float64       `json:"quantile,omitempty"`
+}
+
+// snapshotValue is a float64 that is encoded as a JSON string if it is not
+// finite.
+type snapshotValue float64
+
+func (v snapshotValue) MarshalJSON() ([]byte, error) {
+	switch val := float64(v); {
+	case math.IsNaN(val):
+		return []byte(`"NaN"`), nil
+	case math.IsInf(val, 1):
+		return []byte(`"+Inf"`), nil
+	case math.IsInf(val, -1):
+		return []byte(`"-Inf"`), nil
+	}
+	return strconv.AppendFloat(nil, float64(v), 'g', -1, 64), nil
+}
+
+func (v *snapshotValue) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err == nil {
+		val, err := strconv.ParseFloat(str, 64)
+		*v = snapshotValue(val)
+		return err
+	}
+	var val float64
+	err := json.Unmarshal(data, &val)
+	*v = snapshotValue(val)
+	return err
+}
+
+func parseStatKind(name string) (StatKind, error) {
+	for kind := StatKindUnknown; kind <= StatKindHistogram; kind++ {
+		if kind.String() == name {
+			return kind, nil
+		}
+	}
+	return StatKindUnknown, fmt.Errorf("unknown stat kind %q", name)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s *Snapshot) MarshalJSON() ([]byte, error) {
+	out := snapshotJSON{Time: s.Time, Series: make([]seriesSnapshotJSON, 0, len(s.Series))}
+	if !s.Since.IsZero() {
+		out.Since = &s.Since
+	}
+	for _, series := range s.Series {
+		fields := make([]fieldSnapshotJSON, 0, len(series.Fields))
+		for _, field := range series.Fields {
+			fields = append(fields, fieldSnapshotJSON{
+				Field:    field.Field,
+				Value:    snapshotValue(field.Value),
+				Kind:     field.Info.Kind.String(),
+				Unit:     field.Info.Unit,
+				Help:     field.Info.Help,
+				Quantile: field.Info.Quantile,
+			})
+		}
+		out.Series = append(out.Series, seriesSnapshotJSON{
+			Measurement: series.Key.Measurement,
+			Tags:        series.Key.Tags.All(),
+			Fields:      fields,
+		})
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *Snapshot) UnmarshalJSON(data []byte) error {
+	var in snapshotJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	rv := Snapshot{Time: in.Time, Series: make([]SeriesSnapshot, 0, len(in.Series))}
+	if in.Since != nil {
+		rv.Since = *in.Since
+	}
+	for _, series := range in.Series {
+		key := NewSeriesKey(series.Measurement)
+		key.Tags = key.Tags.SetAll(series.Tags)
+		fields := make([]FieldSnapshot, 0, len(series.Fields))
+		for _, field := range series.Fields {
+			kind, err := parseStatKind(field.Kind)
+			if err != nil {
+				return err
+			}
+			fields = append(fields, FieldSnapshot{
+				Field: field.Field,
+				Value: float64(field.Value),
+				Info: StatInfo{
+					Kind:     kind,
+					Unit:     field.Unit,
+					Help:     field.Help,
+					Quantile: field.Quantile,
+				},
+			})
+		}
+		sort.SliceStable(fields, func(i, j int) bool { return fields[i].Field < fields[j].Field })
+		rv.Series = append(rv.Series, newSeriesSnapshot(key, fields))
+	}
+	sort.SliceStable(rv.Series, func(i, j int) bool {
+		return rv.Series[i].keyString() < rv.Series[j].keyString()
+	})
+	*s = rv
+	return nil
+}
@@ -0,0 +1,214 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monkit
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func lookupValue(t *testing.T, s *Snapshot, key SeriesKey, field string) float64 {
+	t.Helper()
+	f, ok := s.Lookup(key, field)
+	if !ok {
+		t.Fatalf("missing %s in snapshot", key.WithField(field))
+	}
+	return f.Value
+}
+
+func TestSnapshot(t *testing.T) {
+	r := NewRegistry()
+	mon := r.ScopeNamed("test")
+	mon.IntVal("size").Observe(3)
+	mon.Counter("open").Inc(2)
+	mon.Meter("hits").Mark(4)
+
+	snap := r.Snapshot()
+	size := NewSeriesKey("size").WithTag("scope", "test")
+	if got := lookupValue(t, snap, size, "sum"); got != 3 {
+		t.Fatalf("got sum %v, expected 3", got)
+	}
+	if f, _ := snap.Lookup(size, "r50"); f.Info.Kind != StatKindQuantile {
+		t.Fatalf("got kind %v for r50", f.Info.Kind)
+	}
+
+	// later changes don't affect the snapshot, but show in a diff.
+	mon.Meter("hits").Mark(6)
+	mon.IntVal("size").Observe(5)
+	next := r.Snapshot()
+	hits := NewSeriesKey("hits").WithTag("scope", "test")
+	if got := lookupValue(t, snap, hits, "total"); got != 4 {
+		t.Fatalf("got total %v in the first snapshot, expected 4", got)
+	}
+	diff := next.Diff(snap)
+	if got := lookupValue(t, diff, hits, "total"); got != 6 {
+		t.Fatalf("got total %v in the diff, expected 6", got)
+	}
+	if got := lookupValue(t, diff, size, "max"); got != 5 {
+		t.Fatalf("got max %v in the diff, expected the current 5", got)
+	}
+	if !diff.Since.Equal(snap.Time) {
+		t.Fatalf("got since %v, expected %v", diff.Since, snap.Time)
+	}
+
+	// snapshots re-emit exactly what they contain.
+	var count int
+	snap.Stats(func(key SeriesKey, field string, val float64) { count++ })
+	var expected int
+	r.Stats(func(key SeriesKey, field string, val float64) { expected++ })
+	if count != expected {
+		t.Fatalf("got %d values, expected %d", count, expected)
+	}
+}
+
+func TestSnapshotJSON(t *testing.T) {
+	r := NewRegistry()
+	mon := r.ScopeNamed("test")
+	mon.Counter("empty") // NaN high and low
+	mon.DurationVal("latency").Describe("request latency", "")
+
+	snap := r.Snapshot()
+	data, err := json.Marshal(snap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Snapshot
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	empty := NewSeriesKey("empty").WithTag("scope", "test")
+	if got := lookupValue(t, &decoded, empty, "high"); !math.IsNaN(got) {
+		t.Fatalf("got high %v, expected NaN", got)
+	}
+	latency := NewSeriesKey("latency").WithTag("scope", "test")
+	f, _ := decoded.Lookup(latency, "count")
+	if f.Info.Kind != StatKindCounter || f.Info.Help == "" {
+		t.Fatalf("got info %+v", f.Info)
+	}
+	if !decoded.Time.Equal(snap.Time) || len(decoded.Series) != len(snap.Series) {
+		t.Fatalf("got %d series at %v, expected %d at %v",
+			len(decoded.Series), decoded.Time, len(snap.Series), snap.Time)
+	}
+}
+
+func TestViews(t *testing.T) {
+	r := NewRegistry()
+	mon := r.ScopeNamed("test")
+	val := mon.IntVal("size")
+	val.Observe(100)
+
+	first := r.NewView()
+	defer first.Close()
+	val.Observe(1)
+	second := r.NewView()
+	defer second.Close()
+	val.Observe(2)
+	mon.Meter("hits").Mark(1)
+
+	size := NewSeriesKey("size").WithTag("scope", "test")
+	hits := NewSeriesKey("hits").WithTag("scope", "test")
+
+	snap := first.Snapshot()
+	if count, max := lookupValue(t, snap, size, "count"), lookupValue(t, snap, size, "max"); count != 2 || max != 2 {
+		t.Fatalf("first view got count %v and max %v, expected 2 and 2", count, max)
+	}
+	if got := lookupValue(t, snap, hits, "total"); got != 1 {
+		t.Fatalf("first view got total %v, expected 1", got)
+	}
+
+	// reading the first view does not reset the second one.
+	val.Observe(3)
+	snap = second.Snapshot()
+	if count, sum := lookupValue(t, snap, size, "count"), lookupValue(t, snap, size, "sum"); count != 2 || sum != 5 {
+		t.Fatalf("second view got count %v and sum %v, expected 2 and 5", count, sum)
+	}
+
+	// without new values, only the count is left.
+	snap = second.Snapshot()
+	if count := lookupValue(t, snap, size, "count"); count != 0 {
+		t.Fatalf("second view got count %v, expected 0", count)
+	}
+	if _, ok := snap.Lookup(size, "max"); ok {
+		t.Fatal("expected no max without values")
+	}
+
+	snap = first.Snapshot()
+	if count := lookupValue(t, snap, size, "count"); count != 1 {
+		t.Fatalf("first view got count %v, expected 1", count)
+	}
+
+	// the distribution itself is unaffected.
+	if got := val.Quantile(1); got != 100 {
+		t.Fatalf("got max %v, expected 100", got)
+	}
+}
+
+func TestViewFuncs(t *testing.T) {
+	r := NewRegistry()
+	f := r.ScopeNamed("test").FuncNamed("work")
+	f.Observe()(nil)
+
+	view := r.NewView()
+	defer view.Close()
+	for i := 0; i < 3; i++ {
+		f.Observe()(nil)
+	}
+
+	snap := view.Snapshot()
+	times := NewSeriesKey("function_times").WithTags(
+		NewSeriesTag("kind", "success"), NewSeriesTag("name", "work"), NewSeriesTag("scope", "test"))
+	if got := lookupValue(t, snap, times, "count"); got != 3 {
+		t.Fatalf("got count %v, expected 3", got)
+	}
+	function := NewSeriesKey("function").WithTags(NewSeriesTag("name", "work"), NewSeriesTag("scope", "test"))
+	if got := lookupValue(t, snap, function, "successes"); got != 3 {
+		t.Fatalf("got successes %v, expected 3", got)
+	}
+}
+
+func TestViewCumulativeDelta(t *testing.T) {
+	base := NewRegistry()
+	r := base.WithTransformers(NewCumulativeDeltaTransformer(base, CumulativeDeltaOptions{}))
+	mon := r.ScopeNamed("test")
+	val := mon.IntVal("size")
+	counter := mon.Counter("items")
+
+	view := r.NewView()
+	defer view.Close()
+	size := NewSeriesKey("size").WithTag("scope", "test")
+	items := NewSeriesKey("items").WithTag("scope", "test")
+
+	// the windowed count is not turned into a delta of the cumulative one.
+	val.Observe(1)
+	val.Observe(2)
+	counter.Inc(5)
+	snap := view.Snapshot()
+	if count := lookupValue(t, snap, size, "count"); count != 2 {
+		t.Fatalf("got count %v, expected 2", count)
+	}
+
+	// and it did not count as a collection of the cumulative count.
+	val.Observe(3)
+	counter.Inc(3)
+	snap = view.Snapshot()
+	if count, sum := lookupValue(t, snap, size, "count"), lookupValue(t, snap, size, "sum"); count != 1 || sum != 3 {
+		t.Fatalf("got count %v and sum %v, expected 1 and 3", count, sum)
+	}
+	if got := lookupValue(t, snap, items, "value"); got != 8 {
+		t.Fatalf("got items %v, expected 8", got)
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monkit
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// maxStripes bounds the number of stripes a contended value is spread over.
+const maxStripes = 64
+
+// stripeCount is the number of stripes used by contended values: the number
+// of Ps rounded up to a power of two.
+var stripeCount = func() int {
+	n := 1
+	for n < runtime.GOMAXPROCS(0) && n < maxStripes {
+		n *= 2
+	}
+	return n
+}()
+
+type stripeHint struct {
+	idx uint32
+}
+
+var (
+	stripeNext  uint32
+	stripeHints = sync.Pool{New: func() interface{} {
+		return &stripeHint{idx: atomic.AddUint32(&stripeNext, 1)}
+	}}
+)
+
+// stripe returns a stripe index below n, which must be a power of two. Since
+// sync.Pool keeps per-P caches, goroutines running on the same P mostly get
+// the same index, and goroutines on different Ps mostly get different ones.
+func stripe(n int) int {
+	hint := stripeHints.Get().(*stripeHint)
+	idx := int(hint.idx) & (n - 1)
+	stripeHints.Put(hint)
+	return idx
+}
+
+// paddedInt64 is an int64 alone in its cache line.
+type paddedInt64 struct {
+	v int64
+	_ [56]byte
+}
+
+// adder is an int64 sum for concurrent updates. It starts out as a single
+// value, and once updates collide, spreads them over stripes that are summed
+// when read. The zero value is ready to use.
+type adder struct {
+	// sync/atomic things
+	base    int64
+	stripes atomic.Pointer[[]paddedInt64]
+}
+
+// Add adds n to the sum.
+func (a *adder) Add(n int64) {
+	stripes := a.stripes.Load()
+	if stripes == nil {
+		base := atomic.LoadInt64(&a.base)
+		if atomic.CompareAndSwapInt64(&a.base, base, base+n) {
+			return
+		}
+		stripes = a.grow()
+	}
+	atomic.AddInt64(&(*stripes)[stripe(len(*stripes))].v, n)
+}
+
+func (a *adder) grow() *[]paddedInt64 {
+	stripes := make([]paddedInt64, stripeCount)
+	if a.stripes.CompareAndSwap(nil, &stripes) {
+		return &stripes
+	}
+	return a.stripes.Load()
+}
+
+// Sum returns the current sum. It is not a snapshot if there are concurrent
+// updates.
+func (a *adder) Sum() int64 {
+	sum := atomic.LoadInt64(&a.base)
+	if stripes := a.stripes.Load(); stripes != nil {
+		for i := range *stripes {
+			sum += atomic.LoadInt64(&(*stripes)[i].v)
+		}
+	}
+	return sum
+}
+
+// Drain resets the sum to zero and returns what it was. Concurrent updates
+// are either included or kept for the next Drain.
+func (a *adder) Drain() int64 {
+	sum := atomic.SwapInt64(&a.base, 0)
+	if stripes := a.stripes.Load(); stripes != nil {
+		for i := range *stripes {
+			sum += atomic.SwapInt64(&(*stripes)[i].v, 0)
+		}
+	}
+	return sum
+}
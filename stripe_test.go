@@ -0,0 +1,225 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monkit
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAdder(t *testing.T) {
+	var a adder
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				a.Add(2)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if sum := a.Sum(); sum != 16000 {
+		t.Fatalf("got sum %d, expected 16000", sum)
+	}
+	if sum := a.Drain(); sum != 16000 {
+		t.Fatalf("got drained %d, expected 16000", sum)
+	}
+	if sum := a.Sum(); sum != 0 {
+		t.Fatalf("got sum %d after drain, expected 0", sum)
+	}
+}
+
+func TestCounterConcurrent(t *testing.T) {
+	c := NewCounter(NewSeriesKey("counter"))
+	if low, high, ok := c.bounds(); ok {
+		t.Fatalf("got bounds %d and %d for an empty counter", low, high)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				c.Inc(1)
+			}
+			for j := 0; j < 1000; j++ {
+				c.Dec(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	low, high, _ := c.bounds()
+	if c.Current() != 0 || low < 0 || high < 1000 || high > 8000 {
+		t.Fatalf("got current %d, low %d, high %d", c.Current(), low, high)
+	}
+	if c.activity() != 16000 {
+		t.Fatalf("got %d updates, expected 16000", c.activity())
+	}
+}
+
+func TestFuncStatsShards(t *testing.T) {
+	f := NewFuncStats(NewSeriesKey("function"))
+	f.UseHistogram([]float64{1})
+	shards := f.growShards()
+
+	// record into the FuncStats and all of its shards, where the FuncStats
+	// has the most recent call.
+	errBad := errors.New("bad")
+	now := time.Now()
+	f.end(nil, false, time.Second, now, nil)
+	for i := range *shards {
+		shard := &(*shards)[i]
+		earlier := now.Add(-time.Minute)
+		shard.record(nil, false, 2*time.Second, earlier, nil)
+		shard.record(errBad, false, time.Second, earlier, nil)
+		shard.record(nil, true, time.Second, earlier, nil)
+		shard.used = true
+	}
+
+	n := int64(len(*shards))
+	if f.Success() != 1+n || f.Panics() != n || f.Errors()[getErrorName(errBad)] != n {
+		t.Fatalf("got %d successes, %d panics and errors %v with %d shards",
+			f.Success(), f.Panics(), f.Errors(), n)
+	}
+	successes := f.SuccessTimes()
+	if successes.Low != time.Second || successes.High != 2*time.Second ||
+		successes.Sum != time.Duration(1+2*n)*time.Second || successes.Recent != time.Second {
+		t.Fatalf("got success times %+v", successes)
+	}
+	if _, counts := f.successHist.buckets(); counts[0] != 1 || counts[1] != 1+n {
+		t.Fatalf("got success histogram counts %v", counts)
+	}
+
+	// the shards were emptied by collecting them.
+	for i := range *shards {
+		if shard := &(*shards)[i]; shard.used || shard.successTimes.Count != 0 {
+			t.Fatalf("shard %d was not emptied", i)
+		}
+	}
+}
+
+func TestFuncStatsConcurrent(t *testing.T) {
+	f := NewFuncStats(NewSeriesKey("function"))
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				f.Observe()(nil)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if f.Success() != 8000 || f.SuccessTimes().Count != 8000 {
+		t.Fatalf("got %d successes, expected 8000", f.Success())
+	}
+}
+
+func TestMergeReservoirs(t *testing.T) {
+	var a, b [ReservoirSize]float32
+	for i := range a {
+		a[i], b[i] = 1, 2
+	}
+	rng := newXORShift128()
+
+	// a stands for many more values than b, so most samples stay.
+	mergeReservoirs(&a, 1000000, &b, ReservoirSize, &rng)
+	var twos int
+	for _, sample := range a {
+		if sample == 2 {
+			twos++
+		}
+	}
+	if twos == 0 || twos > ReservoirSize/2 {
+		t.Fatalf("got %d samples from b out of %d", twos, ReservoirSize)
+	}
+
+	// small reservoirs are appended.
+	var c, d [ReservoirSize]float32
+	c[0], d[0], d[1] = 1, 2, 3
+	mergeReservoirs(&c, 1, &d, 2, &rng)
+	if c[0] != 1 || c[1] != 2 || c[2] != 3 {
+		t.Fatalf("got %v, expected 1, 2 and 3 first", c[:3])
+	}
+}
+
+// The parallel benchmarks show how the hot paths scale with the number of
+// CPUs, compared to a value behind a single mutex, when run like:
+//
+//	go test -run - -bench Parallel -cpu 1,2,4,8
+
+func BenchmarkMutexParallel(b *testing.B) {
+	var mtx sync.Mutex
+	var val int64
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			mtx.Lock()
+			val++
+			mtx.Unlock()
+		}
+	})
+}
+
+func BenchmarkMeterMarkParallel(b *testing.B) {
+	m := NewMeter(NewSeriesKey("meter"))
+	defer m.ticker.unregister(m)
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			m.Mark(1)
+		}
+	})
+}
+
+func BenchmarkCounterIncParallel(b *testing.B) {
+	c := NewCounter(NewSeriesKey("counter"))
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			c.Inc(1)
+		}
+	})
+}
+
+func BenchmarkFuncStatsObserveParallel(b *testing.B) {
+	f := NewFuncStats(NewSeriesKey("function"))
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			f.Observe()(nil)
+		}
+	})
+}
+
+func BenchmarkTaskParallel(b *testing.B) {
+	mon := Package()
+	b.RunParallel(func(pb *testing.PB) {
+		ctx := context.Background()
+		for pb.Next() {
+			var err error
+			func() {
+				ctx := ctx
+				defer mon.Task()(&ctx)(&err)
+			}()
+		}
+	})
+}
@@ -37,8 +37,10 @@ import (
 //
 // Timers implement StatSource.
 type Timer struct {
-	mtx   sync.Mutex
-	times *DurationDist
+	mtx    sync.Mutex
+	times  *DurationDist
+	window *DurationDist // see drainWindow
+	description
 }
 
 // NewTimer constructs a new Timer.
@@ -72,6 +74,9 @@ func (r *RunningTimer) Stop() time.Duration {
 	r.t.mtx.Lock()
 	if !r.stopped {
 		r.t.times.Insert(elapsed)
+		if r.t.window != nil {
+			r.t.window.Insert(elapsed)
+		}
 		r.stopped = true
 	}
 	r.t.mtx.Unlock()
@@ -86,6 +91,25 @@ func (t *Timer) Values() *DurationDist {
 	return rv
 }
 
+// UseSketch makes the Timer estimate quantiles with a Sketch of the given
+// relative accuracy instead of the sample reservoir.
+func (t *Timer) UseSketch(relativeAccuracy float64) {
+	t.mtx.Lock()
+	t.times.UseSketch(relativeAccuracy)
+	t.mtx.Unlock()
+}
+
+func (t *Timer) drainWindow() []windowDist {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	window := t.window
+	if window == nil {
+		window = t.times.Copy()
+	}
+	t.window = t.times.emptyCopy()
+	return []windowDist{window}
+}
+
 // Stats implements the StatSource interface
 func (t *Timer) Stats(cb func(key SeriesKey, field string, val float64)) {
 	t.mtx.Lock()
@@ -94,3 +118,9 @@ func (t *Timer) Stats(cb func(key SeriesKey, field string, val float64)) {
 
 	times.Stats(cb)
 }
+
+// DescribeStats implements the StatDescriber interface.
+func (t *Timer) DescribeStats(cb func(key SeriesKey, field string, info StatInfo)) {
+	help, _ := t.get()
+	describeDist(t.times.key, help, UnitSeconds, StatKindCounter, cb)
+}
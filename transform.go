@@ -32,20 +32,63 @@ func (f CallbackTransformerFunc) Transform(cb func(SeriesKey, string, float64))
 	return f(cb)
 }
 
+// FlushingCallbackTransformer is a CallbackTransformer that holds back values
+// until all values of a collection have been passed to it, for example to
+// aggregate them. Registry.Stats and TransformStatSource call the flush
+// function once the collection is done. Its plain Transform method can only
+// pass values on right away.
+type FlushingCallbackTransformer interface {
+	CallbackTransformer
+
+	// TransformFlushing is like Transform, but also returns a function that
+	// passes the held back values to cb. It must be called once after all
+	// values have been passed to the returned callback.
+	TransformFlushing(cb func(SeriesKey, string, float64)) (
+		transformed func(SeriesKey, string, float64), flush func())
+}
+
+// ApplyTransformers applies the transformers to cb the same way as
+// Registry.WithTransformers does, where the last transformer gets the values
+// first. The returned flush function must be called once all values have been
+// passed to the returned callback, for the sake of any
+// FlushingCallbackTransformers.
+func ApplyTransformers(cb func(SeriesKey, string, float64),
+	transformers ...CallbackTransformer) (
+	transformed func(SeriesKey, string, float64), flush func()) {
+	var flushes []func()
+	for _, t := range transformers {
+		if ft, ok := t.(FlushingCallbackTransformer); ok {
+			var flush func()
+			cb, flush = ft.TransformFlushing(cb)
+			flushes = append(flushes, flush)
+		} else {
+			cb = t.Transform(cb)
+		}
+	}
+	return cb, func() {
+		// the last transformer passes its values to the ones before it.
+		for i := len(flushes) - 1; i >= 0; i-- {
+			flushes[i]()
+		}
+	}
+}
+
 // TransformStatSource will make sure that a StatSource has the provided
 // CallbackTransformers applied to callbacks given to the StatSource.
 func TransformStatSource(s StatSource, transformers ...CallbackTransformer) StatSource {
 	return StatSourceFunc(func(cb func(key SeriesKey, field string, val float64)) {
-		for _, t := range transformers {
-			cb = t.Transform(cb)
-		}
+		cb, flush := ApplyTransformers(cb, transformers...)
 		s.Stats(cb)
+		flush()
 	})
 }
 
 // DeltaTransformer calculates deltas from any total fields. It keeps internal
 // state to keep track of the previous totals, so care should be taken to use
 // a different DeltaTransformer per output.
+//
+// Deprecated: DeltaTransformer only considers fields named "total", and
+// emits negative deltas after resets. Use CumulativeDeltaTransformer instead.
 type DeltaTransformer struct {
 	mtx        sync.Mutex
 	lastTotals map[string]float64
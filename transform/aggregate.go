@@ -0,0 +1,126 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/spacemonkeygo/monkit/v3"
+)
+
+// AggregateOp combines the values of series that Aggregate merges.
+type AggregateOp int
+
+const (
+	// Sum adds the values up.
+	Sum AggregateOp = iota
+	// Min keeps the lowest value.
+	Min
+	// Max keeps the highest value.
+	Max
+)
+
+// String returns the name of the op as used in configurations.
+func (op AggregateOp) String() string {
+	switch op {
+	case Sum:
+		return "sum"
+	case Min:
+		return "min"
+	case Max:
+		return "max"
+	default:
+		return fmt.Sprintf("AggregateOp(%d)", int(op))
+	}
+}
+
+// ParseAggregateOp returns the AggregateOp with the given name.
+func ParseAggregateOp(name string) (AggregateOp, error) {
+	for _, op := range []AggregateOp{Sum, Min, Max} {
+		if op.String() == name {
+			return op, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown aggregate op %q", name)
+}
+
+func (op AggregateOp) combine(a, b float64) float64 {
+	switch op {
+	case Min:
+		return math.Min(a, b)
+	case Max:
+		return math.Max(a, b)
+	default:
+		return a + b
+	}
+}
+
+// Aggregate returns a CallbackTransformer that removes the tags with the given
+// keys and combines the values of the series that end up with the same key
+// and field with op. For example, with the "instance" tag, the sum of a field
+// of all instances is emitted instead of the field of every instance.
+//
+// The combined values are only known once all values of a collection have
+// been seen, so they are emitted by the flush function of TransformFlushing,
+// which Registry.Stats and monkit.TransformStatSource call. Its plain
+// Transform method drops the tags without combining anything.
+func Aggregate(op AggregateOp, tags ...string) monkit.FlushingCallbackTransformer {
+	return &aggregator{op: op, drop: tagKeys(tags)}
+}
+
+type aggregator struct {
+	op   AggregateOp
+	drop map[string]bool
+}
+
+type aggregate struct {
+	key   monkit.SeriesKey
+	field string
+	val   float64
+}
+
+// Transform implements monkit.CallbackTransformer.
+func (a *aggregator) Transform(
+	cb func(monkit.SeriesKey, string, float64)) func(monkit.SeriesKey, string, float64) {
+	return func(key monkit.SeriesKey, field string, val float64) {
+		cb(withoutTags(key, a.drop), field, val)
+	}
+}
+
+// TransformFlushing implements monkit.FlushingCallbackTransformer.
+func (a *aggregator) TransformFlushing(cb func(monkit.SeriesKey, string, float64)) (
+	transformed func(monkit.SeriesKey, string, float64), flush func()) {
+	var order []*aggregate
+	byName := map[string]*aggregate{}
+
+	transformed = func(key monkit.SeriesKey, field string, val float64) {
+		key = withoutTags(key, a.drop)
+		name := key.WithField(field)
+		if agg, ok := byName[name]; ok {
+			agg.val = a.op.combine(agg.val, val)
+			return
+		}
+		agg := &aggregate{key: key, field: field, val: val}
+		byName[name] = agg
+		order = append(order, agg)
+	}
+	flush = func() {
+		for _, agg := range order {
+			cb(agg.key, agg.field, agg.val)
+		}
+	}
+	return transformed, flush
+}
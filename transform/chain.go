@@ -0,0 +1,48 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"github.com/spacemonkeygo/monkit/v3"
+)
+
+// Chain returns a CallbackTransformer that applies the transformers in order:
+// values are passed to the first one, what it emits to the second one, and
+// so on.
+func Chain(transformers ...monkit.CallbackTransformer) monkit.FlushingCallbackTransformer {
+	// monkit.ApplyTransformers passes values to the last transformer first.
+	reversed := make([]monkit.CallbackTransformer, 0, len(transformers))
+	for i := len(transformers) - 1; i >= 0; i-- {
+		reversed = append(reversed, transformers[i])
+	}
+	return chain(reversed)
+}
+
+type chain []monkit.CallbackTransformer
+
+// Transform implements monkit.CallbackTransformer.
+func (c chain) Transform(
+	cb func(monkit.SeriesKey, string, float64)) func(monkit.SeriesKey, string, float64) {
+	for _, t := range c {
+		cb = t.Transform(cb)
+	}
+	return cb
+}
+
+// TransformFlushing implements monkit.FlushingCallbackTransformer.
+func (c chain) TransformFlushing(cb func(monkit.SeriesKey, string, float64)) (
+	transformed func(monkit.SeriesKey, string, float64), flush func()) {
+	return monkit.ApplyTransformers(cb, c...)
+}
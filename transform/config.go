@@ -0,0 +1,176 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/spacemonkeygo/monkit/v3"
+)
+
+type matchConfig struct {
+	Measurement string `json:"measurement"`
+	Field       string `json:"field"`
+}
+
+type renameConfig struct {
+	Tag  string `json:"tag"`
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type aggregateConfig struct {
+	Op   string   `json:"op"`
+	Tags []string `json:"tags"`
+}
+
+// Load reads the configuration of a Chain from the file at path. See the
+// package documentation for the format.
+func Load(path string) (monkit.FlushingCallbackTransformer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	t, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return t, nil
+}
+
+// Parse parses the configuration of a Chain. See the package documentation
+// for the format.
+func Parse(data []byte) (monkit.FlushingCallbackTransformer, error) {
+	var steps []map[string]json.RawMessage
+	if err := json.Unmarshal(data, &steps); err != nil {
+		return nil, fmt.Errorf("transform: %w", err)
+	}
+	transformers := make([]monkit.CallbackTransformer, 0, len(steps))
+	for i, step := range steps {
+		if len(step) != 1 {
+			return nil, fmt.Errorf("transform: step %d: expected a single transformer, got %d", i, len(step))
+		}
+		for name, raw := range step {
+			t, err := parseStep(name, raw)
+			if err != nil {
+				return nil, fmt.Errorf("transform: step %d: %s: %w", i, name, err)
+			}
+			transformers = append(transformers, t)
+		}
+	}
+	return Chain(transformers...), nil
+}
+
+func parseStep(name string, raw json.RawMessage) (monkit.CallbackTransformer, error) {
+	switch name {
+	case "allow", "deny":
+		var configs []matchConfig
+		if err := json.Unmarshal(raw, &configs); err != nil {
+			return nil, err
+		}
+		matches := make([]Match, 0, len(configs))
+		for _, config := range configs {
+			m, err := config.parse()
+			if err != nil {
+				return nil, err
+			}
+			matches = append(matches, m)
+		}
+		if name == "allow" {
+			return Allow(matches...), nil
+		}
+		return Deny(matches...), nil
+
+	case "rename_measurement", "rename_field", "rewrite_tag":
+		var config renameConfig
+		if err := json.Unmarshal(raw, &config); err != nil {
+			return nil, err
+		}
+		pattern, err := parsePattern(config.From)
+		if err != nil {
+			return nil, err
+		}
+		switch name {
+		case "rename_measurement":
+			return RenameMeasurement(pattern, config.To), nil
+		case "rename_field":
+			return RenameField(pattern, config.To), nil
+		}
+		if config.Tag == "" {
+			return nil, fmt.Errorf("missing tag")
+		}
+		return RewriteTag(config.Tag, pattern, config.To), nil
+
+	case "drop_tags":
+		var tags []string
+		if err := json.Unmarshal(raw, &tags); err != nil {
+			return nil, err
+		}
+		return DropTags(tags...), nil
+
+	case "rename_tag":
+		var config renameConfig
+		if err := json.Unmarshal(raw, &config); err != nil {
+			return nil, err
+		}
+		if config.From == "" || config.To == "" {
+			return nil, fmt.Errorf("missing from or to")
+		}
+		return RenameTag(config.From, config.To), nil
+
+	case "aggregate":
+		var config aggregateConfig
+		if err := json.Unmarshal(raw, &config); err != nil {
+			return nil, err
+		}
+		op, err := ParseAggregateOp(config.Op)
+		if err != nil {
+			return nil, err
+		}
+		return Aggregate(op, config.Tags...), nil
+
+	default:
+		return nil, fmt.Errorf("unknown transformer")
+	}
+}
+
+func (config matchConfig) parse() (m Match, err error) {
+	if config.Measurement != "" {
+		if m.Measurement, err = parsePattern(config.Measurement); err != nil {
+			return m, err
+		}
+	}
+	if config.Field != "" {
+		if m.Field, err = parsePattern(config.Field); err != nil {
+			return m, err
+		}
+	}
+	return m, nil
+}
+
+// parsePattern parses a glob, or a regular expression enclosed in slashes.
+func parsePattern(pattern string) (*regexp.Regexp, error) {
+	if len(pattern) >= 2 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
+		return regexp.Compile(pattern[1 : len(pattern)-1])
+	}
+	if pattern == "" {
+		return nil, fmt.Errorf("empty pattern")
+	}
+	return Glob(pattern), nil
+}
@@ -0,0 +1,56 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package transform provides monkit.CallbackTransformers for the usual needs of
+exporters: filtering values by measurement and field names, renaming
+measurements and fields, dropping and rewriting tags, and aggregating series
+across the values of tags.
+
+The transformers can be applied to a Registry directly:
+
+	reg := monkit.Default.WithTransformers(transform.Chain(
+	  transform.Deny(transform.Match{Measurement: transform.Glob("debug_*")}),
+	  transform.Aggregate(transform.Sum, "instance")))
+
+Note that Chain applies its transformers in the order they are given, while
+Registry.WithTransformers passes values to the last transformer first.
+
+# Configuration
+
+Chains can also be built from a JSON configuration with Parse or Load. The
+configuration is a list of steps, applied in order, where every step is an
+object with a single key naming the transformer:
+
+	[
+	  {"deny": [{"measurement": "debug_*"}, {"field": "/^p[0-9]+$/"}]},
+	  {"allow": [{"measurement": "function"}, {"measurement": "http_*"}]},
+	  {"rename_measurement": {"from": "/^http_(.*)$/", "to": "web_$1"}},
+	  {"rename_field": {"from": "total", "to": "count"}},
+	  {"drop_tags": ["pid"]},
+	  {"rename_tag": {"from": "svc", "to": "service"}},
+	  {"rewrite_tag": {"tag": "host", "from": "/[.].*$/", "to": ""}},
+	  {"aggregate": {"op": "sum", "tags": ["instance"]}}
+	]
+
+Patterns are globs, where * matches any number of characters and ? matches a
+single one, unless they are enclosed in slashes, in which case they are
+regular expressions. A glob has to match the whole name, while a regular
+expression only has to match part of it. The "to" values of renames are
+expanded like in regexp.Regexp.ReplaceAllString, so they can refer to groups
+of regular expressions.
+
+The aggregate ops are "sum", "min" and "max".
+*/
+package transform // import "github.com/spacemonkeygo/monkit/v3/transform"
@@ -0,0 +1,94 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/spacemonkeygo/monkit/v3"
+)
+
+// Pattern matches names, such as measurement and field names.
+// *regexp.Regexp implements it.
+type Pattern interface {
+	MatchString(s string) bool
+}
+
+// Glob compiles a glob pattern that has to match whole names, where * matches
+// any number of characters and ? matches a single one. All other characters
+// match themselves. The returned Regexp can be used as a Pattern or to rename
+// with.
+func Glob(pattern string) *regexp.Regexp {
+	var expr strings.Builder
+	expr.WriteString("^")
+	for _, r := range pattern {
+		switch r {
+		case '*':
+			expr.WriteString(".*")
+		case '?':
+			expr.WriteString(".")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	expr.WriteString("$")
+	return regexp.MustCompile(expr.String())
+}
+
+// Match selects values by their measurement and field names. A nil Pattern
+// matches every name, so the zero Match matches all values.
+type Match struct {
+	Measurement Pattern
+	Field       Pattern
+}
+
+// Matches returns whether both patterns match.
+func (m Match) Matches(key monkit.SeriesKey, field string) bool {
+	return (m.Measurement == nil || m.Measurement.MatchString(key.Measurement)) &&
+		(m.Field == nil || m.Field.MatchString(field))
+}
+
+func matchesAny(matches []Match, key monkit.SeriesKey, field string) bool {
+	for _, m := range matches {
+		if m.Matches(key, field) {
+			return true
+		}
+	}
+	return false
+}
+
+// Allow returns a CallbackTransformer that only passes on the values that any
+// of matches matches.
+func Allow(matches ...Match) monkit.CallbackTransformer {
+	return filter(matches, true)
+}
+
+// Deny returns a CallbackTransformer that drops the values that any of
+// matches matches.
+func Deny(matches ...Match) monkit.CallbackTransformer {
+	return filter(matches, false)
+}
+
+func filter(matches []Match, allow bool) monkit.CallbackTransformer {
+	return monkit.CallbackTransformerFunc(func(
+		cb func(monkit.SeriesKey, string, float64)) func(monkit.SeriesKey, string, float64) {
+		return func(key monkit.SeriesKey, field string, val float64) {
+			if matchesAny(matches, key, field) == allow {
+				cb(key, field, val)
+			}
+		}
+	})
+}
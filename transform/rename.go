@@ -0,0 +1,49 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"regexp"
+
+	"github.com/spacemonkeygo/monkit/v3"
+)
+
+// RenameMeasurement returns a CallbackTransformer that renames the
+// measurements pattern matches, by replacing the matches with replacement as
+// in regexp.Regexp.ReplaceAllString. Use Glob to rename whole names.
+func RenameMeasurement(pattern *regexp.Regexp, replacement string) monkit.CallbackTransformer {
+	return monkit.CallbackTransformerFunc(func(
+		cb func(monkit.SeriesKey, string, float64)) func(monkit.SeriesKey, string, float64) {
+		return func(key monkit.SeriesKey, field string, val float64) {
+			if pattern.MatchString(key.Measurement) {
+				key.Measurement = pattern.ReplaceAllString(key.Measurement, replacement)
+			}
+			cb(key, field, val)
+		}
+	})
+}
+
+// RenameField is like RenameMeasurement, but renames fields.
+func RenameField(pattern *regexp.Regexp, replacement string) monkit.CallbackTransformer {
+	return monkit.CallbackTransformerFunc(func(
+		cb func(monkit.SeriesKey, string, float64)) func(monkit.SeriesKey, string, float64) {
+		return func(key monkit.SeriesKey, field string, val float64) {
+			if pattern.MatchString(field) {
+				field = pattern.ReplaceAllString(field, replacement)
+			}
+			cb(key, field, val)
+		}
+	})
+}
@@ -0,0 +1,96 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"regexp"
+
+	"github.com/spacemonkeygo/monkit/v3"
+)
+
+// DropTags returns a CallbackTransformer that removes the tags with the given
+// keys from all series. Series that only differed by those tags end up with
+// the same key, see Aggregate to combine them.
+func DropTags(keys ...string) monkit.CallbackTransformer {
+	drop := tagKeys(keys)
+	return monkit.CallbackTransformerFunc(func(
+		cb func(monkit.SeriesKey, string, float64)) func(monkit.SeriesKey, string, float64) {
+		return func(key monkit.SeriesKey, field string, val float64) {
+			cb(withoutTags(key, drop), field, val)
+		}
+	})
+}
+
+// RenameTag returns a CallbackTransformer that renames the tag key from to
+// to. If a series has both tags, the renamed one replaces the other.
+func RenameTag(from, to string) monkit.CallbackTransformer {
+	drop := tagKeys([]string{from})
+	return monkit.CallbackTransformerFunc(func(
+		cb func(monkit.SeriesKey, string, float64)) func(monkit.SeriesKey, string, float64) {
+		return func(key monkit.SeriesKey, field string, val float64) {
+			if tagVal, ok := key.Tags.All()[from]; ok {
+				key = withoutTags(key, drop).WithTag(to, tagVal)
+			}
+			cb(key, field, val)
+		}
+	})
+}
+
+// RewriteTag returns a CallbackTransformer that rewrites the values of the
+// tag with the given key, by replacing the matches of pattern with
+// replacement as in regexp.Regexp.ReplaceAllString.
+func RewriteTag(tag string, pattern *regexp.Regexp, replacement string) monkit.CallbackTransformer {
+	return monkit.CallbackTransformerFunc(func(
+		cb func(monkit.SeriesKey, string, float64)) func(monkit.SeriesKey, string, float64) {
+		return func(key monkit.SeriesKey, field string, val float64) {
+			if tagVal, ok := key.Tags.All()[tag]; ok && pattern.MatchString(tagVal) {
+				key = key.WithTag(tag, pattern.ReplaceAllString(tagVal, replacement))
+			}
+			cb(key, field, val)
+		}
+	})
+}
+
+func tagKeys(keys []string) map[string]bool {
+	set := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		set[key] = true
+	}
+	return set
+}
+
+// withoutTags returns key without the tags in drop.
+func withoutTags(key monkit.SeriesKey, drop map[string]bool) monkit.SeriesKey {
+	all := key.Tags.All()
+	found := false
+	for tag := range all {
+		if drop[tag] {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return key
+	}
+	kept := make(map[string]string, len(all))
+	for tag, val := range all {
+		if !drop[tag] {
+			kept[tag] = val
+		}
+	}
+	rv := monkit.NewSeriesKey(key.Measurement)
+	rv.Tags = rv.Tags.SetAll(kept)
+	return rv
+}
@@ -0,0 +1,157 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/spacemonkeygo/monkit/v3"
+)
+
+func testSource() monkit.StatSource {
+	return monkit.StatSourceFunc(func(cb func(monkit.SeriesKey, string, float64)) {
+		cb(monkit.NewSeriesKey("http_requests").WithTag("instance", "a").WithTag("host", "web1.example.com"), "total", 3)
+		cb(monkit.NewSeriesKey("http_requests").WithTag("instance", "b").WithTag("host", "web1.example.com"), "total", 4)
+		cb(monkit.NewSeriesKey("http_requests").WithTag("instance", "c").WithTag("host", "web2.example.com"), "total", 5)
+		cb(monkit.NewSeriesKey("debug_queue").WithTag("instance", "a"), "len", 7)
+		cb(monkit.NewSeriesKey("function").WithTag("name", "work"), "p50", 0.1)
+		cb(monkit.NewSeriesKey("function").WithTag("name", "work"), "successes", 10)
+	})
+}
+
+func collect(t monkit.CallbackTransformer) map[string]float64 {
+	values := map[string]float64{}
+	monkit.TransformStatSource(testSource(), t).Stats(
+		func(key monkit.SeriesKey, field string, val float64) {
+			values[key.WithField(field)] = val
+		})
+	return values
+}
+
+func assertValues(t *testing.T, got, expected map[string]float64) {
+	t.Helper()
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("got %v, expected %v", got, expected)
+	}
+}
+
+func TestGlob(t *testing.T) {
+	for _, test := range []struct {
+		pattern, name string
+		match         bool
+	}{
+		{"http_*", "http_requests", true},
+		{"http_*", "xhttp_requests", false},
+		{"p?", "p50", false},
+		{"p??", "p50", true},
+		{"a.b", "a.b", true},
+		{"a.b", "axb", false},
+	} {
+		if got := Glob(test.pattern).MatchString(test.name); got != test.match {
+			t.Errorf("Glob(%q).MatchString(%q) = %v", test.pattern, test.name, got)
+		}
+	}
+}
+
+func TestFilter(t *testing.T) {
+	assertValues(t, collect(Allow(Match{Measurement: Glob("function")})), map[string]float64{
+		"function,name=work p50":       0.1,
+		"function,name=work successes": 10,
+	})
+	assertValues(t, collect(Deny(
+		Match{Measurement: Glob("*_*")},
+		Match{Field: regexp.MustCompile(`^p\d+$`)})), map[string]float64{
+		"function,name=work successes": 10,
+	})
+}
+
+func TestRenameAndTags(t *testing.T) {
+	values := collect(Chain(
+		Allow(Match{Measurement: Glob("http_*")}),
+		RenameMeasurement(regexp.MustCompile(`^http_(.*)$`), "web_$1"),
+		RenameField(Glob("total"), "count"),
+		RenameTag("instance", "node"),
+		RewriteTag("host", regexp.MustCompile(`[.].*$`), ""),
+		DropTags("node")))
+	assertValues(t, values, map[string]float64{
+		"web_requests,host=web1 count": 4,
+		"web_requests,host=web2 count": 5,
+	})
+}
+
+func TestAggregate(t *testing.T) {
+	for _, test := range []struct {
+		op       AggregateOp
+		expected map[string]float64
+	}{
+		{Sum, map[string]float64{"http_requests total": 12, "debug_queue len": 7}},
+		{Min, map[string]float64{"http_requests total": 3, "debug_queue len": 7}},
+		{Max, map[string]float64{"http_requests total": 5, "debug_queue len": 7}},
+	} {
+		values := collect(Chain(
+			Deny(Match{Measurement: Glob("function")}),
+			Aggregate(test.op, "instance", "host")))
+		assertValues(t, values, test.expected)
+	}
+
+	// aggregates pass through the transformers after them.
+	r := monkit.NewRegistry()
+	r.ScopeNamed("test").Chain(testSource())
+	values := map[string]float64{}
+	r.WithTransformers(Chain(
+		Aggregate(Sum, "instance"),
+		Allow(Match{Measurement: Glob("http_*")}),
+		DropTags("scope"),
+		Aggregate(Sum, "host"),
+	)).Stats(func(key monkit.SeriesKey, field string, val float64) {
+		values[key.WithField(field)] = val
+	})
+	assertValues(t, values, map[string]float64{"http_requests total": 12})
+}
+
+func TestParse(t *testing.T) {
+	chain, err := Parse([]byte(`[
+		{"deny": [{"measurement": "debug_*"}, {"field": "/^p[0-9]+$/"}]},
+		{"allow": [{"measurement": "function"}, {"measurement": "http_*"}]},
+		{"rename_measurement": {"from": "/^http_(.*)$/", "to": "web_$1"}},
+		{"rename_field": {"from": "total", "to": "count"}},
+		{"rename_tag": {"from": "name", "to": "func"}},
+		{"rewrite_tag": {"tag": "host", "from": "/[.].*$/", "to": ""}},
+		{"drop_tags": ["host"]},
+		{"aggregate": {"op": "max", "tags": ["instance"]}}
+	]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertValues(t, collect(chain), map[string]float64{
+		"web_requests count":           5,
+		"function,func=work successes": 10,
+	})
+
+	for _, config := range []string{
+		`{}`,
+		`[{"allow": [], "deny": []}]`,
+		`[{"filter": []}]`,
+		`[{"deny": [{"field": "/(/"}]}]`,
+		`[{"aggregate": {"op": "avg", "tags": ["instance"]}}]`,
+		`[{"rewrite_tag": {"from": "a", "to": "b"}}]`,
+	} {
+		if _, err := Parse([]byte(config)); err == nil {
+			t.Errorf("expected an error for %s", config)
+		}
+	}
+}
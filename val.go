@@ -15,6 +15,8 @@
 package monkit
 
 import (
+	"context"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -31,8 +33,10 @@ import (
 //	  ...
 //	}
 type IntVal struct {
-	mtx  sync.Mutex
-	dist IntDist
+	mtx    sync.Mutex
+	dist   IntDist
+	window *IntDist // see drainWindow
+	description
 }
 
 // NewIntVal creates an IntVal
@@ -46,6 +50,21 @@ func NewIntVal(key SeriesKey) (v *IntVal) {
 func (v *IntVal) Observe(val int64) {
 	v.mtx.Lock()
 	v.dist.Insert(val)
+	if v.window != nil {
+		v.window.Insert(val)
+	}
+	v.mtx.Unlock()
+}
+
+// ObserveCtx is like Observe, but if ctx has a Span, also keeps the value as
+// an exemplar of the Span. See ExemplarSource.
+func (v *IntVal) ObserveCtx(ctx context.Context, val int64) {
+	span := spanFromContext(ctx)
+	v.mtx.Lock()
+	v.dist.insertSpan(val, span)
+	if v.window != nil {
+		v.window.Insert(val)
+	}
 	v.mtx.Unlock()
 }
 
@@ -58,6 +77,34 @@ func (v *IntVal) Stats(cb func(key SeriesKey, field string, val float64)) {
 	vd.Stats(cb)
 }
 
+// DescribeStats implements the StatDescriber interface.
+func (v *IntVal) DescribeStats(cb func(key SeriesKey, field string, info StatInfo)) {
+	help, unit := v.get()
+	describeDist(v.dist.key, help, unit, v.dist.sumKind(), cb)
+}
+
+func (v *IntVal) drainWindow() []windowDist {
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+	window := v.window
+	if window == nil {
+		window = v.dist.Copy()
+	}
+	v.window = v.dist.emptyCopy()
+	return []windowDist{window}
+}
+
+// Exemplars implements the ExemplarSource interface.
+func (v *IntVal) Exemplars(cb func(key SeriesKey, field string, exemplars []Exemplar)) {
+	v.mtx.Lock()
+	exemplars := v.dist.exemplars.list()
+	v.mtx.Unlock()
+
+	if len(exemplars) > 0 {
+		cb(v.dist.key, "count", exemplars)
+	}
+}
+
 // Quantile returns an estimate of the requested quantile of observed values.
 // 0 <= quantile <= 1
 func (v *IntVal) Quantile(quantile float64) (rv int64) {
@@ -67,6 +114,38 @@ func (v *IntVal) Quantile(quantile float64) (rv int64) {
 	return rv
 }
 
+// UseSketch makes the IntVal estimate quantiles with a Sketch of the given
+// relative accuracy instead of the sample reservoir. Previously observed values
+// are not part of the Sketch.
+func (v *IntVal) UseSketch(relativeAccuracy float64) {
+	v.mtx.Lock()
+	v.dist.UseSketch(relativeAccuracy)
+	v.mtx.Unlock()
+}
+
+func (v *IntVal) ensureSketch(relativeAccuracy float64) {
+	v.mtx.Lock()
+	if v.dist.sketch == nil {
+		v.dist.UseSketch(relativeAccuracy)
+	}
+	v.mtx.Unlock()
+}
+
+// Sketch returns a copy of the Sketch of observed values, or nil if the
+// IntVal does not use one. See UseSketch.
+func (v *IntVal) Sketch() *Sketch {
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+	return v.dist.Sketch()
+}
+
+// Sketches implements the SketchSource interface.
+func (v *IntVal) Sketches(cb func(key SeriesKey, sketch *Sketch)) {
+	if sketch := v.Sketch(); sketch != nil {
+		cb(v.dist.key, sketch)
+	}
+}
+
 // FloatVal is a convenience wrapper around an FloatDist. Constructed using
 // NewFloatVal, though its expected usage is like:
 //
@@ -78,8 +157,10 @@ func (v *IntVal) Quantile(quantile float64) (rv int64) {
 //	  ...
 //	}
 type FloatVal struct {
-	mtx  sync.Mutex
-	dist FloatDist
+	mtx    sync.Mutex
+	dist   FloatDist
+	window *FloatDist // see drainWindow
+	description
 }
 
 // NewFloatVal creates a FloatVal
@@ -93,6 +174,21 @@ func NewFloatVal(key SeriesKey) (v *FloatVal) {
 func (v *FloatVal) Observe(val float64) {
 	v.mtx.Lock()
 	v.dist.Insert(val)
+	if v.window != nil {
+		v.window.Insert(val)
+	}
+	v.mtx.Unlock()
+}
+
+// ObserveCtx is like Observe, but if ctx has a Span, also keeps the value as
+// an exemplar of the Span. See ExemplarSource.
+func (v *FloatVal) ObserveCtx(ctx context.Context, val float64) {
+	span := spanFromContext(ctx)
+	v.mtx.Lock()
+	v.dist.insertSpan(val, span)
+	if v.window != nil {
+		v.window.Insert(val)
+	}
 	v.mtx.Unlock()
 }
 
@@ -105,6 +201,34 @@ func (v *FloatVal) Stats(cb func(key SeriesKey, field string, val float64)) {
 	vd.Stats(cb)
 }
 
+// DescribeStats implements the StatDescriber interface.
+func (v *FloatVal) DescribeStats(cb func(key SeriesKey, field string, info StatInfo)) {
+	help, unit := v.get()
+	describeDist(v.dist.key, help, unit, v.dist.sumKind(), cb)
+}
+
+func (v *FloatVal) drainWindow() []windowDist {
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+	window := v.window
+	if window == nil {
+		window = v.dist.Copy()
+	}
+	v.window = v.dist.emptyCopy()
+	return []windowDist{window}
+}
+
+// Exemplars implements the ExemplarSource interface.
+func (v *FloatVal) Exemplars(cb func(key SeriesKey, field string, exemplars []Exemplar)) {
+	v.mtx.Lock()
+	exemplars := v.dist.exemplars.list()
+	v.mtx.Unlock()
+
+	if len(exemplars) > 0 {
+		cb(v.dist.key, "count", exemplars)
+	}
+}
+
 // Quantile returns an estimate of the requested quantile of observed values.
 // 0 <= quantile <= 1
 func (v *FloatVal) Quantile(quantile float64) (rv float64) {
@@ -114,6 +238,38 @@ func (v *FloatVal) Quantile(quantile float64) (rv float64) {
 	return rv
 }
 
+// UseSketch makes the FloatVal estimate quantiles with a Sketch of the given
+// relative accuracy instead of the sample reservoir. Previously observed values
+// are not part of the Sketch.
+func (v *FloatVal) UseSketch(relativeAccuracy float64) {
+	v.mtx.Lock()
+	v.dist.UseSketch(relativeAccuracy)
+	v.mtx.Unlock()
+}
+
+func (v *FloatVal) ensureSketch(relativeAccuracy float64) {
+	v.mtx.Lock()
+	if v.dist.sketch == nil {
+		v.dist.UseSketch(relativeAccuracy)
+	}
+	v.mtx.Unlock()
+}
+
+// Sketch returns a copy of the Sketch of observed values, or nil if the
+// FloatVal does not use one. See UseSketch.
+func (v *FloatVal) Sketch() *Sketch {
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+	return v.dist.Sketch()
+}
+
+// Sketches implements the SketchSource interface.
+func (v *FloatVal) Sketches(cb func(key SeriesKey, sketch *Sketch)) {
+	if sketch := v.Sketch(); sketch != nil {
+		cb(v.dist.key, sketch)
+	}
+}
+
 // BoolVal keeps statistics about boolean values. It keeps the number of trues,
 // number of falses, and the disposition (number of trues minus number of
 // falses). Constructed using NewBoolVal, though its expected usage is like:
@@ -130,6 +286,7 @@ type BoolVal struct {
 	falses int64
 	recent int32
 	key    SeriesKey
+	description
 }
 
 // NewBoolVal creates a BoolVal
@@ -159,6 +316,19 @@ func (v *BoolVal) Stats(cb func(key SeriesKey, field string, val float64)) {
 	cb(v.key, "true", float64(trues))
 }
 
+// DescribeStats implements the StatDescriber interface.
+func (v *BoolVal) DescribeStats(cb func(key SeriesKey, field string, info StatInfo)) {
+	help, _ := v.get()
+	cb(v.key, "disposition", describeField(StatKindGauge, help, "",
+		"number of true values minus number of false values"))
+	cb(v.key, "false", describeField(StatKindCounter, help, "",
+		"number of false values"))
+	cb(v.key, "recent", describeField(StatKindGauge, help, "",
+		"1 if the most recent value was true, 0 otherwise"))
+	cb(v.key, "true", describeField(StatKindCounter, help, "",
+		"number of true values"))
+}
+
 // StructVal keeps track of a structure of data. Constructed using
 // NewStructVal, though its expected usage is like:
 //
@@ -211,8 +381,11 @@ func (v *StructVal) Stats(cb func(key SeriesKey, field string, val float64)) {
 //	  ...
 //	}
 type DurationVal struct {
-	mtx  sync.Mutex
-	dist DurationDist
+	mtx    sync.Mutex
+	dist   DurationDist
+	window *DurationDist // see drainWindow
+	events *eventHook    // nil unless created through a Scope
+	description
 }
 
 // NewDurationVal creates an DurationVal
@@ -226,7 +399,24 @@ func NewDurationVal(key SeriesKey) (v *DurationVal) {
 func (v *DurationVal) Observe(val time.Duration) {
 	v.mtx.Lock()
 	v.dist.Insert(val)
+	if v.window != nil {
+		v.window.Insert(val)
+	}
 	v.mtx.Unlock()
+	v.events.duration(v.dist.key, val)
+}
+
+// ObserveCtx is like Observe, but if ctx has a Span, also keeps the value as
+// an exemplar of the Span. See ExemplarSource.
+func (v *DurationVal) ObserveCtx(ctx context.Context, val time.Duration) {
+	span := spanFromContext(ctx)
+	v.mtx.Lock()
+	v.dist.insertSpan(val, span)
+	if v.window != nil {
+		v.window.Insert(val)
+	}
+	v.mtx.Unlock()
+	v.events.duration(v.dist.key, val)
 }
 
 // Stats implements the StatSource interface.
@@ -238,6 +428,34 @@ func (v *DurationVal) Stats(cb func(key SeriesKey, field string, val float64)) {
 	vd.Stats(cb)
 }
 
+// DescribeStats implements the StatDescriber interface.
+func (v *DurationVal) DescribeStats(cb func(key SeriesKey, field string, info StatInfo)) {
+	help, _ := v.get()
+	describeDist(v.dist.key, help, UnitSeconds, v.dist.sumKind(), cb)
+}
+
+func (v *DurationVal) drainWindow() []windowDist {
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+	window := v.window
+	if window == nil {
+		window = v.dist.Copy()
+	}
+	v.window = v.dist.emptyCopy()
+	return []windowDist{window}
+}
+
+// Exemplars implements the ExemplarSource interface.
+func (v *DurationVal) Exemplars(cb func(key SeriesKey, field string, exemplars []Exemplar)) {
+	v.mtx.Lock()
+	exemplars := v.dist.exemplars.list()
+	v.mtx.Unlock()
+
+	if len(exemplars) > 0 {
+		cb(v.dist.key, "count", exemplars)
+	}
+}
+
 // Quantile returns an estimate of the requested quantile of observed values.
 // 0 <= quantile <= 1
 func (v *DurationVal) Quantile(quantile float64) (rv time.Duration) {
@@ -247,8 +465,37 @@ func (v *DurationVal) Quantile(quantile float64) (rv time.Duration) {
 	return rv
 }
 
-// Aggregate can implement additional aggregation for collected values.
-type Aggregate func() (observe func(val float64), stat func() (field string, val float64))
+// UseSketch makes the DurationVal estimate quantiles with a Sketch of the given
+// relative accuracy instead of the sample reservoir. Previously observed values
+// are not part of the Sketch.
+func (v *DurationVal) UseSketch(relativeAccuracy float64) {
+	v.mtx.Lock()
+	v.dist.UseSketch(relativeAccuracy)
+	v.mtx.Unlock()
+}
+
+func (v *DurationVal) ensureSketch(relativeAccuracy float64) {
+	v.mtx.Lock()
+	if v.dist.sketch == nil {
+		v.dist.UseSketch(relativeAccuracy)
+	}
+	v.mtx.Unlock()
+}
+
+// Sketch returns a copy of the Sketch of observed values, or nil if the
+// DurationVal does not use one. See UseSketch.
+func (v *DurationVal) Sketch() *Sketch {
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+	return v.dist.Sketch()
+}
+
+// Sketches implements the SketchSource interface.
+func (v *DurationVal) Sketches(cb func(key SeriesKey, sketch *Sketch)) {
+	if sketch := v.Sketch(); sketch != nil {
+		cb(v.dist.key, sketch)
+	}
+}
 
 // RawVal is a simple wrapper around a float64 value without any aggregation
 // (histogram, sum, etc). Constructed using NewRawVal, though its expected usage is like:
@@ -260,21 +507,30 @@ type Aggregate func() (observe func(val float64), stat func() (field string, val
 //	  mon.RawVal("value").Observe(val)
 //	  ...
 //	}
+//
+// Aggregations such as Sum, Mean or Max can be added to report more than the
+// most recent value, and UseBuckets adds histogram buckets.
 type RawVal struct {
 	mtx       sync.Mutex
 	value     float64
 	key       SeriesKey
 	stats     []func() (field string, val float64)
 	observers []func(val float64)
+	fields    map[string]bool // the fields of stats
+	buckets   *bucketCounts
+	updates   int64
+	description
 }
 
 // NewRawVal creates a RawVal
 func NewRawVal(key SeriesKey, aggregations ...Aggregate) *RawVal {
-	val := &RawVal{key: key}
+	val := &RawVal{key: key, fields: map[string]bool{}}
 	for _, agg := range aggregations {
 		observe, stat := agg()
+		field, _ := stat()
 		val.stats = append(val.stats, stat)
 		val.observers = append(val.observers, observe)
+		val.fields[field] = true
 	}
 	return val
 }
@@ -283,20 +539,89 @@ func NewRawVal(key SeriesKey, aggregations ...Aggregate) *RawVal {
 func (v *RawVal) Observe(val float64) {
 	v.mtx.Lock()
 	v.value = val
+	v.updates++
 	for _, o := range v.observers {
 		o(val)
 	}
+	if v.buckets != nil {
+		v.buckets.insert(val)
+	}
 	v.mtx.Unlock()
 }
 
+// UseBuckets makes the RawVal also count the observed values in buckets with
+// the given upper bounds, which are reported like the fields of a Histogram,
+// including its sum and count fields unless aggregations such as Sum and
+// Count already report them. Previously observed values are not counted.
+// Calling UseBuckets again with the same bounds has no effect.
+func (v *RawVal) UseBuckets(buckets []float64) {
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+	if v.buckets != nil && sameBounds(v.buckets.bounds, buckets) {
+		return
+	}
+	v.buckets = &bucketCounts{}
+	initBucketCounts(v.buckets, v.key, buckets)
+}
+
 // Stats implements the StatSource interface.
 func (v *RawVal) Stats(cb func(key SeriesKey, field string, val float64)) {
+	type stat struct {
+		field string
+		val   float64
+	}
+
 	v.mtx.Lock()
-	cb(v.key, "recent", v.value)
-	v.mtx.Unlock()
+	value := v.value
+	stats := make([]stat, 0, len(v.stats))
 	for _, s := range v.stats {
-		field, value := s()
-		cb(v.key, field, value)
+		field, val := s()
+		stats = append(stats, stat{field: field, val: val})
+	}
+	var buckets *bucketCounts
+	if v.buckets != nil {
+		buckets = v.buckets.copy()
+	}
+	v.mtx.Unlock()
+
+	cb(v.key, "recent", value)
+	for _, s := range stats {
+		cb(v.key, s.field, s.val)
+	}
+	if buckets != nil {
+		buckets.stats(func(key SeriesKey, field string, val float64) {
+			if field == "bucket" || !v.fields[field] {
+				cb(key, field, val)
+			}
+		})
+	}
+}
+
+// DescribeStats implements the StatDescriber interface. Fields of
+// aggregations are described if they are named like the fields of the
+// built-in aggregations, such as Count and Sum, as nothing is known about
+// other aggregations.
+func (v *RawVal) DescribeStats(cb func(key SeriesKey, field string, info StatInfo)) {
+	help, unit := v.get()
+	cb(v.key, "recent", describeField(StatKindGauge, help, unit,
+		"most recently observed value"))
+	fields := make([]string, 0, len(v.fields))
+	for field := range v.fields {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		describeAggregate(v.key, field, help, unit, cb)
+	}
+	v.mtx.Lock()
+	hasBuckets := v.buckets != nil
+	v.mtx.Unlock()
+	if hasBuckets {
+		describeHistogram(v.key, help, unit, func(key SeriesKey, field string, info StatInfo) {
+			if field == "bucket" || !v.fields[field] {
+				cb(key, field, info)
+			}
+		})
 	}
 }
 
@@ -307,23 +632,3 @@ func (v *RawVal) Get() float64 {
 	v.mtx.Unlock()
 	return value
 }
-
-// Count is a value aggregator that counts the number of times the value is measured.
-func Count() (observe func(val float64), stat func() (field string, val float64)) {
-	var counter int
-	return func(val float64) {
-			counter++
-		}, func() (field string, val float64) {
-			return "count", float64(counter)
-		}
-}
-
-// Sum is a value aggregator that summarizes the values measured.
-func Sum() (observe func(val float64), stat func() (field string, val float64)) {
-	var sum int
-	return func(val float64) {
-			sum += int(val)
-		}, func() (field string, val float64) {
-			return "sum", float64(sum)
-		}
-}
@@ -0,0 +1,186 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monkit
+
+// windowDist is a distribution of the values observed by a windowSource
+// within an interval.
+type windowDist interface {
+	Stats(cb func(key SeriesKey, field string, val float64))
+	// add merges other, which has the same type, into the distribution.
+	add(other windowDist)
+	clone() windowDist
+}
+
+// windowSource is implemented by the StatSources with distributions. Once
+// drainWindow has been called, they keep a second copy of their
+// distributions with the values observed since the last call to drainWindow.
+type windowSource interface {
+	// drainWindow returns the distributions of values observed since the
+	// last call, or since the source was created for the first call.
+	drainWindow() []windowDist
+}
+
+// View gives one consumer of a Registry, such as an exporter, Snapshots of
+// what changed since its previous Snapshot, independent of other Views and
+// readers of the Registry. Create one with Registry.NewView.
+type View struct {
+	r *Registry
+
+	// protected by r.viewMtx
+	prev    *Snapshot
+	pending map[windowSource]*pendingWindow
+}
+
+type pendingWindow struct {
+	scope *Scope
+	dists []windowDist
+}
+
+// NewView creates a View. Its first Snapshot contains what changed since the
+// View was created. Views must be closed once they are no longer used.
+func (r *Registry) NewView() *View {
+	r.viewMtx.Lock()
+	defer r.viewMtx.Unlock()
+
+	// drain the windows, so that the new View starts out empty.
+	r.distributeWindows()
+
+	v := &View{
+		r:       r,
+		prev:    r.Snapshot(),
+		pending: map[windowSource]*pendingWindow{},
+	}
+	if r.views == nil {
+		r.views = map[*View]struct{}{}
+	}
+	r.views[v] = struct{}{}
+	return v
+}
+
+// Snapshot returns the statistics of the Registry like Registry.Snapshot,
+// but as of what changed since the previous call, or since the View was
+// created: counter and histogram fields are differences as described in
+// Snapshot.Diff, and the fields of distributions, such as those of Funcs,
+// Timers, IntVals, FloatVals and DurationVals, are computed from only the
+// values observed in between. Snapshot.Since is the time of the previous
+// Snapshot.
+//
+// Every call is one collection for the Registry's CallbackTransformers. The
+// fields computed from the values observed in between are not passed to them
+// again, but take the place of what they made of the cumulative fields, and
+// are left out where they dropped or combined those.
+func (v *View) Snapshot() *Snapshot {
+	r := v.r
+	r.viewMtx.Lock()
+	defer r.viewMtx.Unlock()
+
+	// outputs keeps track of where the transformers put every value.
+	outputs := map[string][]seriesField{}
+	var input string
+	b := newSnapshotBuilder(r)
+	cb, flush := r.transform(func(key SeriesKey, field string, val float64) {
+		if input != "" {
+			outputs[input] = append(outputs[input], seriesField{key: key, field: field})
+		}
+		b.add(key, field, val)
+	})
+	r.Scopes(func(s *Scope) {
+		s.Stats(func(key SeriesKey, field string, val float64) {
+			input = key.WithField(field)
+			cb(key, field, val)
+			input = ""
+		})
+	})
+	flush()
+	r.distributeWindows()
+	current := b.done()
+
+	windows := newSnapshotBuilder(r)
+	windows.catalog = b.catalog
+	for _, pending := range v.pending {
+		tagger := pending.scope.tagger()
+		for _, dist := range pending.dists {
+			dist.Stats(func(key SeriesKey, field string, val float64) {
+				key = tagger.tag(key)
+				if len(r.transformers) == 0 {
+					windows.add(key, field, val)
+					return
+				}
+				for _, out := range outputs[key.WithField(field)] {
+					windows.add(out.key, out.field, val)
+				}
+			})
+		}
+	}
+	windowed := map[string]*SeriesSnapshot{}
+	for _, series := range windows.done().Series {
+		series := series
+		windowed[series.keyString()] = &series
+	}
+
+	snapshot := current.diff(v.prev, windowed)
+	v.prev = current
+	v.pending = map[windowSource]*pendingWindow{}
+	return snapshot
+}
+
+type seriesField struct {
+	key   SeriesKey
+	field string
+}
+
+// Close stops keeping track of changes for the View. Snapshot must not be
+// called after Close.
+func (v *View) Close() {
+	v.r.viewMtx.Lock()
+	defer v.r.viewMtx.Unlock()
+	delete(v.r.views, v)
+	v.pending = nil
+}
+
+// distributeWindows drains the windows of all sources in the Registry and
+// adds them to the pending windows of all Views. r.viewMtx must be held.
+func (r *Registry) distributeWindows() {
+	r.Scopes(func(s *Scope) {
+		for _, named := range s.allNamedSources() {
+			source, ok := named.source.(windowSource)
+			if !ok {
+				continue
+			}
+			dists := source.drainWindow()
+			for v := range r.views {
+				pending, ok := v.pending[source]
+				if !ok {
+					pending = &pendingWindow{scope: s}
+					for _, dist := range dists {
+						pending.dists = append(pending.dists, dist.clone())
+					}
+					v.pending[source] = pending
+					continue
+				}
+				for i, dist := range dists {
+					pending.dists[i].add(dist)
+				}
+			}
+		}
+	})
+}
+
+// transform applies the Registry's CallbackTransformers to cb. flush must be
+// called once all values have been passed to cb.
+func (r *Registry) transform(cb func(key SeriesKey, field string, val float64)) (
+	transformed func(key SeriesKey, field string, val float64), flush func()) {
+	return ApplyTransformers(cb, r.transformers...)
+}
@@ -0,0 +1,154 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zipkin
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/spacemonkeygo/monkit/v3"
+	"github.com/spacemonkeygo/monkit/v3/collect"
+	"github.com/spacemonkeygo/monkit/v3/internal/spanqueue"
+)
+
+// DefaultEndpoint is the span endpoint of a local Zipkin collector.
+const DefaultEndpoint = "http://localhost:9411/api/v2/spans"
+
+// Options configure a Collector. The zero value of every option picks a
+// default.
+type Options struct {
+	// Endpoint is the URL spans are posted to. Defaults to DefaultEndpoint.
+	Endpoint string
+	// Header is added to the requests, such as for authentication.
+	Header http.Header
+	// Client is used for the requests, http.DefaultClient if nil.
+	Client *http.Client
+	// Sample decides which traces are collected. Defaults to all of them.
+	Sample func(t *monkit.Trace) bool
+	// QueueSize is the most finished spans kept waiting to be posted.
+	// Defaults to 2048.
+	QueueSize int
+	// DropOldest makes a full queue drop its oldest spans for new ones,
+	// rather than dropping the new ones.
+	DropOldest bool
+	// BatchSize is the most spans posted in one request. Defaults to 512.
+	BatchSize int
+	// BatchTimeout is the longest a span waits for its batch to fill up
+	// before it is posted. Defaults to 5 seconds.
+	BatchTimeout time.Duration
+}
+
+func (opts Options) withDefaults() Options {
+	if opts.Endpoint == "" {
+		opts.Endpoint = DefaultEndpoint
+	}
+	return opts
+}
+
+// scopeName is the Scope the statistics of Collectors are reported in.
+const scopeName = "github.com/spacemonkeygo/monkit/v3/zipkin"
+
+// Collector collects finished Spans and posts them in batches to a Zipkin
+// collector, encoded like Encode does. Create one with NewCollector, and
+// call Run to collect the traces of its Registry.
+//
+// Spans of posts that fail are put back at the front of the queue and posted
+// again with the next batch, so they are only lost once the queue is full and
+// Options.DropOldest picks them to be dropped, or when Run returns. Spans of a
+// post that failed after it was received are sent twice.
+type Collector struct {
+	opts  Options
+	queue *spanqueue.Queue
+}
+
+// NewCollector creates a Collector for the traces of r.
+func NewCollector(r *monkit.Registry, opts Options) *Collector {
+	c := &Collector{opts: opts.withDefaults()}
+	c.queue = spanqueue.New(r, r.ScopeNamed(scopeName), spanqueue.Options{
+		Sample:       opts.Sample,
+		QueueSize:    opts.QueueSize,
+		DropOldest:   opts.DropOldest,
+		BatchSize:    opts.BatchSize,
+		BatchTimeout: opts.BatchTimeout,
+	}, c.post)
+	return c
+}
+
+// Run observes the Registry's new traces, picked by Options.Sample, and posts
+// their spans as they finish, until ctx is done. It then posts the queued
+// spans one last time, and spans that finish afterwards are ignored. A
+// Collector can only be run once.
+func (c *Collector) Run(ctx context.Context) error {
+	err := c.queue.Run(ctx)
+	if errors.Is(err, spanqueue.ErrAlreadyRun) {
+		return errors.New("zipkin: collector already run")
+	}
+	return err
+}
+
+// Start implements monkit.SpanCtxObserver.
+func (c *Collector) Start(ctx context.Context, s *monkit.Span) context.Context {
+	return c.queue.Start(ctx, s)
+}
+
+// Finish implements monkit.SpanCtxObserver. It queues the Span to be posted,
+// or drops it or the oldest queued one if the queue is full.
+func (c *Collector) Finish(ctx context.Context, s *monkit.Span, err error, panicked bool, finish time.Time) {
+	c.queue.Finish(ctx, s, err, panicked, finish)
+}
+
+// Flush posts the queued spans in batches of at most BatchSize, until the
+// queue is empty or a post fails. It returns the error of the failed post,
+// whose spans are put back into the queue.
+func (c *Collector) Flush(ctx context.Context) error {
+	return c.queue.Flush(ctx)
+}
+
+func (c *Collector) post(ctx context.Context, batch []*collect.FinishedSpan) error {
+	var body bytes.Buffer
+	if err := Encode(&body, batch); err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.opts.Endpoint, &body)
+	if err != nil {
+		return err
+	}
+	for key, values := range c.opts.Header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := c.opts.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<20))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("zipkin: post %s: %s", c.opts.Endpoint, resp.Status)
+	}
+	return nil
+}
+
+var _ monkit.SpanCtxObserver = (*Collector)(nil)
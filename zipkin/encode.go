@@ -0,0 +1,105 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zipkin
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spacemonkeygo/monkit/v3/collect"
+)
+
+// Span is a span in the Zipkin v2 JSON format.
+type Span struct {
+	TraceID       string            `json:"traceId"`
+	ID            string            `json:"id"`
+	ParentID      string            `json:"parentId,omitempty"`
+	Name          string            `json:"name"`
+	Timestamp     int64             `json:"timestamp"`
+	Duration      int64             `json:"duration"`
+	LocalEndpoint Endpoint          `json:"localEndpoint"`
+	Tags          map[string]string `json:"tags,omitempty"`
+}
+
+// Endpoint is a Zipkin endpoint.
+type Endpoint struct {
+	ServiceName string `json:"serviceName,omitempty"`
+}
+
+// Convert turns a FinishedSpan into a Zipkin span. The span is named after
+// the full name of its Func, and its service is the name of the Func's Scope.
+// Its constant tags (see Span.Tags) and annotations become tags, along with
+// its args as the tag "monkit.args" and "monkit.orphaned" if it is orphaned.
+// Spans that fail have an "error" tag with the error message, or "panic" if
+// they panicked. The ids are 64 bit, so trace ids are 16 hex digits long.
+func Convert(s *collect.FinishedSpan) Span {
+	span := s.Span
+	out := Span{
+		TraceID:       hexID(span.Trace().Id()),
+		ID:            hexID(span.Id()),
+		Name:          span.Func().FullName(),
+		Timestamp:     span.Start().UnixNano() / int64(time.Microsecond),
+		Duration:      int64(s.Finish.Sub(span.Start()) / time.Microsecond),
+		LocalEndpoint: Endpoint{ServiceName: span.Func().Scope().Name()},
+	}
+	if parent, ok := span.ParentId(); ok {
+		out.ParentID = hexID(parent)
+	}
+	// Zipkin drops durations below a microsecond.
+	if out.Duration < 1 {
+		out.Duration = 1
+	}
+
+	tags := map[string]string{}
+	for _, tag := range span.Tags() {
+		tags[tag.Key] = tag.Val
+	}
+	if args := span.Args(); len(args) > 0 {
+		tags["monkit.args"] = "[" + strings.Join(args, ", ") + "]"
+	}
+	for _, annotation := range span.Annotations() {
+		tags[annotation.Name] = annotation.Value
+	}
+	if span.Orphaned() {
+		tags["monkit.orphaned"] = "true"
+	}
+	switch {
+	case s.Panicked:
+		tags["error"] = "panic"
+	case s.Err != nil:
+		tags["error"] = s.Err.Error()
+	}
+	if len(tags) > 0 {
+		out.Tags = tags
+	}
+	return out
+}
+
+// Encode writes spans to w as a JSON list of Zipkin spans, as accepted by
+// the /api/v2/spans endpoint of a Zipkin collector. See Convert.
+func Encode(w io.Writer, spans []*collect.FinishedSpan) error {
+	out := make([]Span, 0, len(spans))
+	for _, s := range spans {
+		out = append(out, Convert(s))
+	}
+	return json.NewEncoder(w).Encode(out)
+}
+
+func hexID(id int64) string {
+	return fmt.Sprintf("%016x", uint64(id))
+}
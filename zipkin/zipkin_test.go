@@ -0,0 +1,117 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zipkin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/spacemonkeygo/monkit/v3"
+)
+
+func work(mon *monkit.Scope) {
+	ctx := context.Background()
+	var err error
+	defer mon.TaskNamed("parent")(&ctx, "arg", 5)(&err)
+	monkit.SpanFromCtx(ctx).Annotate("user", "alice")
+
+	func() {
+		ctx, err := ctx, error(nil)
+		defer mon.TaskNamed("child")(&ctx)(&err)
+		err = errors.New("child failed")
+	}()
+
+	func() {
+		ctx := ctx
+		defer func() { _ = recover() }()
+		defer mon.TaskNamed("boom")(&ctx)(nil)
+		panic("boom")
+	}()
+}
+
+func TestCollector(t *testing.T) {
+	bodies := make(chan []byte, 100)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/api/v2/spans" || req.Header.Get("Content-Type") != "application/json" ||
+			req.Header.Get("Authorization") != "secret" {
+			t.Errorf("got %s %v", req.URL.Path, req.Header)
+		}
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			t.Error(err)
+		}
+		bodies <- body
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	r := monkit.NewRegistry()
+	r.SetTags(monkit.NewSeriesTag("host", "a"), monkit.NewSeriesTag("user", "nobody"))
+	mon := r.ScopeNamed("test")
+	c := NewCollector(r, Options{
+		Endpoint:     server.URL + "/api/v2/spans",
+		Header:       http.Header{"Authorization": {"secret"}},
+		BatchTimeout: 50 * time.Millisecond,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error, 1)
+	go func() { errs <- c.Run(ctx) }()
+
+	// traces are only observed once Run registered the collector.
+	spans := map[string]Span{}
+	for deadline := time.Now().Add(5 * time.Second); len(spans) < 3; {
+		work(mon)
+		select {
+		case body := <-bodies:
+			var batch []Span
+			if err := json.Unmarshal(body, &batch); err != nil {
+				t.Fatal(err)
+			}
+			for _, s := range batch {
+				spans[s.Name] = s
+			}
+		case <-time.After(10 * time.Millisecond):
+			if time.Now().After(deadline) {
+				t.Fatal("nothing posted")
+			}
+		}
+	}
+	cancel()
+	if err := <-errs; err != nil {
+		t.Fatal(err)
+	}
+
+	parent, child, boom := spans["test.parent"], spans["test.child"], spans["test.boom"]
+	if len(parent.TraceID) != 16 || len(parent.ID) != 16 || parent.ParentID != "" ||
+		child.TraceID != parent.TraceID || child.ParentID != parent.ID ||
+		boom.ParentID != parent.ID {
+		t.Fatalf("got ids %+v %+v %+v", parent, child, boom)
+	}
+	if parent.LocalEndpoint.ServiceName != "test" || parent.Timestamp == 0 || parent.Duration < 1 ||
+		parent.Tags["monkit.args"] != `["arg", 5]` || parent.Tags["user"] != "alice" || parent.Tags["host"] != "a" ||
+		parent.Tags["error"] != "" {
+		t.Fatalf("got parent %+v", parent)
+	}
+	if child.Tags["error"] != "child failed" || boom.Tags["error"] != "panic" {
+		t.Fatalf("got child %+v and boom %+v", child, boom)
+	}
+}